@@ -0,0 +1,33 @@
+// Package api embeds the OpenAPI specification of the Rates API.
+package api
+
+import (
+	_ "embed"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed open-api.spec.yaml
+var specYAML []byte
+
+// SpecYAML returns the OpenAPI specification as YAML.
+func SpecYAML() []byte {
+	return specYAML
+}
+
+// SpecJSON returns the OpenAPI specification converted to JSON.
+func SpecJSON() ([]byte, error) {
+	var document interface{}
+	if err := yaml.Unmarshal(specYAML, &document); err != nil {
+		return nil, errors.Wrap(err, "failed to parse OpenAPI specification")
+	}
+
+	data, err := json.Marshal(document)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode OpenAPI specification")
+	}
+
+	return data, nil
+}
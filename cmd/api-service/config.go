@@ -18,8 +18,51 @@ const (
 	ServerTimeout  = 15 * time.Second
 	deletionDays   = 30
 	contextTimeout = 60 * time.Second
+	// maxTimeSeriesDays allows a year of daily rates, including leap years, per time series request.
+	maxTimeSeriesDays = 366
+	// maxBatchItems bounds the dates of a batch rate request unless configured otherwise.
+	maxBatchItems = 500
+	// csvDecimalSeparator is the decimal separator of CSV output unless configured otherwise.
+	csvDecimalSeparator = "."
+	// cacheMaxAge lets clients reuse rate responses for a minute before revalidating them.
+	cacheMaxAge = 1 * time.Minute
+	// historicalCacheMaxAge lets clients reuse responses for past days for a day.
+	historicalCacheMaxAge = 24 * time.Hour
+	// specValidationSampleRate validates one JSON response body in a hundred in log mode unless configured otherwise.
+	specValidationSampleRate = 0.01
+	// specValidationMaxBody records response bodies of up to 256 KiB for validation unless configured otherwise,
+	// which holds a full year time series of a few currencies.
+	specValidationMaxBody = 256 << 10
+	// streamHeartbeat keeps idle rate streams below the common proxy read timeout of 30 to 60 seconds.
+	streamHeartbeat = 15 * time.Second
+	// streamPollInterval is how often rate streams check for new rates unless configured otherwise.
+	streamPollInterval = 5 * time.Second
+	// grpcPort is the port of the gRPC server unless configured otherwise.
+	grpcPort = 9090
+	// webhookPollInterval is how often due webhook deliveries are attempted unless configured otherwise.
+	webhookPollInterval = 10 * time.Second
+	// webhookTimeout bounds a webhook delivery attempt unless configured otherwise.
+	webhookTimeout = 10 * time.Second
+	// webhookMaxAttempts spreads the retries of a delivery over about two hours with the default backoff.
+	webhookMaxAttempts = 8
+	// webhookInitialBackoff is the delay before the first retry of a delivery unless configured otherwise.
+	webhookInitialBackoff = 30 * time.Second
+	// webhookMaxBackoff caps the delay between two delivery attempts unless configured otherwise.
+	webhookMaxBackoff = 1 * time.Hour
+	// publicationCutoff leaves the sync about two hours after the ECB publishes the rates around 16:00 CET.
+	publicationCutoff = 16 * time.Hour
+	// healthTimeout bounds the database checks of the readiness probe unless configured otherwise.
+	healthTimeout = 2 * time.Second
+	// rateLimitPeriod is the period of the rate limit groups that do not configure one.
+	rateLimitPeriod = 1 * time.Minute
 )
 
+// anonymousPaths are the path prefixes served without an API key unless configured otherwise:
+// the probes, which orchestrators call without credentials.
+//
+//nolint:gochecknoglobals // read-only default
+var anonymousPaths = []string{"/health"}
+
 // ReadConfig reads the configuration file from the given path and returns the StartupConfig.
 func readConfig(path string) (*models.StartupConfig, error) {
 	data, err := os.ReadFile(path)
@@ -40,6 +83,21 @@ func readConfig(path string) (*models.StartupConfig, error) {
 	return &config, nil
 }
 
+// postgresParams returns the connection parameters of the configured database.
+func postgresParams(config *models.StartupConfig) models.PostgresConfigParams {
+	return models.PostgresConfigParams{
+		Host:           config.Database.Host,
+		Port:           config.Database.Port,
+		Username:       config.Database.User,
+		Password:       config.Database.Pass,
+		Database:       config.Database.Name,
+		SSLMode:        string(config.Database.SSLMode),
+		MinConnections: config.Database.MinConnections,
+		MaxConnections: config.Database.MaxConnections,
+		SchemaName:     config.Database.Schema,
+	}
+}
+
 // setDefaults sets the default values for the configuration.
 // If the configuration file does not have a value for a field, the default value is set.
 func setDefaults(config *models.StartupConfig) {
@@ -92,6 +150,71 @@ func setDefaults(config *models.StartupConfig) {
 	if config.HTTP.Port == 0 {
 		config.HTTP.Port = 8080
 	}
+	if config.API.MaxTimeSeriesDays == 0 {
+		config.API.MaxTimeSeriesDays = maxTimeSeriesDays
+	}
+	if config.API.MaxBatchItems == 0 {
+		config.API.MaxBatchItems = maxBatchItems
+	}
+	if config.API.CSVDecimalSeparator == "" {
+		config.API.CSVDecimalSeparator = csvDecimalSeparator
+	}
+	if config.API.CacheMaxAge == 0 {
+		config.API.CacheMaxAge = cacheMaxAge
+	}
+	if config.API.HistoricalCacheMaxAge == 0 {
+		config.API.HistoricalCacheMaxAge = historicalCacheMaxAge
+	}
+	if config.API.SpecValidation == "" {
+		config.API.SpecValidation = models.ValidationLog
+	}
+	if config.API.SpecValidationSampleRate == 0 {
+		config.API.SpecValidationSampleRate = specValidationSampleRate
+	}
+	if config.API.SpecValidationMaxBody == 0 {
+		config.API.SpecValidationMaxBody = specValidationMaxBody
+	}
+	if config.API.StreamHeartbeat == 0 {
+		config.API.StreamHeartbeat = streamHeartbeat
+	}
+	if config.API.StreamPollInterval == 0 {
+		config.API.StreamPollInterval = streamPollInterval
+	}
+	if config.GRPC.Port == 0 {
+		config.GRPC.Port = grpcPort
+	}
+	if config.Webhooks.PollInterval == 0 {
+		config.Webhooks.PollInterval = webhookPollInterval
+	}
+	if config.Webhooks.Timeout == 0 {
+		config.Webhooks.Timeout = webhookTimeout
+	}
+	if config.Webhooks.MaxAttempts == 0 {
+		config.Webhooks.MaxAttempts = webhookMaxAttempts
+	}
+	if config.Webhooks.InitialBackoff == 0 {
+		config.Webhooks.InitialBackoff = webhookInitialBackoff
+	}
+	if config.Webhooks.MaxBackoff == 0 {
+		config.Webhooks.MaxBackoff = webhookMaxBackoff
+	}
+	if config.Health.PublicationCutoff == 0 {
+		config.Health.PublicationCutoff = publicationCutoff
+	}
+	if config.Health.Timeout == 0 {
+		config.Health.Timeout = healthTimeout
+	}
+	if config.RateLimit.Store == "" {
+		config.RateLimit.Store = models.RateLimitStoreMemory
+	}
+	if config.Auth.Anonymous == nil {
+		config.Auth.Anonymous = anonymousPaths
+	}
+	for i := range config.RateLimit.Groups {
+		if config.RateLimit.Groups[i].Period == 0 {
+			config.RateLimit.Groups[i].Period = rateLimitPeriod
+		}
+	}
 }
 
 // ParseFlags parses command-line flags into an AppConfig struct and returns it
@@ -148,6 +148,30 @@ func TestSetDefaults(t *testing.T) {
 	assert.Equal(t, deleteInterval, config.CronJobs.Cleanup.DeletionInterval)
 	assert.Equal(t, deletionDays, config.CronJobs.Cleanup.MaxAge)
 	assert.Equal(t, 8080, config.HTTP.Port)
+	assert.Equal(t, maxTimeSeriesDays, config.API.MaxTimeSeriesDays)
+	assert.Equal(t, maxBatchItems, config.API.MaxBatchItems)
+	assert.Equal(t, csvDecimalSeparator, config.API.CSVDecimalSeparator)
+	assert.Equal(t, cacheMaxAge, config.API.CacheMaxAge)
+	assert.Equal(t, historicalCacheMaxAge, config.API.HistoricalCacheMaxAge)
+	assert.Equal(t, models.ValidationLog, config.API.SpecValidation)
+	assert.InDelta(t, specValidationSampleRate, config.API.SpecValidationSampleRate, 0)
+	assert.Equal(t, specValidationMaxBody, config.API.SpecValidationMaxBody)
+	assert.False(t, config.API.SwaggerUI)
+	assert.Equal(t, streamHeartbeat, config.API.StreamHeartbeat)
+	assert.Equal(t, streamPollInterval, config.API.StreamPollInterval)
+	assert.Equal(t, grpcPort, config.GRPC.Port)
+	assert.Equal(t, webhookPollInterval, config.Webhooks.PollInterval)
+	assert.Equal(t, webhookTimeout, config.Webhooks.Timeout)
+	assert.Equal(t, webhookMaxAttempts, config.Webhooks.MaxAttempts)
+	assert.Equal(t, webhookInitialBackoff, config.Webhooks.InitialBackoff)
+	assert.Equal(t, webhookMaxBackoff, config.Webhooks.MaxBackoff)
+	assert.Equal(t, publicationCutoff, config.Health.PublicationCutoff)
+	assert.Zero(t, config.Health.MaxLagDays)
+	assert.Equal(t, healthTimeout, config.Health.Timeout)
+	assert.False(t, config.RateLimit.Enabled)
+	assert.Equal(t, models.RateLimitStoreMemory, config.RateLimit.Store)
+	assert.False(t, config.Auth.Enabled)
+	assert.Equal(t, []string{"/health"}, config.Auth.Anonymous)
 }
 
 func TestParseFlags(t *testing.T) {
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/light-bringer/rates-exchanger-service/db"
+	"github.com/light-bringer/rates-exchanger-service/internal/apikey"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+)
+
+const (
+	// keysCommand is the subcommand managing the API keys.
+	keysCommand = "keys"
+
+	keysUsage = `usage:
+  api-service keys create [-config-file path] -name name -scopes rates:read[,admin] [-expires-in 720h]
+  api-service keys list [-config-file path]
+  api-service keys revoke [-config-file path] id`
+)
+
+// keysInvocation is a parsed invocation of the keys subcommand.
+type keysInvocation struct {
+	action     string
+	configPath string
+	name       string
+	scopes     []models.APIKeyScope
+	expiresIn  time.Duration
+	id         int64
+}
+
+// runKeys creates, lists or revokes API keys in the database of the configuration, as requested by
+// the arguments following the keys subcommand, and writes the outcome to out.
+func runKeys(args []string, out io.Writer) error {
+	invocation, err := parseKeysArgs(args)
+	if err != nil {
+		return err
+	}
+
+	config, err := readConfig(invocation.configPath)
+	if err != nil {
+		return err
+	}
+	setDefaults(config)
+
+	dbConfig := db.NewPostgresConfig(postgresParams(config))
+	if dbConfig == nil {
+		return errors.New("failed to create the database configuration")
+	}
+
+	ctx := context.Background()
+	dbConn, err := db.BuildPGXConnPool(ctx, *dbConfig)
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to the database")
+	}
+	defer dbConn.Close()
+
+	keys := apikey.NewService(apikey.NewPostgresStore(dbConn, config.Database.Schema))
+	return invocation.run(ctx, keys, out)
+}
+
+// parseKeysArgs parses the arguments following the keys subcommand.
+func parseKeysArgs(args []string) (keysInvocation, error) {
+	if len(args) == 0 {
+		return keysInvocation{}, errors.New("missing keys action\n" + keysUsage)
+	}
+
+	invocation := keysInvocation{action: args[0]}
+	flags := flag.NewFlagSet(keysCommand+" "+invocation.action, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.StringVar(&invocation.configPath, "config-file", "config.yaml", "path to the configuration file")
+
+	var scopes string
+	switch invocation.action {
+	case "create":
+		flags.StringVar(&invocation.name, "name", "", "name of the key")
+		flags.StringVar(&scopes, "scopes", string(models.ScopeRatesRead), "comma separated scopes of the key")
+		flags.DurationVar(&invocation.expiresIn, "expires-in", 0, "lifetime of the key; keys without one do not expire")
+	case "list", "revoke":
+	default:
+		return keysInvocation{}, errors.Errorf("unknown keys action %q\n%s", invocation.action, keysUsage)
+	}
+
+	if err := flags.Parse(args[1:]); err != nil {
+		return keysInvocation{}, errors.Wrapf(err, "invalid arguments\n%s", keysUsage)
+	}
+
+	switch invocation.action {
+	case "create":
+		var err error
+		if invocation.scopes, err = apikey.ParseScopes(scopes); err != nil {
+			return keysInvocation{}, err //nolint:wrapcheck // the error names the scope
+		}
+		if invocation.expiresIn < 0 {
+			return keysInvocation{}, errors.New("the lifetime of a key must be positive")
+		}
+	case "revoke":
+		if flags.NArg() != 1 {
+			return keysInvocation{}, errors.New("revoke takes the ID of the key\n" + keysUsage)
+		}
+		id, err := strconv.ParseInt(flags.Arg(0), 10, 64)
+		if err != nil || id <= 0 {
+			return keysInvocation{}, errors.Errorf("invalid key ID %q", flags.Arg(0))
+		}
+		invocation.id = id
+	}
+	if invocation.action != "revoke" && flags.NArg() > 0 {
+		return keysInvocation{}, errors.Errorf("unexpected arguments %q\n%s", flags.Args(), keysUsage)
+	}
+
+	return invocation, nil
+}
+
+// run executes the invocation with the given key service.
+func (k keysInvocation) run(ctx context.Context, keys *apikey.Service, out io.Writer) error {
+	switch k.action {
+	case "create":
+		var expiresAt *time.Time
+		if k.expiresIn > 0 {
+			expiry := time.Now().Add(k.expiresIn).UTC().Truncate(time.Second)
+			expiresAt = &expiry
+		}
+
+		key, secret, err := keys.CreateKey(ctx, k.name, k.scopes, expiresAt)
+		if err != nil {
+			return errors.Wrap(err, "failed to create the API key")
+		}
+
+		fmt.Fprintf(out, "Created API key %d (%s) with scopes %s, expiring %s.\n",
+			key.ID, key.Name, formatScopes(key.Scopes), formatTime(key.ExpiresAt, "never"))
+		fmt.Fprintf(out, "Secret: %s\n", secret)
+		fmt.Fprintln(out, "Store the secret now; it cannot be shown again.")
+		return nil
+	case "list":
+		list, err := keys.ListKeys(ctx)
+		if err != nil {
+			return errors.Wrap(err, "failed to list the API keys")
+		}
+
+		writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0) //nolint:gomnd // column padding
+		fmt.Fprintln(writer, "ID\tNAME\tPREFIX\tSCOPES\tEXPIRES\tLAST USED\tSTATUS")
+		now := time.Now()
+		for _, key := range list {
+			fmt.Fprintf(writer, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n", key.ID, key.Name, key.Prefix, formatScopes(key.Scopes),
+				formatTime(key.ExpiresAt, "never"), formatTime(key.LastUsedAt, "never"), keyStatus(key, now))
+		}
+		return errors.Wrap(writer.Flush(), "failed to write the API keys")
+	case "revoke":
+		if err := keys.RevokeKey(ctx, k.id); err != nil {
+			return errors.Wrapf(err, "failed to revoke API key %d", k.id)
+		}
+
+		fmt.Fprintf(out, "Revoked API key %d.\n", k.id)
+		return nil
+	default:
+		return errors.Errorf("unknown keys action %q", k.action)
+	}
+}
+
+// keyStatus describes whether a key is accepted at the given time.
+func keyStatus(key models.APIKey, now time.Time) string {
+	switch {
+	case key.RevokedAt != nil:
+		return "revoked"
+	case !key.IsActive(now):
+		return "expired"
+	default:
+		return "active"
+	}
+}
+
+// formatScopes joins scopes with commas.
+func formatScopes(scopes []models.APIKeyScope) string {
+	values := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		values = append(values, string(scope))
+	}
+	return strings.Join(values, ",")
+}
+
+// formatTime formats an optional time in UTC, or returns fallback if it is unset.
+func formatTime(t *time.Time, fallback string) string {
+	if t == nil {
+		return fallback
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// isKeysCommand reports whether the command line invokes the keys subcommand.
+func isKeysCommand() bool {
+	return len(os.Args) > 1 && os.Args[1] == keysCommand
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/light-bringer/rates-exchanger-service/internal/apikey"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseKeysArgs(t *testing.T) {
+	testCases := []struct {
+		name       string
+		args       []string
+		invocation keysInvocation
+		err        string
+	}{
+		{
+			name: "Create",
+			args: []string{"create", "-name", "reporting", "-scopes", "rates:read,admin", "-expires-in", "720h"},
+			invocation: keysInvocation{
+				action:     "create",
+				configPath: "config.yaml",
+				name:       "reporting",
+				scopes:     []models.APIKeyScope{models.ScopeRatesRead, models.ScopeAdmin},
+				expiresIn:  720 * time.Hour,
+			},
+		},
+		{
+			name: "Create with default scope",
+			args: []string{"create", "-config-file", "prod.yaml", "-name", "reporting"},
+			invocation: keysInvocation{
+				action:     "create",
+				configPath: "prod.yaml",
+				name:       "reporting",
+				scopes:     []models.APIKeyScope{models.ScopeRatesRead},
+			},
+		},
+		{
+			name:       "List",
+			args:       []string{"list"},
+			invocation: keysInvocation{action: "list", configPath: "config.yaml"},
+		},
+		{
+			name:       "Revoke",
+			args:       []string{"revoke", "42"},
+			invocation: keysInvocation{action: "revoke", configPath: "config.yaml", id: 42},
+		},
+		{
+			name: "Missing action",
+			err:  "missing keys action",
+		},
+		{
+			name: "Unknown action",
+			args: []string{"rotate"},
+			err:  `unknown keys action "rotate"`,
+		},
+		{
+			name: "Unknown scope",
+			args: []string{"create", "-name", "reporting", "-scopes", "root"},
+			err:  `unknown scope "root"`,
+		},
+		{
+			name: "Unknown flag",
+			args: []string{"list", "-all"},
+			err:  "invalid arguments",
+		},
+		{
+			name: "Revoke without ID",
+			args: []string{"revoke"},
+			err:  "revoke takes the ID of the key",
+		},
+		{
+			name: "Invalid ID",
+			args: []string{"revoke", "abc"},
+			err:  `invalid key ID "abc"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			invocation, err := parseKeysArgs(tc.args)
+			if tc.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.invocation, invocation)
+		})
+	}
+}
+
+func TestKeysInvocation_Run(t *testing.T) {
+	ctx := context.Background()
+	keys := apikey.NewService(apikey.NewMemoryStore())
+
+	var out bytes.Buffer
+	create := keysInvocation{action: "create", name: "reporting", scopes: []models.APIKeyScope{models.ScopeRatesRead}}
+	require.NoError(t, create.run(ctx, keys, &out))
+	assert.Contains(t, out.String(), "Created API key 1 (reporting) with scopes rates:read, expiring never.")
+	assert.Contains(t, out.String(), "Secret: rx_")
+
+	secret := strings.TrimPrefix(strings.Split(out.String(), "\n")[1], "Secret: ")
+	_, err := keys.Authenticate(ctx, secret)
+	require.NoError(t, err)
+
+	out.Reset()
+	require.NoError(t, keysInvocation{action: "revoke", id: 1}.run(ctx, keys, &out))
+	assert.Equal(t, "Revoked API key 1.\n", out.String())
+	assert.ErrorIs(t, keysInvocation{action: "revoke", id: 2}.run(ctx, keys, &out), apikey.ErrKeyNotFound)
+
+	out.Reset()
+	require.NoError(t, keysInvocation{action: "list"}.run(ctx, keys, &out))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, []string{"ID", "NAME", "PREFIX", "SCOPES", "EXPIRES", "LAST", "USED", "STATUS"}, strings.Fields(lines[0]))
+	fields := strings.Fields(lines[1])
+	assert.Equal(t, []string{"1", "reporting", secret[:10], "rates:read", "never"}, fields[:5])
+	assert.Equal(t, "revoked", fields[len(fields)-1])
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -12,13 +13,28 @@ import (
 
 	"github.com/light-bringer/rates-exchanger-service/cron"
 	"github.com/light-bringer/rates-exchanger-service/db"
+	"github.com/light-bringer/rates-exchanger-service/internal/alert"
+	"github.com/light-bringer/rates-exchanger-service/internal/apikey"
+	"github.com/light-bringer/rates-exchanger-service/internal/currency"
 	"github.com/light-bringer/rates-exchanger-service/internal/handler"
+	"github.com/light-bringer/rates-exchanger-service/internal/health"
+	"github.com/light-bringer/rates-exchanger-service/internal/metrics"
+	"github.com/light-bringer/rates-exchanger-service/internal/ratelimit"
+	"github.com/light-bringer/rates-exchanger-service/internal/rpc"
 	"github.com/light-bringer/rates-exchanger-service/internal/service"
 	"github.com/light-bringer/rates-exchanger-service/internal/sync"
-	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/light-bringer/rates-exchanger-service/internal/webhook"
 )
 
 func main() {
+	// Manage the API keys instead of serving if the keys subcommand is given
+	if isKeysCommand() {
+		if err := runKeys(os.Args[2:], os.Stdout); err != nil {
+			log.Fatalf("Error running the keys command: %v", err)
+		}
+		return
+	}
+
 	// read the configuration file
 	configPath, paramErr := parseFlags()
 	if paramErr != nil {
@@ -33,17 +49,7 @@ func main() {
 	// set the default values for the configuration
 	setDefaults(config)
 
-	dbParams := models.PostgresConfigParams{
-		Host:           config.Database.Host,
-		Port:           config.Database.Port,
-		Username:       config.Database.User,
-		Password:       config.Database.Pass,
-		Database:       config.Database.Name,
-		SSLMode:        string(config.Database.SSLMode),
-		MinConnections: config.Database.MinConnections,
-		MaxConnections: config.Database.MaxConnections,
-		SchemaName:     config.Database.Schema,
-	}
+	dbParams := postgresParams(config)
 
 	// Create a context that listens for termination signals
 	ctx, cancel := signal.NotifyContext(
@@ -71,23 +77,100 @@ func main() {
 		log.Fatal("Error connecting to the database", err)
 	}
 	defer dbConn.Close()
+
+	// Seed the ISO 4217 currencies before the first sync, which only stores rates of known currencies
+	currencyService := currency.NewService(currency.NewPostgresStore(dbConn, config.Database.Schema))
+	if err = currencyService.Seed(context.Background()); err != nil {
+		log.Fatalf("Error seeding the currencies: %v", err)
+	}
+
 	syncService := sync.NewExchangeRateSync(config.Database.Schema, config.CronJobs.Rates.SyncURL, dbConn)
+
+	// Notify the webhooks of the rates changed by each sync, including the startup sync
+	var webhookService *webhook.Service
+	if config.Webhooks.Enabled {
+		webhookService = webhook.NewService(webhook.NewPostgresStore(dbConn, config.Database.Schema), config.Webhooks)
+		syncService.AddListener(webhookService.Notify)
+		go webhookService.Run(ctx)
+	}
+
+	// Create a new rates service, which also provides the rates the alert rules are evaluated on
+	ratesService := service.NewRatesService(dbConn, config.Database.Schema)
+
+	// Share a single watch of the stored rates among the rate streams of the HTTP and gRPC APIs
+	versionHub := service.NewVersionHub(ctx, ratesService, config.API.StreamPollInterval)
+
+	// Evaluate the alert rules on the rates changed by each sync
+	var alertService *alert.Service
+	if config.Alerts.Enabled {
+		notifier, notifierErr := alert.NewNotifier(config.Alerts)
+		if notifierErr != nil {
+			log.Fatalf("Error creating the alert notifier: %v", notifierErr)
+		}
+		alertService = alert.NewService(alert.NewPostgresStore(dbConn, config.Database.Schema), ratesService, notifier)
+		syncService.AddListener(alertService.Notify)
+		go alertService.Run(ctx)
+	}
+
+	// Collect the metrics of the HTTP requests, the database pool, the jobs and the stored rates,
+	// reading the stored rates within the timeout of the readiness checks
+	serviceMetrics := metrics.NewMetrics(metrics.NewPoolCollector(dbConn),
+		metrics.NewDataCollector(ratesService, config.Health.Timeout))
+	syncService.AddRunObserver(serviceMetrics.ObserveRun)
+
+	// Limit the requests of each client per route group, sharing the limits through Postgres if configured
+	var limiter *ratelimit.Limiter
+	if config.RateLimit.Enabled {
+		store, storeErr := ratelimit.NewStore(config.RateLimit.Store, dbConn, config.Database.Schema)
+		if storeErr != nil {
+			log.Fatalf("Error creating the rate limit store: %v", storeErr)
+		}
+		if limiter, err = ratelimit.NewLimiter(store, config.RateLimit); err != nil {
+			log.Fatalf("Error creating the rate limiter: %v", err)
+		}
+		go limiter.Run(ctx)
+	}
+
+	// Require an API key on all routes but the anonymous ones
+	var keyService *apikey.Service
+	if config.Auth.Enabled {
+		keyService = apikey.NewService(apikey.NewPostgresStore(dbConn, config.Database.Schema))
+	}
+
 	cleanSvc := func() {
 		syncService.Cleanup(config.CronJobs.Cleanup.MaxAge)
 	}
-	// Run the cleanup service once before starting the cron job
-	cleanSvc()
 
-	// Run the sync service once before starting the cron job
-	syncService.Sync()
+	// Run the cleanup and sync services once before starting the cron jobs.
+	// They run in the background, so that the health probes are answered while the startup sync runs;
+	// the readiness probe fails until it finished.
+	go func() {
+		cleanSvc()
+		syncService.Sync()
 
-	// Start the cron jobs
-	go cron.Periodically(ctx, syncService.Sync, config.CronJobs.Rates.UpdateInterval)
-	go cron.Periodically(ctx, cleanSvc, config.CronJobs.Cleanup.DeletionInterval)
+		go cron.Periodically(ctx, syncService.Sync, config.CronJobs.Rates.UpdateInterval)
+		go cron.Periodically(ctx, cleanSvc, config.CronJobs.Cleanup.DeletionInterval)
+	}()
 
-	// Create a new rates service and handler
-	ratesService := service.NewRatesService(dbConn, config.Database.Schema)
-	ratesHandler := handler.NewHandler(ratesService)
+	// Create a new rates handler
+	checker := health.NewChecker(dbConn, ratesService, syncService, config.Health)
+	ratesHandler := handler.NewHandler(ratesService, config.API).
+		WithVersionHub(versionHub).
+		WithCurrencies(currencyService).
+		WithHealth(checker).
+		WithMetrics(serviceMetrics)
+	if webhookService != nil {
+		ratesHandler.WithWebhooks(webhookService)
+	}
+	if alertService != nil {
+		ratesHandler.WithAlerts(alertService)
+	}
+	if limiter != nil {
+		ratesHandler.WithRateLimit(limiter)
+	}
+	if keyService != nil {
+		ratesHandler.WithAuthentication(keyService, config.Auth.Anonymous)
+	}
 
 	server := &http.Server{
 		Addr:         fmt.Sprintf(":%d", config.HTTP.Port),
@@ -95,6 +178,8 @@ func main() {
 		ReadTimeout:  ServerTimeout,
 		WriteTimeout: ServerTimeout,
 	}
+	// End the open rate streams when shutting down, since they never become idle.
+	server.RegisterOnShutdown(ratesHandler.Close)
 
 	// Run server in a goroutine so that it doesn't block
 	go func() {
@@ -104,6 +189,29 @@ func main() {
 		}
 	}()
 
+	// Start the gRPC server next to the HTTP server
+	var grpcServer *rpc.Server
+	if config.GRPC.Enabled {
+		listener, listenErr := net.Listen("tcp", fmt.Sprintf(":%d", config.GRPC.Port))
+		if listenErr != nil {
+			log.Fatalf("gRPC server Listen: %v", listenErr)
+		}
+
+		grpcServer = rpc.NewServer(ratesService, versionHub)
+		if limiter != nil {
+			grpcServer.WithRateLimit(limiter)
+		}
+		if keyService != nil {
+			grpcServer.WithAuthentication(keyService, config.Auth.Anonymous)
+		}
+		go func() {
+			slog.Info("Starting gRPC server", "addr", listener.Addr().String())
+			if err := grpcServer.Serve(listener); err != nil {
+				log.Fatalf("gRPC server Serve: %v", err)
+			}
+		}()
+	}
+
 	// Listen for the context cancellation signal from NotifyContext
 	<-ctx.Done()
 
@@ -121,5 +229,11 @@ func main() {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
 
+	if grpcServer != nil {
+		if err := grpcServer.Shutdown(shutdownCtx); err != nil {
+			log.Fatalf("gRPC server forced to shutdown: %v", err)
+		}
+	}
+
 	slog.Info("Server exited gracefully!")
 }
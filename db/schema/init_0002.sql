@@ -0,0 +1,13 @@
+-- Table: rate_api.sync_state
+-- Single row holding the data version of rate_api.exchange_rates.
+-- The sequence is incremented in the same transaction whenever a sync or cleanup changes rows,
+-- so that every API instance derives the same ETag from it.
+
+CREATE TABLE
+    IF NOT EXISTS rate_api.sync_state (
+        id BOOLEAN NOT NULL DEFAULT TRUE PRIMARY KEY CHECK (id),
+        sequence BIGINT NOT NULL DEFAULT 0,
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO rate_api.sync_state (id) VALUES (TRUE) ON CONFLICT (id) DO NOTHING;
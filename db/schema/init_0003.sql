@@ -0,0 +1,12 @@
+-- Table: rate_api.sync_events
+-- One row per sync that inserted or changed rates, keyed by the sync sequence of rate_api.sync_state.
+-- The rows let streaming clients resume from the last event they received.
+
+CREATE TABLE
+    IF NOT EXISTS rate_api.sync_events (
+        sequence BIGINT NOT NULL PRIMARY KEY,
+        latest_day DATE NOT NULL,
+        currencies TEXT[] NOT NULL,
+        days DATE[] NOT NULL,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
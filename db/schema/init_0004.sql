@@ -0,0 +1,42 @@
+-- Table: rate_api.webhooks
+-- Subscriptions notified after a sync inserted or changed rates.
+-- The secret signs the deliveries and is therefore stored as is.
+
+CREATE TABLE
+    IF NOT EXISTS rate_api.webhooks (
+        id BIGSERIAL PRIMARY KEY,
+        url TEXT NOT NULL,
+        secret TEXT NOT NULL,
+        description TEXT NOT NULL DEFAULT '',
+        active BOOLEAN NOT NULL DEFAULT TRUE,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Table: rate_api.webhook_deliveries
+-- Delivery log: one row per event and webhook, updated on every attempt.
+-- Deliveries that exhausted their attempts are kept with status 'dead' (dead letters).
+-- Indexes: due deliveries, deliveries per webhook
+
+CREATE TABLE
+    IF NOT EXISTS rate_api.webhook_deliveries (
+        id BIGSERIAL PRIMARY KEY,
+        webhook_id BIGINT NOT NULL REFERENCES rate_api.webhooks (id) ON DELETE CASCADE,
+        event TEXT NOT NULL,
+        sequence BIGINT NOT NULL,
+        payload JSONB NOT NULL,
+        status TEXT NOT NULL DEFAULT 'pending',
+        attempts INTEGER NOT NULL DEFAULT 0,
+        next_attempt_at TIMESTAMPTZ,
+        last_status_code INTEGER,
+        last_error TEXT NOT NULL DEFAULT '',
+        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+        delivered_at TIMESTAMPTZ
+);
+
+CREATE INDEX
+    IF NOT EXISTS webhook_deliveries_due_idx ON rate_api.webhook_deliveries (next_attempt_at)
+    WHERE status = 'pending';
+
+CREATE INDEX
+    IF NOT EXISTS webhook_deliveries_webhook_idx ON rate_api.webhook_deliveries (webhook_id, id DESC);
@@ -0,0 +1,17 @@
+-- Table: rate_api.alert_rules
+-- Threshold alerts evaluated after every sync that changed the rates of the latest day.
+-- Conditions: above, below, crosses_above, crosses_below, change_percent
+
+CREATE TABLE
+    IF NOT EXISTS rate_api.alert_rules (
+        id BIGSERIAL PRIMARY KEY,
+        currency CHAR(3) NOT NULL,
+        base CHAR(3) NOT NULL DEFAULT 'EUR',
+        condition TEXT NOT NULL,
+        threshold DOUBLE PRECISION NOT NULL,
+        cooldown_seconds BIGINT NOT NULL DEFAULT 0,
+        active BOOLEAN NOT NULL DEFAULT TRUE,
+        last_triggered_at TIMESTAMPTZ,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
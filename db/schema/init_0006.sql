@@ -0,0 +1,14 @@
+-- Table: rate_api.currencies
+-- ISO 4217 currencies, seeded by the service at startup from its embedded list.
+-- Rates of currencies missing from this table are rejected by the sync.
+
+CREATE TABLE
+    IF NOT EXISTS rate_api.currencies (
+        code CHAR(3) PRIMARY KEY,
+        name TEXT NOT NULL,
+        numeric_code CHAR(3) NOT NULL,
+        -- NULL for units without minor units, such as gold or the SDR
+        minor_units SMALLINT,
+        countries TEXT[] NOT NULL DEFAULT '{}',
+        withdrawn BOOLEAN NOT NULL DEFAULT FALSE
+);
@@ -0,0 +1,14 @@
+-- Table: rate_api.rate_limit_buckets
+-- Token buckets of the rate limiter, shared by all instances when rate_limit.store is postgres.
+-- Each key is a route group and a client; buckets unused until they are full again are pruned.
+
+CREATE UNLOGGED TABLE
+    IF NOT EXISTS rate_api.rate_limit_buckets (
+        key TEXT PRIMARY KEY,
+        tokens DOUBLE PRECISION NOT NULL,
+        -- whether the last request took a token
+        allowed BOOLEAN NOT NULL,
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS rate_limit_buckets_updated_at_idx ON rate_api.rate_limit_buckets (updated_at);
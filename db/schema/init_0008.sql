@@ -0,0 +1,18 @@
+-- Table: rate_api.api_keys
+-- API keys clients authenticate with when auth.enabled is set. Only the SHA-256 hash of each secret is stored;
+-- the prefix identifies a key in listings without revealing it. Keys are managed with the keys subcommand.
+
+CREATE TABLE
+    IF NOT EXISTS rate_api.api_keys (
+        id BIGSERIAL PRIMARY KEY,
+        name TEXT NOT NULL,
+        prefix TEXT NOT NULL,
+        hash CHAR(64) NOT NULL UNIQUE,
+        -- rates:read or admin
+        scopes TEXT[] NOT NULL,
+        -- NULL for keys that do not expire
+        expires_at TIMESTAMPTZ,
+        last_used_at TIMESTAMPTZ,
+        revoked_at TIMESTAMPTZ,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
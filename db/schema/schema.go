@@ -0,0 +1,10 @@
+// Package schema embeds the SQL scripts creating the tables of the service, which the database container
+// runs when it is first started.
+package schema
+
+import "embed"
+
+// Scripts holds the init_*.sql scripts of the rate_api schema; their names sort in the order they are run.
+//
+//go:embed *.sql
+var Scripts embed.FS
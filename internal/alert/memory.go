@@ -0,0 +1,108 @@
+package alert
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/light-bringer/rates-exchanger-service/models"
+)
+
+// MemoryStore keeps alert rules in memory. It is safe for concurrent use.
+type MemoryStore struct {
+	mu     sync.Mutex
+	lastID int64
+	rules  map[int64]models.AlertRule
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{rules: make(map[int64]models.AlertRule)}
+}
+
+func (m *MemoryStore) CreateRule(_ context.Context, rule models.AlertRule) (models.AlertRule, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lastID++
+	now := time.Now()
+	rule.ID = m.lastID
+	rule.CreatedAt = now
+	rule.UpdatedAt = now
+	m.rules[rule.ID] = rule
+
+	return rule, nil
+}
+
+func (m *MemoryStore) ListRules(_ context.Context, activeOnly bool) ([]models.AlertRule, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	rules := make([]models.AlertRule, 0, len(m.rules))
+	for _, rule := range m.rules {
+		if activeOnly && !rule.Active {
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
+
+	return rules, nil
+}
+
+func (m *MemoryStore) GetRule(_ context.Context, id int64) (models.AlertRule, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	rule, ok := m.rules[id]
+	if !ok {
+		return models.AlertRule{}, ErrRuleNotFound
+	}
+	return rule, nil
+}
+
+func (m *MemoryStore) UpdateRule(_ context.Context, rule models.AlertRule) (models.AlertRule, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, ok := m.rules[rule.ID]
+	if !ok {
+		return models.AlertRule{}, ErrRuleNotFound
+	}
+
+	stored.Currency = rule.Currency
+	stored.Base = rule.Base
+	stored.Condition = rule.Condition
+	stored.Threshold = rule.Threshold
+	stored.CooldownSeconds = rule.CooldownSeconds
+	stored.Active = rule.Active
+	stored.UpdatedAt = time.Now()
+	m.rules[stored.ID] = stored
+
+	return stored, nil
+}
+
+func (m *MemoryStore) DeleteRule(_ context.Context, id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.rules[id]; !ok {
+		return ErrRuleNotFound
+	}
+	delete(m.rules, id)
+	return nil
+}
+
+func (m *MemoryStore) MarkTriggered(_ context.Context, id int64, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	rule, ok := m.rules[id]
+	if !ok {
+		return ErrRuleNotFound
+	}
+	rule.LastTriggeredAt = &at
+	m.rules[id] = rule
+	return nil
+}
@@ -0,0 +1,207 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/light-bringer/rates-exchanger-service/internal/logging"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+)
+
+// Notifier sends triggered alerts.
+type Notifier interface {
+	Notify(ctx context.Context, alert models.Alert) error
+}
+
+// NewNotifier returns the notifier configured for the alerts. Without configured notifiers, alerts are logged.
+// Several notifiers are combined, so that every alert is sent through all of them.
+func NewNotifier(config models.AlertConfig) (Notifier, error) {
+	if len(config.Notifiers) == 0 {
+		return LogNotifier{}, nil
+	}
+
+	notifiers := make(MultiNotifier, 0, len(config.Notifiers))
+	for _, notifierType := range config.Notifiers {
+		switch notifierType {
+		case models.NotifierLog:
+			notifiers = append(notifiers, LogNotifier{})
+		case models.NotifierSMTP:
+			notifier, err := NewSMTPNotifier(config.SMTP)
+			if err != nil {
+				return nil, err
+			}
+			notifiers = append(notifiers, notifier)
+		default:
+			return nil, errors.Errorf("unknown alert notifier %q", notifierType)
+		}
+	}
+
+	if len(notifiers) == 1 {
+		return notifiers[0], nil
+	}
+	return notifiers, nil
+}
+
+// LogNotifier writes alerts to the service log.
+type LogNotifier struct{}
+
+func (LogNotifier) Notify(ctx context.Context, alert models.Alert) error {
+	logging.FromContext(ctx).Warn("Alert triggered", "rule", alert.Rule.ID, "alert", alert.Subject(), "date", alert.Date,
+		"rate", alert.Rate, "previous_date", alert.PreviousDate, "previous_rate", alert.PreviousRate)
+	return nil
+}
+
+const (
+	// notifyAttempts is how often MultiNotifier tries a notifier before giving up on an alert.
+	notifyAttempts = 2
+	// notifyRetryDelay is the pause before MultiNotifier retries the failed notifiers.
+	notifyRetryDelay = time.Second
+)
+
+// MultiNotifier sends alerts through several notifiers. A failing notifier does not keep the others
+// from being notified; only the failed notifiers are retried, so that the others do not send duplicates.
+// The alert counts as sent once any notifier delivered it, so that the rule is marked as triggered and
+// the next sync does not notify the successful notifiers again. The first error is returned if all fail.
+type MultiNotifier []Notifier
+
+func (m MultiNotifier) Notify(ctx context.Context, alert models.Alert) error {
+	logger := logging.FromContext(ctx)
+	var firstErr error
+	delivered := false
+	pending := m
+	for attempt := 1; len(pending) > 0; attempt++ {
+		failed := make(MultiNotifier, 0, len(pending))
+		for _, notifier := range pending {
+			if err := notifier.Notify(ctx, alert); err != nil {
+				logger.Error("Failed to send alert", "rule", alert.Rule.ID, "attempt", attempt, "error", err)
+				failed = append(failed, notifier)
+				if firstErr == nil {
+					firstErr = err
+				}
+				continue
+			}
+			delivered = true
+		}
+		if len(failed) == 0 || attempt == notifyAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			failed = nil
+		case <-time.After(notifyRetryDelay):
+		}
+		pending = failed
+	}
+
+	if delivered {
+		return nil
+	}
+	return firstErr
+}
+
+// SMTPNotifier sends alerts as plain text emails. STARTTLS is used if the server offers it.
+type SMTPNotifier struct {
+	host string
+	addr string
+	auth smtp.Auth
+	from string
+	to   []string
+}
+
+// NewSMTPNotifier returns a new SMTPNotifier with the given configuration.
+func NewSMTPNotifier(config models.SMTPConfig) (*SMTPNotifier, error) {
+	if config.Host == "" || config.From == "" || len(config.To) == 0 {
+		return nil, errors.New("the smtp notifier requires a host, a sender and recipients")
+	}
+
+	port := config.Port
+	if port == 0 {
+		port = 25
+	}
+
+	notifier := &SMTPNotifier{
+		host: config.Host,
+		addr: net.JoinHostPort(config.Host, strconv.Itoa(port)),
+		from: config.From,
+		to:   config.To,
+	}
+	if config.Username != "" {
+		notifier.auth = smtp.PlainAuth("", config.Username, config.Password, config.Host)
+	}
+
+	return notifier, nil
+}
+
+func (n *SMTPNotifier) Notify(ctx context.Context, alert models.Alert) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", n.addr)
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to smtp server")
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, n.host)
+	if err != nil {
+		conn.Close()
+		return errors.Wrap(err, "failed to start smtp session")
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err = client.StartTLS(&tls.Config{ServerName: n.host, MinVersion: tls.VersionTLS12}); err != nil {
+			return errors.Wrap(err, "failed to start tls")
+		}
+	}
+	if n.auth != nil {
+		if err = client.Auth(n.auth); err != nil {
+			return errors.Wrap(err, "failed to authenticate")
+		}
+	}
+
+	if err = client.Mail(n.from); err != nil {
+		return errors.Wrap(err, "failed to set sender")
+	}
+	for _, recipient := range n.to {
+		if err = client.Rcpt(recipient); err != nil {
+			return errors.Wrapf(err, "failed to add recipient %s", recipient)
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return errors.Wrap(err, "failed to start message")
+	}
+	if _, err = writer.Write(n.message(alert)); err != nil {
+		return errors.Wrap(err, "failed to write message")
+	}
+	if err = writer.Close(); err != nil {
+		return errors.Wrap(err, "failed to send message")
+	}
+
+	return errors.Wrap(client.Quit(), "failed to end smtp session")
+}
+
+// message formats an alert as an RFC 5322 message.
+func (n *SMTPNotifier) message(alert models.Alert) []byte {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", n.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(n.to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", "Rate alert: "+alert.Subject()))
+	fmt.Fprintf(&msg, "Date: %s\r\n", alert.TriggeredAt.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(alert.Body(), "\n", "\r\n"))
+	return msg.Bytes()
+}
@@ -0,0 +1,223 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/light-bringer/rates-exchanger-service/internal/logging"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// smtpMessage is a message received by the fake SMTP server.
+type smtpMessage struct {
+	from string
+	to   []string
+	data string
+}
+
+// fakeSMTPServer accepts a single SMTP session on a local port and returns the received message.
+func fakeSMTPServer(t *testing.T) (string, int, <-chan smtpMessage) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	messages := make(chan smtpMessage, 1)
+	go func() {
+		conn, acceptErr := listener.Accept()
+		if acceptErr != nil {
+			return
+		}
+		defer conn.Close()
+
+		text := textproto.NewConn(conn)
+		var msg smtpMessage
+		text.PrintfLine("220 localhost ESMTP fake")
+		for {
+			line, readErr := text.ReadLine()
+			if readErr != nil {
+				return
+			}
+			command := strings.ToUpper(line)
+			switch {
+			case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+				text.PrintfLine("250-localhost")
+				text.PrintfLine("250 8BITMIME")
+			case strings.HasPrefix(command, "MAIL FROM:"):
+				// Drop ESMTP parameters such as BODY=8BITMIME.
+				from, _, _ := strings.Cut(strings.TrimSpace(line[len("MAIL FROM:"):]), " ")
+				msg.from = strings.Trim(from, "<>")
+				text.PrintfLine("250 OK")
+			case strings.HasPrefix(command, "RCPT TO:"):
+				msg.to = append(msg.to, strings.Trim(line[len("RCPT TO:"):], "<> "))
+				text.PrintfLine("250 OK")
+			case command == "DATA":
+				text.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+				data, dataErr := text.ReadDotBytes()
+				if dataErr != nil {
+					return
+				}
+				msg.data = string(data)
+				text.PrintfLine("250 OK")
+			case command == "QUIT":
+				text.PrintfLine("221 Bye")
+				messages <- msg
+				return
+			default:
+				text.PrintfLine("502 Command not implemented")
+			}
+		}
+	}()
+
+	host, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	portNumber, err := strconv.Atoi(port)
+	require.NoError(t, err)
+
+	return host, portNumber, messages
+}
+
+func testAlert() models.Alert {
+	return models.Alert{
+		Rule: models.AlertRule{
+			ID: 3, Currency: "TRY", Base: "EUR", Condition: models.AlertCrossesAbove, Threshold: 35,
+		},
+		Date:          "2024-06-03",
+		Rate:          35.12,
+		PreviousDate:  "2024-05-31",
+		PreviousRate:  34.9,
+		ChangePercent: 0.63,
+		TriggeredAt:   time.Date(2024, 6, 3, 16, 5, 0, 0, time.UTC),
+	}
+}
+
+func TestSMTPNotifier(t *testing.T) {
+	host, port, messages := fakeSMTPServer(t)
+
+	notifier, err := NewSMTPNotifier(models.SMTPConfig{
+		Host: host,
+		Port: port,
+		From: "rates@example.com",
+		To:   []string{"treasury@example.com", "fx@example.com"},
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, notifier.Notify(ctx, testAlert()))
+
+	select {
+	case msg := <-messages:
+		assert.Equal(t, "rates@example.com", msg.from)
+		assert.Equal(t, []string{"treasury@example.com", "fx@example.com"}, msg.to)
+		assert.Contains(t, msg.data, "Subject: Rate alert: EUR/TRY crossed above 35 to 35.12\n")
+		assert.Contains(t, msg.data, "To: treasury@example.com, fx@example.com\n")
+		assert.Contains(t, msg.data, "Rate on 2024-05-31: 34.9\n")
+	case <-ctx.Done():
+		t.Fatal("no message received")
+	}
+}
+
+func TestSMTPNotifierUnreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().(*net.TCPAddr)
+	listener.Close()
+
+	notifier, err := NewSMTPNotifier(models.SMTPConfig{
+		Host: "127.0.0.1", Port: addr.Port, From: "rates@example.com", To: []string{"fx@example.com"},
+	})
+	require.NoError(t, err)
+
+	err = notifier.Notify(context.Background(), testAlert())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to connect to smtp server")
+}
+
+func TestLogNotifier(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := logging.NewContext(context.Background(), slog.New(slog.NewTextHandler(&buf, nil)).With("request_id", "abc"))
+
+	require.NoError(t, LogNotifier{}.Notify(ctx, testAlert()))
+	assert.Contains(t, buf.String(), "Alert triggered")
+	assert.Contains(t, buf.String(), "request_id=abc", "the alert is logged with the logger of the context")
+}
+
+func TestMultiNotifier(t *testing.T) {
+	ctx := context.Background()
+
+	failing := &recordingNotifier{err: errors.New("smtp down")}
+	succeeding := &recordingNotifier{}
+	require.NoError(t, MultiNotifier{failing, succeeding}.Notify(ctx, testAlert()),
+		"the alert is sent if any notifier delivers it")
+	assert.Len(t, succeeding.alerts, 1, "only the failed notifiers are retried")
+
+	err := MultiNotifier{failing, &recordingNotifier{err: errors.New("log down")}}.Notify(ctx, testAlert())
+	require.Error(t, err)
+	assert.Equal(t, "smtp down", err.Error())
+}
+
+func TestNewNotifier(t *testing.T) {
+	smtpConfig := models.SMTPConfig{Host: "localhost", From: "rates@example.com", To: []string{"fx@example.com"}}
+
+	testCases := []struct {
+		name        string
+		config      models.AlertConfig
+		expected    interface{}
+		expectedErr string
+	}{
+		{
+			name:     "Log by default",
+			config:   models.AlertConfig{},
+			expected: LogNotifier{},
+		},
+		{
+			name:     "SMTP",
+			config:   models.AlertConfig{Notifiers: []models.NotifierType{models.NotifierSMTP}, SMTP: smtpConfig},
+			expected: &SMTPNotifier{},
+		},
+		{
+			name: "Combined",
+			config: models.AlertConfig{
+				Notifiers: []models.NotifierType{models.NotifierLog, models.NotifierSMTP},
+				SMTP:      smtpConfig,
+			},
+			expected: MultiNotifier{},
+		},
+		{
+			name:        "Incomplete SMTP",
+			config:      models.AlertConfig{Notifiers: []models.NotifierType{models.NotifierSMTP}},
+			expectedErr: "requires a host",
+		},
+		{
+			name:        "Unknown notifier",
+			config:      models.AlertConfig{Notifiers: []models.NotifierType{"pager"}},
+			expectedErr: `unknown alert notifier "pager"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			notifier, err := NewNotifier(tc.config)
+			if tc.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.IsType(t, tc.expected, notifier)
+		})
+	}
+}
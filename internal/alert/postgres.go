@@ -0,0 +1,199 @@
+package alert
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/light-bringer/rates-exchanger-service/internal/logging"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+)
+
+// ruleColumns are the columns of a rule, in the order scanned by scanRule.
+//
+//nolint:gochecknoglobals // read-only column list
+var ruleColumns = []string{
+	"id", "currency", "base", "condition", "threshold", "cooldown_seconds", "active",
+	"last_triggered_at", "created_at", "updated_at",
+}
+
+// PostgresStore stores the alert rules in Postgres.
+type PostgresStore struct {
+	db        *pgxpool.Pool
+	tableName string
+}
+
+// NewPostgresStore returns a new PostgresStore using the table of the given schema.
+func NewPostgresStore(db *pgxpool.Pool, schema string) *PostgresStore {
+	return &PostgresStore{db: db, tableName: schema + ".alert_rules"}
+}
+
+func (p *PostgresStore) builder() squirrel.StatementBuilderType {
+	return squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+}
+
+func (p *PostgresStore) CreateRule(ctx context.Context, rule models.AlertRule) (models.AlertRule, error) {
+	logger := logging.FromContext(ctx)
+	query, args, err := p.builder().
+		Insert(p.tableName).
+		Columns("currency", "base", "condition", "threshold", "cooldown_seconds", "active").
+		Values(rule.Currency, rule.Base, rule.Condition, rule.Threshold, rule.CooldownSeconds, rule.Active).
+		Suffix("RETURNING " + strings.Join(ruleColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return models.AlertRule{}, errors.Wrap(err, "failed to build SQL query")
+	}
+
+	created, err := scanRule(p.db.QueryRow(ctx, query, args...))
+	if err != nil {
+		logger.Error("Failed to create alert rule", "error", err)
+		return models.AlertRule{}, errors.Wrap(err, "failed to create alert rule")
+	}
+
+	return created, nil
+}
+
+func (p *PostgresStore) ListRules(ctx context.Context, activeOnly bool) ([]models.AlertRule, error) {
+	logger := logging.FromContext(ctx)
+	builder := p.builder().
+		Select(ruleColumns...).
+		From(p.tableName).
+		OrderBy("id ASC")
+	if activeOnly {
+		builder = builder.Where(squirrel.Eq{"active": true})
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build SQL query")
+	}
+
+	rows, err := p.db.Query(ctx, query, args...)
+	if err != nil {
+		logger.Error("Failed to execute query", "error", err)
+		return nil, errors.Wrap(err, "failed to execute query")
+	}
+	defer rows.Close()
+
+	rules := make([]models.AlertRule, 0)
+	for rows.Next() {
+		rule, scanErr := scanRule(rows)
+		if scanErr != nil {
+			return nil, errors.Wrap(scanErr, "failed to scan row")
+		}
+		rules = append(rules, rule)
+	}
+	if err = rows.Err(); err != nil {
+		logger.Error("Failed to read rows", "error", err)
+		return nil, errors.Wrap(err, "failed to read rows")
+	}
+
+	return rules, nil
+}
+
+func (p *PostgresStore) GetRule(ctx context.Context, id int64) (models.AlertRule, error) {
+	logger := logging.FromContext(ctx)
+	query, args, err := p.builder().
+		Select(ruleColumns...).
+		From(p.tableName).
+		Where(squirrel.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return models.AlertRule{}, errors.Wrap(err, "failed to build SQL query")
+	}
+
+	rule, err := scanRule(p.db.QueryRow(ctx, query, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.AlertRule{}, ErrRuleNotFound
+	}
+	if err != nil {
+		logger.Error("Failed to fetch alert rule", "error", err)
+		return models.AlertRule{}, errors.Wrap(err, "failed to fetch alert rule")
+	}
+
+	return rule, nil
+}
+
+func (p *PostgresStore) UpdateRule(ctx context.Context, rule models.AlertRule) (models.AlertRule, error) {
+	logger := logging.FromContext(ctx)
+	query, args, err := p.builder().
+		Update(p.tableName).
+		Set("currency", rule.Currency).
+		Set("base", rule.Base).
+		Set("condition", rule.Condition).
+		Set("threshold", rule.Threshold).
+		Set("cooldown_seconds", rule.CooldownSeconds).
+		Set("active", rule.Active).
+		Set("updated_at", squirrel.Expr("NOW()")).
+		Where(squirrel.Eq{"id": rule.ID}).
+		Suffix("RETURNING " + strings.Join(ruleColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return models.AlertRule{}, errors.Wrap(err, "failed to build SQL query")
+	}
+
+	updated, err := scanRule(p.db.QueryRow(ctx, query, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.AlertRule{}, ErrRuleNotFound
+	}
+	if err != nil {
+		logger.Error("Failed to update alert rule", "error", err)
+		return models.AlertRule{}, errors.Wrap(err, "failed to update alert rule")
+	}
+
+	return updated, nil
+}
+
+func (p *PostgresStore) DeleteRule(ctx context.Context, id int64) error {
+	logger := logging.FromContext(ctx)
+	query, args, err := p.builder().Delete(p.tableName).Where(squirrel.Eq{"id": id}).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build SQL query")
+	}
+
+	res, err := p.db.Exec(ctx, query, args...)
+	if err != nil {
+		logger.Error("Failed to delete alert rule", "error", err)
+		return errors.Wrap(err, "failed to delete alert rule")
+	}
+	if res.RowsAffected() == 0 {
+		return ErrRuleNotFound
+	}
+
+	return nil
+}
+
+func (p *PostgresStore) MarkTriggered(ctx context.Context, id int64, at time.Time) error {
+	logger := logging.FromContext(ctx)
+	query, args, err := p.builder().
+		Update(p.tableName).
+		Set("last_triggered_at", at).
+		Where(squirrel.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build SQL query")
+	}
+
+	res, err := p.db.Exec(ctx, query, args...)
+	if err != nil {
+		logger.Error("Failed to mark alert rule as triggered", "error", err)
+		return errors.Wrap(err, "failed to mark alert rule as triggered")
+	}
+	if res.RowsAffected() == 0 {
+		return ErrRuleNotFound
+	}
+
+	return nil
+}
+
+// scanRule scans a row of ruleColumns.
+func scanRule(row pgx.Row) (models.AlertRule, error) {
+	var rule models.AlertRule
+	err := row.Scan(&rule.ID, &rule.Currency, &rule.Base, &rule.Condition, &rule.Threshold, &rule.CooldownSeconds,
+		&rule.Active, &rule.LastTriggeredAt, &rule.CreatedAt, &rule.UpdatedAt)
+	return rule, err //nolint:wrapcheck // wrapped by the callers
+}
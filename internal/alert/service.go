@@ -0,0 +1,265 @@
+// Package alert evaluates threshold alert rules after each sync and sends the triggered alerts.
+package alert
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/light-bringer/rates-exchanger-service/internal/logging"
+	"github.com/light-bringer/rates-exchanger-service/models"
+)
+
+const (
+	baseCurrency = "EUR"
+	// evaluationTimeout bounds the evaluation of the rules after a sync, including the notifications.
+	evaluationTimeout = time.Minute
+)
+
+// RateSource provides the rates the rules are evaluated on. It is implemented by service.RatesService.
+type RateSource interface {
+	// FetchRecentRates returns the EUR based rates of the given number of latest publication days.
+	FetchRecentRates(ctx context.Context, days uint64) (models.TimeSeries, error)
+}
+
+// Service manages the alert rules and evaluates them after each sync.
+// Evaluations run on the goroutine of Run, so that slow or retried notifications do not delay the sync.
+type Service struct {
+	store    Store
+	rates    RateSource
+	notifier Notifier
+	now      func() time.Time
+	// events holds the latest sync event not evaluated yet.
+	events chan models.SyncEvent
+}
+
+// NewService returns a new Service with the given store, rate source and notifier.
+func NewService(store Store, rates RateSource, notifier Notifier) *Service {
+	return &Service{
+		store:    store,
+		rates:    rates,
+		notifier: notifier,
+		now:      time.Now,
+		events:   make(chan models.SyncEvent, 1),
+	}
+}
+
+// CreateRule creates an alert rule.
+func (s *Service) CreateRule(ctx context.Context, rule models.AlertRule) (models.AlertRule, error) {
+	logger := logging.FromContext(ctx)
+	created, err := s.store.CreateRule(ctx, rule)
+	if err != nil {
+		return models.AlertRule{}, err //nolint:wrapcheck // store errors are wrapped
+	}
+
+	logger.Info("Alert rule created", "id", created.ID, "currency", created.Currency, "base", created.Base,
+		"condition", created.Condition, "threshold", created.Threshold)
+	return created, nil
+}
+
+// ListRules returns all alert rules.
+func (s *Service) ListRules(ctx context.Context) ([]models.AlertRule, error) {
+	return s.store.ListRules(ctx, false) //nolint:wrapcheck // store errors are wrapped
+}
+
+// GetRule returns an alert rule.
+func (s *Service) GetRule(ctx context.Context, id int64) (models.AlertRule, error) {
+	return s.store.GetRule(ctx, id) //nolint:wrapcheck // store errors are wrapped
+}
+
+// UpdateRule applies the given fields to an alert rule and returns it.
+func (s *Service) UpdateRule(ctx context.Context, id int64, input models.AlertRuleInput) (models.AlertRule, error) {
+	rule, err := s.store.GetRule(ctx, id)
+	if err != nil {
+		return models.AlertRule{}, err //nolint:wrapcheck // store errors are wrapped
+	}
+
+	if input.Currency != nil {
+		rule.Currency = *input.Currency
+	}
+	if input.Base != nil {
+		rule.Base = *input.Base
+	}
+	if input.Condition != nil {
+		rule.Condition = *input.Condition
+	}
+	if input.Threshold != nil {
+		rule.Threshold = *input.Threshold
+	}
+	if input.CooldownSeconds != nil {
+		rule.CooldownSeconds = *input.CooldownSeconds
+	}
+	if input.Active != nil {
+		rule.Active = *input.Active
+	}
+
+	return s.store.UpdateRule(ctx, rule) //nolint:wrapcheck // store errors are wrapped
+}
+
+// DeleteRule deletes an alert rule.
+func (s *Service) DeleteRule(ctx context.Context, id int64) error {
+	return s.store.DeleteRule(ctx, id) //nolint:wrapcheck // store errors are wrapped
+}
+
+// Notify queues the evaluation of the rules after a sync without blocking. It is registered as listener
+// of the sync. An event that is still queued is replaced, as the rules are evaluated on the latest rates.
+func (s *Service) Notify(event models.SyncEvent) {
+	for {
+		select {
+		case s.events <- event:
+			return
+		default:
+		}
+
+		select {
+		case <-s.events:
+		default:
+		}
+	}
+}
+
+// Run evaluates the rules for the queued sync events until the context is done.
+func (s *Service) Run(ctx context.Context) {
+	logger := logging.FromContext(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Debug("Stopping alert rule evaluation...")
+			return
+		case event := <-s.events:
+			s.evaluateEvent(ctx, event)
+		}
+	}
+}
+
+// evaluateEvent evaluates the rules for a sync event within the evaluation timeout.
+func (s *Service) evaluateEvent(ctx context.Context, event models.SyncEvent) {
+	ctx, cancel := context.WithTimeout(ctx, evaluationTimeout)
+	defer cancel()
+
+	s.Evaluate(ctx, event)
+}
+
+// Evaluate evaluates the active rules on the latest two publication days and notifies the triggered ones
+// whose cooldown has passed. Rules are only evaluated if the sync changed the rates of the latest day,
+// so that backfilled history does not trigger alerts for stale rates.
+// The function returns the alerts that were sent.
+func (s *Service) Evaluate(ctx context.Context, event models.SyncEvent) []models.Alert {
+	logger := logging.FromContext(ctx)
+	series, err := s.rates.FetchRecentRates(ctx, 2) //nolint:gomnd // the latest and the previous day
+	if err != nil {
+		logger.Error("Failed to fetch rates for alert rules", "error", err)
+		return nil
+	}
+
+	days := make([]string, 0, len(series))
+	for day := range series {
+		days = append(days, day)
+	}
+	sort.Strings(days)
+	if len(days) == 0 || days[len(days)-1] != event.Date {
+		logger.Debug("Latest rates unchanged, alert rules not evaluated", "sync_date", event.Date)
+		return nil
+	}
+
+	rules, err := s.store.ListRules(ctx, true)
+	if err != nil {
+		logger.Error("Failed to fetch alert rules", "error", err)
+		return nil
+	}
+
+	latest := days[len(days)-1]
+	previous := ""
+	if len(days) > 1 {
+		previous = days[0]
+	}
+
+	now := s.now()
+	sent := make([]models.Alert, 0)
+	for _, rule := range rules {
+		alert, triggered := evaluate(rule, latest, series[latest], previous, series[previous])
+		if !triggered {
+			continue
+		}
+		if rule.LastTriggeredAt != nil &&
+			now.Before(rule.LastTriggeredAt.Add(time.Duration(rule.CooldownSeconds)*time.Second)) {
+			logger.Debug("Alert rule in cooldown", "rule", rule.ID, "last_triggered_at", rule.LastTriggeredAt)
+			continue
+		}
+
+		alert.TriggeredAt = now
+		if err = s.notifier.Notify(ctx, alert); err != nil {
+			// The rule is not marked, so that the next sync retries the notification.
+			logger.Error("Failed to send alert", "rule", rule.ID, "error", err)
+			continue
+		}
+		if err = s.store.MarkTriggered(ctx, rule.ID, now); err != nil {
+			logger.Error("Failed to mark alert rule as triggered", "rule", rule.ID, "error", err)
+		}
+		sent = append(sent, alert)
+	}
+
+	logger.Info("Alert rules evaluated", "date", latest, "rules", len(rules), "alerts", len(sent))
+	return sent
+}
+
+// evaluate evaluates a rule on the EUR based rates of the latest and the previous publication day.
+// Conditions comparing both days are not met without a previous day.
+func evaluate(
+	rule models.AlertRule,
+	latest string,
+	latestRates map[string]float64,
+	previous string,
+	previousRates map[string]float64,
+) (models.Alert, bool) {
+	rate, ok := crossRate(latestRates, rule.Currency, rule.Base)
+	if !ok {
+		return models.Alert{}, false
+	}
+
+	alert := models.Alert{Rule: rule, Date: latest, Rate: rate}
+	previousRate, hasPrevious := crossRate(previousRates, rule.Currency, rule.Base)
+	if hasPrevious {
+		alert.PreviousDate = previous
+		alert.PreviousRate = previousRate
+		alert.ChangePercent = (rate - previousRate) / previousRate * models.Percent
+	}
+
+	switch rule.Condition {
+	case models.AlertAbove:
+		return alert, rate > rule.Threshold
+	case models.AlertBelow:
+		return alert, rate < rule.Threshold
+	case models.AlertCrossesAbove:
+		return alert, hasPrevious && previousRate <= rule.Threshold && rate > rule.Threshold
+	case models.AlertCrossesBelow:
+		return alert, hasPrevious && previousRate >= rule.Threshold && rate < rule.Threshold
+	case models.AlertChangePercent:
+		return alert, hasPrevious && math.Abs(alert.ChangePercent) > rule.Threshold
+	default:
+		return models.Alert{}, false
+	}
+}
+
+// crossRate returns the rate of a currency quoted against a base currency from EUR based rates.
+func crossRate(rates map[string]float64, currency, base string) (float64, bool) {
+	rate, ok := eurRate(rates, currency)
+	if !ok {
+		return 0, false
+	}
+	baseRate, ok := eurRate(rates, base)
+	if !ok || baseRate == 0 {
+		return 0, false
+	}
+	return rate / baseRate, true
+}
+
+// eurRate returns the EUR based rate of a currency, which is 1 for EUR itself.
+func eurRate(rates map[string]float64, currency string) (float64, bool) {
+	if currency == baseCurrency {
+		return 1, rates != nil
+	}
+	rate, ok := rates[currency]
+	return rate, ok
+}
@@ -0,0 +1,254 @@
+package alert
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staticRates is a RateSource returning a fixed time series.
+type staticRates models.TimeSeries
+
+func (s staticRates) FetchRecentRates(context.Context, uint64) (models.TimeSeries, error) {
+	return models.TimeSeries(s), nil
+}
+
+// recordingNotifier records the alerts it is sent and fails while err is set.
+type recordingNotifier struct {
+	alerts []models.Alert
+	err    error
+}
+
+func (r *recordingNotifier) Notify(_ context.Context, alert models.Alert) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.alerts = append(r.alerts, alert)
+	return nil
+}
+
+func TestEvaluate(t *testing.T) {
+	latest := map[string]float64{"USD": 1.0890, "TRY": 35.20, "GBP": 0.8500}
+	previous := map[string]float64{"USD": 1.0700, "TRY": 34.90, "GBP": 0.8510}
+
+	testCases := []struct {
+		name      string
+		rule      models.AlertRule
+		previous  map[string]float64
+		triggered bool
+		rate      float64
+	}{
+		{
+			name:      "Above",
+			rule:      models.AlertRule{Currency: "TRY", Base: "EUR", Condition: models.AlertAbove, Threshold: 35},
+			previous:  previous,
+			triggered: true,
+			rate:      35.20,
+		},
+		{
+			name:     "Not below",
+			rule:     models.AlertRule{Currency: "TRY", Base: "EUR", Condition: models.AlertBelow, Threshold: 35},
+			previous: previous,
+		},
+		{
+			name:      "Crosses above",
+			rule:      models.AlertRule{Currency: "TRY", Base: "EUR", Condition: models.AlertCrossesAbove, Threshold: 35},
+			previous:  previous,
+			triggered: true,
+			rate:      35.20,
+		},
+		{
+			name:     "Already above",
+			rule:     models.AlertRule{Currency: "TRY", Base: "EUR", Condition: models.AlertCrossesAbove, Threshold: 34},
+			previous: previous,
+		},
+		{
+			name:     "Crossing needs a previous day",
+			rule:     models.AlertRule{Currency: "TRY", Base: "EUR", Condition: models.AlertCrossesAbove, Threshold: 35},
+			previous: nil,
+		},
+		{
+			name:      "Crosses below against another base",
+			rule:      models.AlertRule{Currency: "EUR", Base: "USD", Condition: models.AlertCrossesBelow, Threshold: 0.93},
+			previous:  previous,
+			triggered: true,
+			rate:      1 / 1.0890,
+		},
+		{
+			name:      "Moves more than 1.5%",
+			rule:      models.AlertRule{Currency: "USD", Base: "EUR", Condition: models.AlertChangePercent, Threshold: 1.5},
+			previous:  previous,
+			triggered: true,
+			rate:      1.0890,
+		},
+		{
+			name:     "Moves less than 1.5%",
+			rule:     models.AlertRule{Currency: "GBP", Base: "EUR", Condition: models.AlertChangePercent, Threshold: 1.5},
+			previous: previous,
+		},
+		{
+			name:     "Unknown currency",
+			rule:     models.AlertRule{Currency: "XXX", Base: "EUR", Condition: models.AlertAbove, Threshold: 0},
+			previous: previous,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			alert, triggered := evaluate(tc.rule, "2024-06-03", latest, "2024-05-31", tc.previous)
+
+			assert.Equal(t, tc.triggered, triggered)
+			if tc.triggered {
+				assert.InDelta(t, tc.rate, alert.Rate, 1e-9)
+				assert.Equal(t, "2024-06-03", alert.Date)
+			}
+		})
+	}
+}
+
+func TestServiceEvaluate(t *testing.T) {
+	ctx := context.Background()
+	rates := staticRates{
+		"2024-05-31": {"USD": 1.0700, "TRY": 34.90},
+		"2024-06-03": {"USD": 1.0890, "TRY": 35.20},
+	}
+	notifier := &recordingNotifier{}
+	service := NewService(NewMemoryStore(), rates, notifier)
+	now := time.Date(2024, 6, 3, 16, 5, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	crossing, err := service.CreateRule(ctx, models.AlertRule{
+		Currency: "TRY", Base: "EUR", Condition: models.AlertAbove, Threshold: 35, CooldownSeconds: 3600, Active: true,
+	})
+	require.NoError(t, err)
+	_, err = service.CreateRule(ctx, models.AlertRule{
+		Currency: "USD", Base: "EUR", Condition: models.AlertChangePercent, Threshold: 1.5, Active: false,
+	})
+	require.NoError(t, err)
+
+	event := models.SyncEvent{Sequence: 1, Date: "2024-06-03", Currencies: []string{"TRY", "USD"}}
+
+	sent := service.Evaluate(ctx, event)
+	require.Len(t, sent, 1, "inactive rules are skipped")
+	assert.Equal(t, crossing.ID, sent[0].Rule.ID)
+	assert.Equal(t, now, sent[0].TriggeredAt)
+	assert.Equal(t, "2024-05-31", sent[0].PreviousDate)
+
+	rule, err := service.GetRule(ctx, crossing.ID)
+	require.NoError(t, err)
+	require.NotNil(t, rule.LastTriggeredAt)
+	assert.Equal(t, now, *rule.LastTriggeredAt)
+
+	// Within the cooldown
+	now = now.Add(30 * time.Minute)
+	assert.Empty(t, service.Evaluate(ctx, event))
+
+	// Failed notifications are retried by the next evaluation.
+	now = now.Add(time.Hour)
+	notifier.err = errors.New("smtp down")
+	assert.Empty(t, service.Evaluate(ctx, event))
+	notifier.err = nil
+	assert.Len(t, service.Evaluate(ctx, event), 1)
+
+	// Syncs that did not change the latest day do not evaluate the rules.
+	now = now.Add(24 * time.Hour)
+	assert.Empty(t, service.Evaluate(ctx, models.SyncEvent{Sequence: 2, Date: "2024-05-31"}))
+	assert.Len(t, notifier.alerts, 2)
+}
+
+func TestServiceEvaluatePartialFailure(t *testing.T) {
+	ctx := context.Background()
+	rates := staticRates{
+		"2024-05-31": {"TRY": 34.90},
+		"2024-06-03": {"TRY": 35.20},
+	}
+	failing := &recordingNotifier{err: errors.New("smtp down")}
+	succeeding := &recordingNotifier{}
+	service := NewService(NewMemoryStore(), rates, MultiNotifier{failing, succeeding})
+	now := time.Date(2024, 6, 3, 16, 5, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	rule, err := service.CreateRule(ctx, models.AlertRule{
+		Currency: "TRY", Base: "EUR", Condition: models.AlertAbove, Threshold: 35, CooldownSeconds: 3600, Active: true,
+	})
+	require.NoError(t, err)
+
+	event := models.SyncEvent{Sequence: 1, Date: "2024-06-03", Currencies: []string{"TRY"}}
+	assert.Len(t, service.Evaluate(ctx, event), 1)
+
+	rule, err = service.GetRule(ctx, rule.ID)
+	require.NoError(t, err)
+	require.NotNil(t, rule.LastTriggeredAt, "the rule is marked once any notifier delivered the alert")
+
+	// The next sync within the cooldown does not send the alert again.
+	now = now.Add(30 * time.Minute)
+	assert.Empty(t, service.Evaluate(ctx, event))
+	assert.Len(t, succeeding.alerts, 1)
+}
+
+// channelNotifier sends the alerts it is sent to a channel.
+type channelNotifier chan models.Alert
+
+func (c channelNotifier) Notify(_ context.Context, alert models.Alert) error {
+	c <- alert
+	return nil
+}
+
+func TestServiceRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	rates := staticRates{
+		"2024-05-31": {"TRY": 34.90},
+		"2024-06-03": {"TRY": 35.20},
+	}
+	notifier := make(channelNotifier, 1)
+	service := NewService(NewMemoryStore(), rates, notifier)
+
+	_, err := service.CreateRule(ctx, models.AlertRule{
+		Currency: "TRY", Base: "EUR", Condition: models.AlertAbove, Threshold: 35, Active: true,
+	})
+	require.NoError(t, err)
+
+	// Notify does not wait for the evaluation, and only the latest queued event is evaluated.
+	service.Notify(models.SyncEvent{Sequence: 1, Date: "2024-05-31"})
+	service.Notify(models.SyncEvent{Sequence: 2, Date: "2024-05-31"})
+	service.Notify(models.SyncEvent{Sequence: 3, Date: "2024-06-03"})
+
+	go service.Run(ctx)
+
+	select {
+	case alert := <-notifier:
+		assert.Equal(t, "2024-06-03", alert.Date)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the queued event was not evaluated")
+	}
+}
+
+func TestServiceUpdateRule(t *testing.T) {
+	ctx := context.Background()
+	service := NewService(NewMemoryStore(), staticRates{}, &recordingNotifier{})
+
+	rule, err := service.CreateRule(ctx, models.AlertRule{
+		Currency: "USD", Base: "EUR", Condition: models.AlertAbove, Threshold: 1.1, Active: true,
+	})
+	require.NoError(t, err)
+
+	threshold := 1.2
+	active := false
+	updated, err := service.UpdateRule(ctx, rule.ID, models.AlertRuleInput{Threshold: &threshold, Active: &active})
+	require.NoError(t, err)
+	assert.InDelta(t, 1.2, updated.Threshold, 0)
+	assert.False(t, updated.Active)
+	assert.Equal(t, models.AlertAbove, updated.Condition)
+
+	_, err = service.UpdateRule(ctx, 42, models.AlertRuleInput{Active: &active})
+	require.ErrorIs(t, err, ErrRuleNotFound)
+
+	require.NoError(t, service.DeleteRule(ctx, rule.ID))
+	require.ErrorIs(t, service.DeleteRule(ctx, rule.ID), ErrRuleNotFound)
+}
@@ -0,0 +1,29 @@
+package alert
+
+import (
+	"context"
+	"time"
+
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+)
+
+// ErrRuleNotFound is returned if no alert rule has the requested ID.
+var ErrRuleNotFound = errors.New("alert rule not found")
+
+// Store persists the alert rules.
+// PostgresStore is used by the service; MemoryStore serves tests and local experiments.
+type Store interface {
+	// CreateRule stores a new rule and returns it with its ID and timestamps.
+	CreateRule(ctx context.Context, rule models.AlertRule) (models.AlertRule, error)
+	// ListRules returns the rules ordered by ID, optionally only the active ones.
+	ListRules(ctx context.Context, activeOnly bool) ([]models.AlertRule, error)
+	// GetRule returns a rule or ErrRuleNotFound.
+	GetRule(ctx context.Context, id int64) (models.AlertRule, error)
+	// UpdateRule replaces the currency, base, condition, threshold, cooldown and state of a rule.
+	UpdateRule(ctx context.Context, rule models.AlertRule) (models.AlertRule, error)
+	// DeleteRule deletes a rule.
+	DeleteRule(ctx context.Context, id int64) error
+	// MarkTriggered records when a rule was last notified, starting its cooldown.
+	MarkTriggered(ctx context.Context, id int64, at time.Time) error
+}
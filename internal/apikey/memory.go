@@ -0,0 +1,87 @@
+package apikey
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/light-bringer/rates-exchanger-service/models"
+)
+
+// MemoryStore keeps API keys in memory. It is safe for concurrent use.
+type MemoryStore struct {
+	mu     sync.Mutex
+	lastID int64
+	keys   map[int64]models.APIKey
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{keys: make(map[int64]models.APIKey)}
+}
+
+func (m *MemoryStore) CreateKey(_ context.Context, key models.APIKey) (models.APIKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lastID++
+	key.ID = m.lastID
+	key.CreatedAt = time.Now()
+	m.keys[key.ID] = key
+
+	return key, nil
+}
+
+func (m *MemoryStore) ListKeys(_ context.Context) ([]models.APIKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keys := make([]models.APIKey, 0, len(m.keys))
+	for _, key := range m.keys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+
+	return keys, nil
+}
+
+func (m *MemoryStore) GetKeyByHash(_ context.Context, hash string) (models.APIKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, key := range m.keys {
+		if key.Hash == hash {
+			return key, nil
+		}
+	}
+	return models.APIKey{}, ErrKeyNotFound
+}
+
+func (m *MemoryStore) RevokeKey(_ context.Context, id int64, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key, ok := m.keys[id]
+	if !ok {
+		return ErrKeyNotFound
+	}
+	if key.RevokedAt == nil {
+		key.RevokedAt = &at
+		m.keys[id] = key
+	}
+	return nil
+}
+
+func (m *MemoryStore) MarkUsed(_ context.Context, id int64, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key, ok := m.keys[id]
+	if !ok {
+		return ErrKeyNotFound
+	}
+	key.LastUsedAt = &at
+	m.keys[id] = key
+	return nil
+}
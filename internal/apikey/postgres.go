@@ -0,0 +1,181 @@
+package apikey
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/light-bringer/rates-exchanger-service/internal/logging"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+)
+
+// keyColumns are the columns of a key, in the order scanned by scanKey.
+//
+//nolint:gochecknoglobals // read-only column list
+var keyColumns = []string{
+	"id", "name", "prefix", "hash", "scopes", "expires_at", "last_used_at", "revoked_at", "created_at",
+}
+
+// PostgresStore stores the API keys in Postgres.
+type PostgresStore struct {
+	db        *pgxpool.Pool
+	tableName string
+}
+
+// NewPostgresStore returns a new PostgresStore using the table of the given schema.
+func NewPostgresStore(db *pgxpool.Pool, schema string) *PostgresStore {
+	return &PostgresStore{db: db, tableName: schema + ".api_keys"}
+}
+
+func (p *PostgresStore) builder() squirrel.StatementBuilderType {
+	return squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+}
+
+func (p *PostgresStore) CreateKey(ctx context.Context, key models.APIKey) (models.APIKey, error) {
+	logger := logging.FromContext(ctx)
+	query, args, err := p.builder().
+		Insert(p.tableName).
+		Columns("name", "prefix", "hash", "scopes", "expires_at").
+		Values(key.Name, key.Prefix, key.Hash, scopeStrings(key.Scopes), key.ExpiresAt).
+		Suffix("RETURNING " + strings.Join(keyColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return models.APIKey{}, errors.Wrap(err, "failed to build SQL query")
+	}
+
+	created, err := scanKey(p.db.QueryRow(ctx, query, args...))
+	if err != nil {
+		logger.Error("Failed to create API key", "error", err)
+		return models.APIKey{}, errors.Wrap(err, "failed to create API key")
+	}
+
+	return created, nil
+}
+
+func (p *PostgresStore) ListKeys(ctx context.Context) ([]models.APIKey, error) {
+	logger := logging.FromContext(ctx)
+	query, args, err := p.builder().
+		Select(keyColumns...).
+		From(p.tableName).
+		OrderBy("id ASC").
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build SQL query")
+	}
+
+	rows, err := p.db.Query(ctx, query, args...)
+	if err != nil {
+		logger.Error("Failed to execute query", "error", err)
+		return nil, errors.Wrap(err, "failed to execute query")
+	}
+	defer rows.Close()
+
+	keys := make([]models.APIKey, 0)
+	for rows.Next() {
+		key, scanErr := scanKey(rows)
+		if scanErr != nil {
+			return nil, errors.Wrap(scanErr, "failed to scan row")
+		}
+		keys = append(keys, key)
+	}
+	if err = rows.Err(); err != nil {
+		logger.Error("Failed to read rows", "error", err)
+		return nil, errors.Wrap(err, "failed to read rows")
+	}
+
+	return keys, nil
+}
+
+func (p *PostgresStore) GetKeyByHash(ctx context.Context, hash string) (models.APIKey, error) {
+	logger := logging.FromContext(ctx)
+	query, args, err := p.builder().
+		Select(keyColumns...).
+		From(p.tableName).
+		Where(squirrel.Eq{"hash": hash}).
+		ToSql()
+	if err != nil {
+		return models.APIKey{}, errors.Wrap(err, "failed to build SQL query")
+	}
+
+	key, err := scanKey(p.db.QueryRow(ctx, query, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.APIKey{}, ErrKeyNotFound
+	}
+	if err != nil {
+		logger.Error("Failed to fetch API key", "error", err)
+		return models.APIKey{}, errors.Wrap(err, "failed to fetch API key")
+	}
+
+	return key, nil
+}
+
+func (p *PostgresStore) RevokeKey(ctx context.Context, id int64, at time.Time) error {
+	logger := logging.FromContext(ctx)
+	query, args, err := p.builder().
+		Update(p.tableName).
+		Set("revoked_at", squirrel.Expr("COALESCE(revoked_at, ?)", at)).
+		Where(squirrel.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build SQL query")
+	}
+
+	res, err := p.db.Exec(ctx, query, args...)
+	if err != nil {
+		logger.Error("Failed to revoke API key", "error", err)
+		return errors.Wrap(err, "failed to revoke API key")
+	}
+	if res.RowsAffected() == 0 {
+		return ErrKeyNotFound
+	}
+
+	return nil
+}
+
+func (p *PostgresStore) MarkUsed(ctx context.Context, id int64, at time.Time) error {
+	logger := logging.FromContext(ctx)
+	query, args, err := p.builder().
+		Update(p.tableName).
+		Set("last_used_at", at).
+		Where(squirrel.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build SQL query")
+	}
+
+	res, err := p.db.Exec(ctx, query, args...)
+	if err != nil {
+		logger.Error("Failed to mark API key as used", "error", err)
+		return errors.Wrap(err, "failed to mark API key as used")
+	}
+	if res.RowsAffected() == 0 {
+		return ErrKeyNotFound
+	}
+
+	return nil
+}
+
+// scanKey scans a row of keyColumns.
+func scanKey(row pgx.Row) (models.APIKey, error) {
+	var key models.APIKey
+	var scopes []string
+	err := row.Scan(&key.ID, &key.Name, &key.Prefix, &key.Hash, &scopes, &key.ExpiresAt, &key.LastUsedAt,
+		&key.RevokedAt, &key.CreatedAt)
+	for _, scope := range scopes {
+		key.Scopes = append(key.Scopes, models.APIKeyScope(scope))
+	}
+	return key, err //nolint:wrapcheck // wrapped by the callers
+}
+
+// scopeStrings converts scopes into the strings of a TEXT[] column.
+func scopeStrings(scopes []models.APIKeyScope) []string {
+	values := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		values = append(values, string(scope))
+	}
+	return values
+}
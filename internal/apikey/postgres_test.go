@@ -0,0 +1,118 @@
+package apikey
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/light-bringer/rates-exchanger-service/internal/testdb"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestPostgresStore returns a PostgresStore on a fresh schema.
+// The test is skipped if no test database is set; see testdb.New.
+func newTestPostgresStore(t *testing.T) *PostgresStore {
+	t.Helper()
+	pool, schema := testdb.New(t)
+	return NewPostgresStore(pool, schema)
+}
+
+func TestPostgresStore(t *testing.T) {
+	store := newTestPostgresStore(t)
+	ctx := context.Background()
+	expiresAt := time.Now().UTC().Add(time.Hour).Truncate(time.Microsecond)
+
+	created, err := store.CreateKey(ctx, models.APIKey{
+		Name:      "reporting",
+		Prefix:    "rx_abcdefg",
+		Hash:      HashSecret("rx_abcdefgh"),
+		Scopes:    []models.APIKeyScope{models.ScopeRatesRead, models.ScopeAdmin},
+		ExpiresAt: &expiresAt,
+	})
+	require.NoError(t, err)
+	assert.NotZero(t, created.ID)
+	assert.Equal(t, []models.APIKeyScope{models.ScopeRatesRead, models.ScopeAdmin}, created.Scopes)
+	require.NotNil(t, created.ExpiresAt)
+	assert.True(t, expiresAt.Equal(*created.ExpiresAt))
+	assert.Nil(t, created.LastUsedAt)
+	assert.Nil(t, created.RevokedAt)
+	assert.False(t, created.CreatedAt.IsZero())
+
+	_, err = store.CreateKey(ctx, models.APIKey{
+		Name: "duplicate", Prefix: "rx_abcdefg", Hash: created.Hash, Scopes: []models.APIKeyScope{models.ScopeAdmin},
+	})
+	require.Error(t, err, "hashes are unique")
+
+	key, err := store.GetKeyByHash(ctx, HashSecret("rx_abcdefgh"))
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, key.ID)
+	assert.Equal(t, "reporting", key.Name)
+	_, err = store.GetKeyByHash(ctx, HashSecret("rx_unknown"))
+	require.ErrorIs(t, err, ErrKeyNotFound)
+
+	usedAt := time.Now().UTC().Truncate(time.Microsecond)
+	require.NoError(t, store.MarkUsed(ctx, created.ID, usedAt))
+	revokedAt := usedAt.Add(time.Minute)
+	require.NoError(t, store.RevokeKey(ctx, created.ID, revokedAt))
+	require.NoError(t, store.RevokeKey(ctx, created.ID, revokedAt.Add(time.Hour)))
+
+	keys, err := store.ListKeys(ctx)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.NotNil(t, keys[0].LastUsedAt)
+	assert.True(t, usedAt.Equal(*keys[0].LastUsedAt))
+	require.NotNil(t, keys[0].RevokedAt)
+	assert.True(t, revokedAt.Equal(*keys[0].RevokedAt), "revoking a key again keeps the first revocation")
+
+	require.ErrorIs(t, store.MarkUsed(ctx, created.ID+1, usedAt), ErrKeyNotFound)
+	require.ErrorIs(t, store.RevokeKey(ctx, created.ID+1, revokedAt), ErrKeyNotFound)
+}
+
+func TestService_AuthenticatePostgres(t *testing.T) {
+	store := newTestPostgresStore(t)
+	service := NewService(store)
+	ctx := context.Background()
+	expiresAt := time.Now().Add(time.Hour)
+
+	active, activeSecret, err := service.CreateKey(ctx, "active", []models.APIKeyScope{models.ScopeRatesRead}, nil)
+	require.NoError(t, err)
+	expiring, expiringSecret, err := service.CreateKey(ctx, "expiring",
+		[]models.APIKeyScope{models.ScopeRatesRead}, &expiresAt)
+	require.NoError(t, err)
+	revoked, revokedSecret, err := service.CreateKey(ctx, "revoked", []models.APIKeyScope{models.ScopeAdmin}, nil)
+	require.NoError(t, err)
+	require.NoError(t, service.RevokeKey(ctx, revoked.ID))
+
+	key, err := service.Authenticate(ctx, activeSecret)
+	require.NoError(t, err)
+	assert.Equal(t, active.ID, key.ID)
+	assert.Equal(t, []models.APIKeyScope{models.ScopeRatesRead}, key.Scopes)
+
+	keys, err := service.ListKeys(ctx)
+	require.NoError(t, err)
+	require.Len(t, keys, 3)
+	assert.NotNil(t, keys[0].LastUsedAt, "the use of a key is recorded")
+	assert.Nil(t, keys[1].LastUsedAt)
+
+	_, err = service.Authenticate(ctx, expiringSecret)
+	require.NoError(t, err, "keys are valid until they expire")
+
+	_, err = service.Authenticate(ctx, revokedSecret)
+	require.ErrorIs(t, err, ErrInvalidKey)
+	_, err = service.Authenticate(ctx, "rx_unknown")
+	require.ErrorIs(t, err, ErrInvalidKey)
+
+	service.now = func() time.Time { return expiresAt.Add(time.Second) }
+	_, err = service.Authenticate(ctx, expiringSecret)
+	require.ErrorIs(t, err, ErrInvalidKey, "expired keys are rejected")
+
+	keys, err = service.ListKeys(ctx)
+	require.NoError(t, err)
+	require.Len(t, keys, 3)
+	assert.Equal(t, expiring.ID, keys[1].ID)
+	assert.NotNil(t, keys[1].LastUsedAt, "expired keys are listed with their last use")
+	assert.NotNil(t, keys[2].RevokedAt)
+	assert.Nil(t, keys[2].LastUsedAt, "rejected keys are not marked as used")
+}
@@ -0,0 +1,170 @@
+// Package apikey manages the API keys clients authenticate with and checks the keys of requests.
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/light-bringer/rates-exchanger-service/internal/logging"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+)
+
+const (
+	// secretPrefix starts every secret, so that leaked keys are easy to recognize.
+	secretPrefix = "rx_"
+	// secretBytes is the number of random bytes of a secret.
+	secretBytes = 32
+	// prefixLength is the number of characters of a secret kept to identify its key.
+	prefixLength = 10
+	// usageResolution is how often the last use of a key is recorded at most, so that
+	// not every request writes to the database.
+	usageResolution = time.Minute
+)
+
+// ErrInvalidKey is returned for secrets of unknown, revoked or expired keys, which are not told apart
+// so that callers learn nothing about the keys.
+var ErrInvalidKey = errors.New("invalid API key")
+
+// Service creates, lists and revokes API keys and authenticates the secrets of requests.
+type Service struct {
+	store Store
+	now   func() time.Time
+}
+
+// NewService returns a new Service with the given store.
+func NewService(store Store) *Service {
+	return &Service{store: store, now: time.Now}
+}
+
+// CreateKey creates a key with the given name, scopes and optional expiry. It returns the key and its
+// secret, which is not stored and cannot be shown again.
+func (s *Service) CreateKey(
+	ctx context.Context,
+	name string,
+	scopes []models.APIKeyScope,
+	expiresAt *time.Time,
+) (models.APIKey, string, error) {
+	logger := logging.FromContext(ctx)
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return models.APIKey{}, "", errors.New("the name of an API key is required")
+	}
+	if len(scopes) == 0 {
+		return models.APIKey{}, "", errors.New("an API key needs at least one scope")
+	}
+	for _, scope := range scopes {
+		if !isValidScope(scope) {
+			return models.APIKey{}, "", errors.Errorf("unknown scope %q", scope)
+		}
+	}
+	if expiresAt != nil && !expiresAt.After(s.now()) {
+		return models.APIKey{}, "", errors.New("the expiry of an API key must be in the future")
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		return models.APIKey{}, "", err
+	}
+
+	created, err := s.store.CreateKey(ctx, models.APIKey{
+		Name:      name,
+		Prefix:    secret[:prefixLength],
+		Hash:      HashSecret(secret),
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return models.APIKey{}, "", err //nolint:wrapcheck // store errors are wrapped
+	}
+
+	logger.Info("API key created", "id", created.ID, "name", created.Name, "scopes", created.Scopes)
+	return created, secret, nil
+}
+
+// ListKeys returns all keys, including the revoked and expired ones.
+func (s *Service) ListKeys(ctx context.Context) ([]models.APIKey, error) {
+	return s.store.ListKeys(ctx) //nolint:wrapcheck // store errors are wrapped
+}
+
+// RevokeKey revokes a key, which is rejected from then on.
+func (s *Service) RevokeKey(ctx context.Context, id int64) error {
+	if err := s.store.RevokeKey(ctx, id, s.now()); err != nil {
+		return err //nolint:wrapcheck // store errors are wrapped
+	}
+
+	logging.FromContext(ctx).Info("API key revoked", "id", id)
+	return nil
+}
+
+// Authenticate returns the key of a secret, or ErrInvalidKey if the key is unknown, revoked or expired.
+// The last use of the key is recorded; failing to do so does not fail the authentication.
+func (s *Service) Authenticate(ctx context.Context, secret string) (models.APIKey, error) {
+	logger := logging.FromContext(ctx)
+	key, err := s.store.GetKeyByHash(ctx, HashSecret(secret))
+	if errors.Is(err, ErrKeyNotFound) {
+		return models.APIKey{}, ErrInvalidKey
+	}
+	if err != nil {
+		return models.APIKey{}, err //nolint:wrapcheck // store errors are wrapped
+	}
+
+	now := s.now()
+	if !key.IsActive(now) {
+		logger.Debug("Inactive API key rejected", "id", key.ID, "revoked_at", key.RevokedAt,
+			"expires_at", key.ExpiresAt)
+		return models.APIKey{}, ErrInvalidKey
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= usageResolution {
+		if err = s.store.MarkUsed(ctx, key.ID, now); err != nil {
+			logger.Warn("Failed to record the use of an API key", "id", key.ID, "error", err)
+		} else {
+			key.LastUsedAt = &now
+		}
+	}
+
+	return key, nil
+}
+
+// ParseScopes parses a comma separated list of scopes, such as "rates:read,admin".
+func ParseScopes(value string) ([]models.APIKeyScope, error) {
+	var scopes []models.APIKeyScope
+	for _, part := range strings.Split(value, ",") {
+		scope := models.APIKeyScope(strings.TrimSpace(part))
+		if scope == "" {
+			continue
+		}
+		if !isValidScope(scope) {
+			return nil, errors.Errorf("unknown scope %q", scope)
+		}
+		scopes = append(scopes, scope)
+	}
+	return scopes, nil
+}
+
+// HashSecret returns the hex encoded SHA-256 hash of a secret, as stored. Secrets are random,
+// so a fast hash does not make them easier to guess.
+func HashSecret(secret string) string {
+	digest := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(digest[:])
+}
+
+// isValidScope reports whether the scope is known.
+func isValidScope(scope models.APIKeyScope) bool {
+	return scope == models.ScopeRatesRead || scope == models.ScopeAdmin
+}
+
+// newSecret returns a new random secret.
+func newSecret() (string, error) {
+	buf := make([]byte, secretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", errors.Wrap(err, "failed to generate API key")
+	}
+	return secretPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
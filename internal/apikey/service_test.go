@@ -0,0 +1,146 @@
+package apikey
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_CreateKey(t *testing.T) {
+	now := time.Date(2024, time.March, 28, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	testCases := []struct {
+		name      string
+		keyName   string
+		scopes    []models.APIKeyScope
+		expiresAt *time.Time
+		err       string
+	}{
+		{
+			name:    "Without expiry",
+			keyName: "reporting",
+			scopes:  []models.APIKeyScope{models.ScopeRatesRead},
+		},
+		{
+			name:      "With expiry",
+			keyName:   "operations",
+			scopes:    []models.APIKeyScope{models.ScopeAdmin},
+			expiresAt: &future,
+		},
+		{
+			name:   "Missing name",
+			scopes: []models.APIKeyScope{models.ScopeRatesRead},
+			err:    "the name of an API key is required",
+		},
+		{
+			name:    "Missing scopes",
+			keyName: "reporting",
+			err:     "an API key needs at least one scope",
+		},
+		{
+			name:    "Unknown scope",
+			keyName: "reporting",
+			scopes:  []models.APIKeyScope{"rates:write"},
+			err:     `unknown scope "rates:write"`,
+		},
+		{
+			name:      "Expiry in the past",
+			keyName:   "reporting",
+			scopes:    []models.APIKeyScope{models.ScopeRatesRead},
+			expiresAt: &past,
+			err:       "the expiry of an API key must be in the future",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := NewMemoryStore()
+			service := NewService(store)
+			service.now = func() time.Time { return now }
+
+			key, secret, err := service.CreateKey(context.Background(), tc.keyName, tc.scopes, tc.expiresAt)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.True(t, strings.HasPrefix(secret, secretPrefix))
+			assert.Equal(t, secret[:prefixLength], key.Prefix)
+			assert.Equal(t, HashSecret(secret), key.Hash)
+			assert.NotContains(t, key.Hash, secret)
+			assert.Equal(t, tc.scopes, key.Scopes)
+			assert.Equal(t, tc.expiresAt, key.ExpiresAt)
+
+			stored, err := store.GetKeyByHash(context.Background(), HashSecret(secret))
+			require.NoError(t, err)
+			assert.Equal(t, key.ID, stored.ID)
+		})
+	}
+}
+
+func TestService_Authenticate(t *testing.T) {
+	now := time.Date(2024, time.March, 28, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	service := NewService(store)
+	service.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	expiry := now.Add(time.Hour)
+	valid, secret, err := service.CreateKey(ctx, "reporting", []models.APIKeyScope{models.ScopeRatesRead}, &expiry)
+	require.NoError(t, err)
+	_, revokedSecret, err := service.CreateKey(ctx, "old", []models.APIKeyScope{models.ScopeAdmin}, nil)
+	require.NoError(t, err)
+	keys, err := service.ListKeys(ctx)
+	require.NoError(t, err)
+	require.NoError(t, service.RevokeKey(ctx, keys[1].ID))
+
+	key, err := service.Authenticate(ctx, secret)
+	require.NoError(t, err)
+	assert.Equal(t, valid.ID, key.ID)
+	require.NotNil(t, key.LastUsedAt)
+	assert.Equal(t, now, *key.LastUsedAt)
+
+	// The last use is recorded at most once per usageResolution.
+	service.now = func() time.Time { return now.Add(time.Second) }
+	key, err = service.Authenticate(ctx, secret)
+	require.NoError(t, err)
+	assert.Equal(t, now, *key.LastUsedAt)
+
+	_, err = service.Authenticate(ctx, revokedSecret)
+	assert.ErrorIs(t, err, ErrInvalidKey)
+	_, err = service.Authenticate(ctx, "rx_unknown")
+	assert.ErrorIs(t, err, ErrInvalidKey)
+
+	service.now = func() time.Time { return expiry }
+	_, err = service.Authenticate(ctx, secret)
+	assert.ErrorIs(t, err, ErrInvalidKey)
+
+	assert.ErrorIs(t, service.RevokeKey(ctx, 42), ErrKeyNotFound)
+}
+
+func TestParseScopes(t *testing.T) {
+	scopes, err := ParseScopes("rates:read, admin,")
+	require.NoError(t, err)
+	assert.Equal(t, []models.APIKeyScope{models.ScopeRatesRead, models.ScopeAdmin}, scopes)
+
+	_, err = ParseScopes("rates:read,root")
+	assert.EqualError(t, err, `unknown scope "root"`)
+}
+
+func TestAPIKey_HasScope(t *testing.T) {
+	reader := models.APIKey{Scopes: []models.APIKeyScope{models.ScopeRatesRead}}
+	admin := models.APIKey{Scopes: []models.APIKeyScope{models.ScopeAdmin}}
+
+	assert.True(t, reader.HasScope(models.ScopeRatesRead))
+	assert.False(t, reader.HasScope(models.ScopeAdmin))
+	assert.True(t, admin.HasScope(models.ScopeRatesRead))
+	assert.True(t, admin.HasScope(models.ScopeAdmin))
+}
@@ -0,0 +1,28 @@
+package apikey
+
+import (
+	"context"
+	"time"
+
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+)
+
+// ErrKeyNotFound is returned if no API key has the requested ID or hash.
+var ErrKeyNotFound = errors.New("API key not found")
+
+// Store persists the API keys.
+// PostgresStore is used by the service; MemoryStore serves tests and local experiments.
+type Store interface {
+	// CreateKey stores a new key and returns it with its ID and creation time.
+	CreateKey(ctx context.Context, key models.APIKey) (models.APIKey, error)
+	// ListKeys returns all keys, including the revoked and expired ones, ordered by ID.
+	ListKeys(ctx context.Context) ([]models.APIKey, error)
+	// GetKeyByHash returns the key with the given hash of its secret or ErrKeyNotFound.
+	GetKeyByHash(ctx context.Context, hash string) (models.APIKey, error)
+	// RevokeKey records when a key was revoked, or returns ErrKeyNotFound. Revoking a revoked key keeps
+	// its first revocation time.
+	RevokeKey(ctx context.Context, id int64, at time.Time) error
+	// MarkUsed records when a key was last used.
+	MarkUsed(ctx context.Context, id int64, at time.Time) error
+}
@@ -0,0 +1,27 @@
+// Package currency provides the ISO 4217 currencies and the dates rates are stored for them.
+package currency
+
+import (
+	_ "embed"
+	"encoding/json"
+	"sort"
+
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+)
+
+// iso4217 lists the active ISO 4217 currencies, together with the withdrawn ones the ECB published rates for.
+//
+//go:embed iso4217.json
+var iso4217 []byte //nolint:gochecknoglobals // embedded read-only data
+
+// ISO4217 returns the embedded ISO 4217 currencies, ordered by code.
+func ISO4217() ([]models.Currency, error) {
+	var currencies []models.Currency
+	if err := json.Unmarshal(iso4217, &currencies); err != nil {
+		return nil, errors.Wrap(err, "invalid embedded ISO 4217 list")
+	}
+
+	sort.Slice(currencies, func(i, j int) bool { return currencies[i].Code < currencies[j].Code })
+	return currencies, nil
+}
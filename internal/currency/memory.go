@@ -0,0 +1,85 @@
+package currency
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/light-bringer/rates-exchanger-service/models"
+)
+
+// MemoryStore keeps currencies in memory. It is safe for concurrent use.
+// The dates rates are stored for are set with SetDataRange.
+type MemoryStore struct {
+	mu         sync.Mutex
+	currencies map[string]models.Currency
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{currencies: make(map[string]models.Currency)}
+}
+
+// SetDataRange sets the first and last publication day with rates of a stored currency.
+func (m *MemoryStore) SetDataRange(code, first, last string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if currency, ok := m.currencies[code]; ok {
+		currency.FirstDate = first
+		currency.LastDate = last
+		m.currencies[code] = currency
+	}
+}
+
+func (m *MemoryStore) Seed(_ context.Context, currencies []models.Currency) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, currency := range currencies {
+		stored := m.currencies[currency.Code]
+		currency.FirstDate = stored.FirstDate
+		currency.LastDate = stored.LastDate
+		m.currencies[currency.Code] = currency
+	}
+
+	return nil
+}
+
+func (m *MemoryStore) Codes(_ context.Context) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	codes := make([]string, 0, len(m.currencies))
+	for code := range m.currencies {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	return codes, nil
+}
+
+func (m *MemoryStore) ListCurrencies(_ context.Context) ([]models.Currency, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	currencies := make([]models.Currency, 0, len(m.currencies))
+	for _, currency := range m.currencies {
+		currencies = append(currencies, currency)
+	}
+	sort.Slice(currencies, func(i, j int) bool { return currencies[i].Code < currencies[j].Code })
+
+	return currencies, nil
+}
+
+func (m *MemoryStore) GetCurrency(_ context.Context, code string) (models.Currency, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	currency, ok := m.currencies[code]
+	if !ok {
+		return models.Currency{}, ErrCurrencyNotFound
+	}
+
+	return currency, nil
+}
@@ -0,0 +1,174 @@
+package currency
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/light-bringer/rates-exchanger-service/internal/logging"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+)
+
+const dateLayout = "2006-01-02"
+
+// PostgresStore stores the currencies in Postgres and reads their data ranges from the exchange rates.
+type PostgresStore struct {
+	db         *pgxpool.Pool
+	tableName  string
+	ratesTable string
+}
+
+// NewPostgresStore returns a new PostgresStore using the tables of the given schema.
+func NewPostgresStore(db *pgxpool.Pool, schema string) *PostgresStore {
+	return &PostgresStore{db: db, tableName: schema + ".currencies", ratesTable: schema + ".exchange_rates"}
+}
+
+func (p *PostgresStore) builder() squirrel.StatementBuilderType {
+	return squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+}
+
+func (p *PostgresStore) Seed(ctx context.Context, currencies []models.Currency) error {
+	logger := logging.FromContext(ctx)
+	if len(currencies) == 0 {
+		return nil
+	}
+
+	insert := p.builder().
+		Insert(p.tableName).
+		Columns("code", "name", "numeric_code", "minor_units", "countries", "withdrawn")
+	for _, currency := range currencies {
+		countries := currency.Countries
+		if countries == nil {
+			countries = []string{}
+		}
+		insert = insert.Values(currency.Code, currency.Name, currency.NumericCode, currency.MinorUnits, countries,
+			currency.Withdrawn)
+	}
+
+	query, args, err := insert.
+		Suffix("ON CONFLICT (code) DO UPDATE SET name = EXCLUDED.name, numeric_code = EXCLUDED.numeric_code, " +
+			"minor_units = EXCLUDED.minor_units, countries = EXCLUDED.countries, withdrawn = EXCLUDED.withdrawn").
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build SQL query")
+	}
+
+	if _, err = p.db.Exec(ctx, query, args...); err != nil {
+		logger.Error("Failed to seed currencies", "error", err)
+		return errors.Wrap(err, "failed to seed currencies")
+	}
+
+	return nil
+}
+
+func (p *PostgresStore) Codes(ctx context.Context) ([]string, error) {
+	logger := logging.FromContext(ctx)
+	query, args, err := p.builder().Select("code").From(p.tableName).OrderBy("code ASC").ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build SQL query")
+	}
+
+	rows, err := p.db.Query(ctx, query, args...)
+	if err != nil {
+		logger.Error("Failed to execute query", "error", err)
+		return nil, errors.Wrap(err, "failed to execute query")
+	}
+
+	codes, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		logger.Error("Failed to read rows", "error", err)
+		return nil, errors.Wrap(err, "failed to read rows")
+	}
+
+	return codes, nil
+}
+
+func (p *PostgresStore) ListCurrencies(ctx context.Context) ([]models.Currency, error) {
+	logger := logging.FromContext(ctx)
+	query, args, err := p.selectCurrencies().OrderBy("c.code ASC").ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build SQL query")
+	}
+
+	rows, err := p.db.Query(ctx, query, args...)
+	if err != nil {
+		logger.Error("Failed to execute query", "error", err)
+		return nil, errors.Wrap(err, "failed to execute query")
+	}
+	defer rows.Close()
+
+	currencies := make([]models.Currency, 0)
+	for rows.Next() {
+		currency, scanErr := scanCurrency(rows)
+		if scanErr != nil {
+			return nil, errors.Wrap(scanErr, "failed to scan row")
+		}
+		currencies = append(currencies, currency)
+	}
+	if err = rows.Err(); err != nil {
+		logger.Error("Failed to read rows", "error", err)
+		return nil, errors.Wrap(err, "failed to read rows")
+	}
+
+	return currencies, nil
+}
+
+func (p *PostgresStore) GetCurrency(ctx context.Context, code string) (models.Currency, error) {
+	logger := logging.FromContext(ctx)
+	query, args, err := p.selectCurrencies().Where(squirrel.Eq{"c.code": code}).ToSql()
+	if err != nil {
+		return models.Currency{}, errors.Wrap(err, "failed to build SQL query")
+	}
+
+	currency, err := scanCurrency(p.db.QueryRow(ctx, query, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.Currency{}, ErrCurrencyNotFound
+	}
+	if err != nil {
+		logger.Error("Failed to fetch currency", "error", err)
+		return models.Currency{}, errors.Wrap(err, "failed to fetch currency")
+	}
+
+	return currency, nil
+}
+
+// selectCurrencies selects the currencies joined with the first and last day of their rates.
+// The rates are quoted against EUR, so EUR has rates on every publication day.
+func (p *PostgresStore) selectCurrencies() squirrel.SelectBuilder {
+	ranges := fmt.Sprintf(
+		`SELECT currency, MIN(day) AS first_day, MAX(day) AS last_day FROM %[1]s GROUP BY currency
+		UNION ALL
+		SELECT 'EUR', MIN(day), MAX(day) FROM %[1]s`,
+		p.ratesTable,
+	)
+
+	return p.builder().
+		Select("c.code", "c.name", "c.numeric_code", "c.minor_units", "c.countries", "c.withdrawn",
+			"r.first_day", "r.last_day").
+		From(p.tableName + " AS c").
+		LeftJoin(fmt.Sprintf("(%s) AS r ON r.currency = c.code", ranges))
+}
+
+// scanCurrency scans a row selected by selectCurrencies.
+func scanCurrency(row pgx.Row) (models.Currency, error) {
+	var currency models.Currency
+	var firstDay, lastDay *time.Time
+	err := row.Scan(&currency.Code, &currency.Name, &currency.NumericCode, &currency.MinorUnits,
+		&currency.Countries, &currency.Withdrawn, &firstDay, &lastDay)
+	if err != nil {
+		return models.Currency{}, err //nolint:wrapcheck // wrapped by the callers
+	}
+
+	if firstDay != nil {
+		currency.FirstDate = firstDay.Format(dateLayout)
+	}
+	if lastDay != nil {
+		currency.LastDate = lastDay.Format(dateLayout)
+	}
+
+	return currency, nil
+}
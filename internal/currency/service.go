@@ -0,0 +1,83 @@
+package currency
+
+import (
+	"context"
+	"sync"
+
+	"github.com/light-bringer/rates-exchanger-service/internal/logging"
+	"github.com/light-bringer/rates-exchanger-service/models"
+)
+
+// Service provides the currencies and validates currency codes against the stored ones.
+// The known codes are cached by Seed and Load, so that validating codes needs no query.
+type Service struct {
+	store Store
+
+	mu    sync.RWMutex
+	codes map[string]struct{}
+}
+
+// NewService returns a new Service with the given store.
+func NewService(store Store) *Service {
+	return &Service{store: store, codes: make(map[string]struct{})}
+}
+
+// Seed stores the embedded ISO 4217 currencies and loads the known codes.
+func (s *Service) Seed(ctx context.Context) error {
+	logger := logging.FromContext(ctx)
+	currencies, err := ISO4217()
+	if err != nil {
+		return err
+	}
+
+	if err = s.store.Seed(ctx, currencies); err != nil {
+		return err //nolint:wrapcheck // store errors are wrapped
+	}
+
+	logger.Info("Currencies seeded", "count", len(currencies))
+	return s.Load(ctx)
+}
+
+// Load loads the known codes from the store.
+func (s *Service) Load(ctx context.Context) error {
+	codes, err := s.store.Codes(ctx)
+	if err != nil {
+		return err //nolint:wrapcheck // store errors are wrapped
+	}
+
+	known := make(map[string]struct{}, len(codes))
+	for _, code := range codes {
+		known[code] = struct{}{}
+	}
+
+	s.mu.Lock()
+	s.codes = known
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Unknown returns the given codes that are not known currencies, in the given order.
+func (s *Service) Unknown(codes []string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	unknown := make([]string, 0)
+	for _, code := range codes {
+		if _, ok := s.codes[code]; !ok {
+			unknown = append(unknown, code)
+		}
+	}
+
+	return unknown
+}
+
+// ListCurrencies returns all currencies ordered by code.
+func (s *Service) ListCurrencies(ctx context.Context) ([]models.Currency, error) {
+	return s.store.ListCurrencies(ctx) //nolint:wrapcheck // store errors are wrapped
+}
+
+// GetCurrency returns a currency, or ErrCurrencyNotFound.
+func (s *Service) GetCurrency(ctx context.Context, code string) (models.Currency, error) {
+	return s.store.GetCurrency(ctx, code) //nolint:wrapcheck // store errors are wrapped
+}
@@ -0,0 +1,76 @@
+package currency
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestISO4217(t *testing.T) {
+	currencies, err := ISO4217()
+	require.NoError(t, err)
+
+	codePattern := regexp.MustCompile(`^[A-Z]{3}$`)
+	numericPattern := regexp.MustCompile(`^[0-9]{3}$`)
+	codes := make(map[string]models.Currency, len(currencies))
+	for _, currency := range currencies {
+		assert.Regexp(t, codePattern, currency.Code)
+		assert.Regexp(t, numericPattern, currency.NumericCode, currency.Code)
+		assert.NotEmpty(t, currency.Name, currency.Code)
+		assert.NotContains(t, codes, currency.Code, "duplicate code")
+		codes[currency.Code] = currency
+	}
+
+	// All currencies published by the ECB, including the ones replaced by the euro.
+	ecb := []string{
+		"AUD", "BGN", "BRL", "CAD", "CHF", "CNY", "CYP", "CZK", "DKK", "EEK", "EUR", "GBP", "HKD", "HRK", "HUF",
+		"IDR", "ILS", "INR", "ISK", "JPY", "KRW", "LTL", "LVL", "MTL", "MXN", "MYR", "NOK", "NZD", "PHP", "PLN",
+		"ROL", "RON", "RUB", "SEK", "SGD", "SIT", "SKK", "THB", "TRL", "TRY", "USD", "ZAR",
+	}
+	for _, code := range ecb {
+		assert.Contains(t, codes, code)
+	}
+
+	require.NotNil(t, codes["JPY"].MinorUnits)
+	assert.Equal(t, 0, *codes["JPY"].MinorUnits)
+	assert.Nil(t, codes["XAU"].MinorUnits)
+	assert.True(t, codes["HRK"].Withdrawn)
+	assert.Contains(t, codes["EUR"].Countries, "Croatia")
+}
+
+func TestService(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	service := NewService(store)
+
+	assert.Equal(t, []string{"USD"}, service.Unknown([]string{"USD"}), "no codes are known before seeding")
+
+	require.NoError(t, service.Seed(ctx))
+	assert.Equal(t, []string{"XXX", "ABC"}, service.Unknown([]string{"USD", "XXX", "EUR", "ABC"}))
+
+	store.SetDataRange("USD", "2024-01-02", "2024-06-03")
+	usd, err := service.GetCurrency(ctx, "USD")
+	require.NoError(t, err)
+	assert.Equal(t, "US Dollar", usd.Name)
+	assert.Equal(t, "840", usd.NumericCode)
+	assert.Equal(t, "2024-01-02", usd.FirstDate)
+	assert.Equal(t, "2024-06-03", usd.LastDate)
+
+	// Seeding again keeps the data ranges.
+	require.NoError(t, service.Seed(ctx))
+	currencies, err := service.ListCurrencies(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "AED", currencies[0].Code)
+	for _, currency := range currencies {
+		if currency.Code == "USD" {
+			assert.Equal(t, "2024-06-03", currency.LastDate)
+		}
+	}
+
+	_, err = service.GetCurrency(ctx, "XXX")
+	require.ErrorIs(t, err, ErrCurrencyNotFound)
+}
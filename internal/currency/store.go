@@ -0,0 +1,24 @@
+package currency
+
+import (
+	"context"
+
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+)
+
+// ErrCurrencyNotFound is returned if the requested code is not a known currency.
+var ErrCurrencyNotFound = errors.New("currency not found")
+
+// Store persists the currencies.
+// PostgresStore is used by the service; MemoryStore serves tests and local experiments.
+type Store interface {
+	// Seed stores the given currencies, replacing the fields of those already stored.
+	Seed(ctx context.Context, currencies []models.Currency) error
+	// Codes returns the codes of the stored currencies.
+	Codes(ctx context.Context) ([]string, error)
+	// ListCurrencies returns the stored currencies ordered by code, with the dates rates are stored for.
+	ListCurrencies(ctx context.Context) ([]models.Currency, error)
+	// GetCurrency returns a currency with the dates rates are stored for, or ErrCurrencyNotFound.
+	GetCurrency(ctx context.Context, code string) (models.Currency, error)
+}
@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/light-bringer/rates-exchanger-service/internal/alert"
+	"github.com/light-bringer/rates-exchanger-service/internal/params"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+)
+
+// CreateAlertRule handles requests to create an alert rule. Rules are active and quoted against EUR by default.
+func (h *Handler) CreateAlertRule(w http.ResponseWriter, r *http.Request) {
+	var input models.AlertRuleInput
+	if err := decodeBody(w, r, &input); err != nil {
+		invalidBody(w, r, err)
+		return
+	}
+
+	switch {
+	case input.Currency == nil:
+		badRequest(w, r, paramErrorf("currency", "currency is required"))
+		return
+	case input.Condition == nil:
+		badRequest(w, r, paramErrorf("condition", "condition is required"))
+		return
+	case input.Threshold == nil:
+		badRequest(w, r, paramErrorf("threshold", "threshold is required"))
+		return
+	}
+	if err := validateAlertRuleInput(&input); err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	rule := models.AlertRule{
+		Currency:  *input.Currency,
+		Base:      baseCurrency,
+		Condition: *input.Condition,
+		Threshold: *input.Threshold,
+		Active:    true,
+	}
+	if input.Base != nil {
+		rule.Base = *input.Base
+	}
+	if input.CooldownSeconds != nil {
+		rule.CooldownSeconds = *input.CooldownSeconds
+	}
+	if input.Active != nil {
+		rule.Active = *input.Active
+	}
+	if rule.Currency == rule.Base {
+		badRequest(w, r, paramErrorf("base", "base must differ from the currency %s", rule.Currency))
+		return
+	}
+
+	created, err := h.alerts.CreateRule(r.Context(), rule)
+	if err != nil {
+		alertError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", "/alert-rules/"+strconv.FormatInt(created.ID, 10))
+	writeJSONStatus(w, http.StatusCreated, created)
+}
+
+// ListAlertRules handles requests for all alert rules.
+func (h *Handler) ListAlertRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := h.alerts.ListRules(r.Context())
+	if err != nil {
+		alertError(w, r, err)
+		return
+	}
+
+	writeJSON(w, map[string]interface{}{"alert_rules": rules})
+}
+
+// GetAlertRule handles requests for an alert rule.
+func (h *Handler) GetAlertRule(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDPath(r, "id")
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	rule, err := h.alerts.GetRule(r.Context(), id)
+	if err != nil {
+		alertError(w, r, err)
+		return
+	}
+
+	writeJSON(w, rule)
+}
+
+// UpdateAlertRule handles requests to change some fields of an alert rule.
+func (h *Handler) UpdateAlertRule(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDPath(r, "id")
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	var input models.AlertRuleInput
+	if err = decodeBody(w, r, &input); err != nil {
+		invalidBody(w, r, err)
+		return
+	}
+	if err = validateAlertRuleInput(&input); err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	rule, err := h.alerts.UpdateRule(r.Context(), id, input)
+	if err != nil {
+		alertError(w, r, err)
+		return
+	}
+
+	writeJSON(w, rule)
+}
+
+// DeleteAlertRule handles requests to delete an alert rule.
+func (h *Handler) DeleteAlertRule(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDPath(r, "id")
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	if err = h.alerts.DeleteRule(r.Context(), id); err != nil {
+		alertError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// validateAlertRuleInput validates the fields given to create or update an alert rule
+// and normalizes the currency codes to upper case.
+func validateAlertRuleInput(input *models.AlertRuleInput) error {
+	codes := []struct {
+		name string
+		code *string
+	}{{"currency", input.Currency}, {"base", input.Base}}
+	for _, field := range codes {
+		if field.code == nil {
+			continue
+		}
+		*field.code = strings.ToUpper(strings.TrimSpace(*field.code))
+		if !params.IsCurrencyCode(*field.code) {
+			return paramErrorf(field.name, "invalid %s %q", field.name, *field.code)
+		}
+	}
+
+	if input.Condition != nil {
+		switch *input.Condition {
+		case models.AlertAbove, models.AlertBelow, models.AlertCrossesAbove, models.AlertCrossesBelow,
+			models.AlertChangePercent:
+		default:
+			return paramErrorf("condition", "invalid condition %q, expected above, below, crosses_above, "+
+				"crosses_below or change_percent", *input.Condition)
+		}
+	}
+
+	if input.Threshold != nil && *input.Threshold <= 0 {
+		return paramErrorf("threshold", "threshold must be positive")
+	}
+	if input.CooldownSeconds != nil && *input.CooldownSeconds < 0 {
+		return paramErrorf("cooldown_seconds", "cooldown_seconds must not be negative")
+	}
+
+	return nil
+}
+
+// alertError writes the problem matching an error returned by the alert service.
+func alertError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, alert.ErrRuleNotFound) {
+		writeProblem(w, r, http.StatusNotFound, models.ErrorCodeNotFound, "", err.Error())
+		return
+	}
+	serviceError(w, r, err, "")
+}
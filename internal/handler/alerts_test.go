@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/light-bringer/rates-exchanger-service/internal/alert"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertRuleRoutes(t *testing.T) {
+	alerts := alert.NewService(alert.NewMemoryStore(), nil, alert.LogNotifier{})
+	routes := NewHandler(nil, models.APIConfig{SpecValidation: models.ValidationStrict}).
+		WithAlerts(alerts).
+		Routes()
+
+	// Create
+	rec := serveJSON(t, routes, http.MethodPost, "/alert-rules",
+		`{"currency":"try","condition":"crosses_above","threshold":35,"cooldown_seconds":3600}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var created models.AlertRule
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, "TRY", created.Currency)
+	assert.Equal(t, "EUR", created.Base)
+	assert.True(t, created.Active)
+	id := strconv.FormatInt(created.ID, 10)
+	assert.Equal(t, "/alert-rules/"+id, rec.Header().Get("Location"))
+
+	// Read
+	rec = serveJSON(t, routes, http.MethodGet, "/alert-rules/"+id, "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), `"condition":"crosses_above"`)
+
+	rec = serveJSON(t, routes, http.MethodGet, "/alert-rules", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var list struct {
+		AlertRules []models.AlertRule `json:"alert_rules"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	require.Len(t, list.AlertRules, 1)
+
+	// Update
+	rec = serveJSON(t, routes, http.MethodPatch, "/alert-rules/"+id, `{"threshold":36,"active":false}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var updated models.AlertRule
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &updated))
+	assert.InDelta(t, 36, updated.Threshold, 0)
+	assert.False(t, updated.Active)
+	assert.Equal(t, models.AlertCrossesAbove, updated.Condition)
+
+	// Delete
+	rec = serveJSON(t, routes, http.MethodDelete, "/alert-rules/"+id, "")
+	require.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+	rec = serveJSON(t, routes, http.MethodGet, "/alert-rules/"+id, "")
+	require.Equal(t, http.StatusNotFound, rec.Code, rec.Body.String())
+	assert.Equal(t, models.ErrorCodeNotFound, decodeProblem(t, rec).Code)
+}
+
+func TestAlertRuleRoutesProblems(t *testing.T) {
+	alerts := alert.NewService(alert.NewMemoryStore(), nil, alert.LogNotifier{})
+	routes := NewHandler(nil, models.APIConfig{}).WithAlerts(alerts).Routes()
+
+	testCases := []struct {
+		name           string
+		method         string
+		target         string
+		body           string
+		expectedStatus int
+		expectedCode   models.ErrorCode
+		expectedParam  string
+	}{
+		{
+			name:           "Missing currency",
+			method:         http.MethodPost,
+			target:         "/alert-rules",
+			body:           `{"condition":"above","threshold":1}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   models.ErrorCodeInvalidParameter,
+			expectedParam:  "currency",
+		},
+		{
+			name:           "Missing threshold",
+			method:         http.MethodPost,
+			target:         "/alert-rules",
+			body:           `{"currency":"USD","condition":"above"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   models.ErrorCodeInvalidParameter,
+			expectedParam:  "threshold",
+		},
+		{
+			name:           "Invalid currency",
+			method:         http.MethodPost,
+			target:         "/alert-rules",
+			body:           `{"currency":"US","condition":"above","threshold":1}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   models.ErrorCodeInvalidParameter,
+			expectedParam:  "currency",
+		},
+		{
+			name:           "Same currency and base",
+			method:         http.MethodPost,
+			target:         "/alert-rules",
+			body:           `{"currency":"EUR","condition":"above","threshold":1}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   models.ErrorCodeInvalidParameter,
+			expectedParam:  "base",
+		},
+		{
+			name:           "Invalid condition",
+			method:         http.MethodPost,
+			target:         "/alert-rules",
+			body:           `{"currency":"USD","condition":"equals","threshold":1}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   models.ErrorCodeInvalidParameter,
+			expectedParam:  "condition",
+		},
+		{
+			name:           "Negative threshold",
+			method:         http.MethodPatch,
+			target:         "/alert-rules/1",
+			body:           `{"threshold":-1}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   models.ErrorCodeInvalidParameter,
+			expectedParam:  "threshold",
+		},
+		{
+			name:           "Negative cooldown",
+			method:         http.MethodPatch,
+			target:         "/alert-rules/1",
+			body:           `{"cooldown_seconds":-60}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   models.ErrorCodeInvalidParameter,
+			expectedParam:  "cooldown_seconds",
+		},
+		{
+			name:           "Unknown field",
+			method:         http.MethodPost,
+			target:         "/alert-rules",
+			body:           `{"currency":"USD","condition":"above","threshold":1,"email":"fx@example.com"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   models.ErrorCodeInvalidBody,
+		},
+		{
+			name:           "Unknown rule",
+			method:         http.MethodPatch,
+			target:         "/alert-rules/42",
+			body:           `{"active":false}`,
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   models.ErrorCodeNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := serveJSON(t, routes, tc.method, tc.target, tc.body)
+
+			require.Equal(t, tc.expectedStatus, rec.Code, rec.Body.String())
+			problem := decodeProblem(t, rec)
+			assert.Equal(t, tc.expectedCode, problem.Code)
+			assert.Equal(t, tc.expectedParam, problem.Param)
+		})
+	}
+}
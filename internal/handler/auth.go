@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/light-bringer/rates-exchanger-service/internal/apikey"
+	"github.com/light-bringer/rates-exchanger-service/internal/logging"
+	"github.com/light-bringer/rates-exchanger-service/internal/ratelimit"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+)
+
+const (
+	apiKeyHeader        = "X-API-Key"
+	authorizationHeader = "Authorization"
+	bearerPrefix        = "Bearer "
+)
+
+// apiKeyContextKey is the context key of the authenticated API key of a request.
+type apiKeyContextKey struct{}
+
+// adminPaths are the path prefixes of the routes that require the admin scope; all other routes
+// require the rates:read scope.
+//
+//nolint:gochecknoglobals // read-only path list
+var adminPaths = []string{"/webhooks", "/alert-rules", "/metrics"}
+
+// withAuthentication requires an API key with the scope of the route on every request but those of
+// the anonymous routes. Requests without a valid key are answered with a 401, and requests whose key
+// lacks the scope with a 403; both answers are rate limited like the routes. The request context carries
+// the key, and the request logger its ID, from then on.
+func (h *Handler) withAuthentication(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h.isAnonymous(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		secret := requestAPIKey(r)
+		if secret == "" {
+			h.reject(w, r, func(w http.ResponseWriter, r *http.Request) {
+				unauthorized(w, r, "An API key is required, sent as a Bearer token or in the X-API-Key header")
+			})
+			return
+		}
+
+		key, err := h.keys.Authenticate(r.Context(), secret)
+		if errors.Is(err, apikey.ErrInvalidKey) {
+			h.reject(w, r, func(w http.ResponseWriter, r *http.Request) {
+				unauthorized(w, r, "The API key is unknown, revoked or expired")
+			})
+			return
+		}
+		if err != nil {
+			writeProblem(w, r, http.StatusInternalServerError, models.ErrorCodeInternal, "", "")
+			return
+		}
+
+		logger := requestLogger(r).With("api_key_id", key.ID)
+		r = r.WithContext(logging.NewContext(context.WithValue(r.Context(), apiKeyContextKey{}, key), logger))
+
+		scope := requiredScope(r.URL.Path)
+		if !key.HasScope(scope) {
+			h.reject(w, r, func(w http.ResponseWriter, r *http.Request) {
+				writeProblem(w, r, http.StatusForbidden, models.ErrorCodeForbidden, "",
+					"The API key lacks the "+string(scope)+" scope")
+			})
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// reject answers a request that failed authentication. The answer is rate limited, if rate limiting is
+// enabled, by the IP address of the client, or by the key if it lacks the scope.
+func (h *Handler) reject(w http.ResponseWriter, r *http.Request, answer http.HandlerFunc) {
+	if h.limiter == nil {
+		answer(w, r)
+		return
+	}
+	h.withRateLimit(answer).ServeHTTP(w, r)
+}
+
+// authenticatedKey returns the API key the request was authenticated with, if any.
+func authenticatedKey(ctx context.Context) (models.APIKey, bool) {
+	key, ok := ctx.Value(apiKeyContextKey{}).(models.APIKey)
+	return key, ok
+}
+
+// isAnonymous reports whether the path belongs to a route served without an API key.
+func (h *Handler) isAnonymous(path string) bool {
+	for _, prefix := range h.anonymous {
+		if ratelimit.MatchesPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// requiredScope returns the scope an API key needs for the route of the path.
+func requiredScope(path string) models.APIKeyScope {
+	for _, prefix := range adminPaths {
+		if ratelimit.MatchesPrefix(path, prefix) {
+			return models.ScopeAdmin
+		}
+	}
+	return models.ScopeRatesRead
+}
+
+// requestAPIKey returns the API key of the request, sent in the X-API-Key header or as a Bearer token,
+// or an empty string if it sent none.
+func requestAPIKey(r *http.Request) string {
+	if key := r.Header.Get(apiKeyHeader); key != "" {
+		return key
+	}
+	if authorization := r.Header.Get(authorizationHeader); strings.HasPrefix(authorization, bearerPrefix) {
+		return strings.TrimSpace(strings.TrimPrefix(authorization, bearerPrefix))
+	}
+	return ""
+}
+
+// unauthorized writes a 401 problem with the challenge of the Bearer scheme.
+func unauthorized(w http.ResponseWriter, r *http.Request, detail string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="rates"`)
+	writeProblem(w, r, http.StatusUnauthorized, models.ErrorCodeUnauthorized, "", detail)
+}
@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/light-bringer/rates-exchanger-service/internal/apikey"
+	"github.com/light-bringer/rates-exchanger-service/internal/metrics"
+	"github.com/light-bringer/rates-exchanger-service/internal/ratelimit"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthentication(t *testing.T) {
+	ctx := context.Background()
+	keys := apikey.NewService(apikey.NewMemoryStore())
+	_, reader, err := keys.CreateKey(ctx, "reporting", []models.APIKeyScope{models.ScopeRatesRead}, nil)
+	require.NoError(t, err)
+	_, admin, err := keys.CreateKey(ctx, "operations", []models.APIKeyScope{models.ScopeAdmin}, nil)
+	require.NoError(t, err)
+	revoked, revokedSecret, err := keys.CreateKey(ctx, "old", []models.APIKeyScope{models.ScopeAdmin}, nil)
+	require.NoError(t, err)
+	require.NoError(t, keys.RevokeKey(ctx, revoked.ID))
+
+	routes := NewHandler(nil, models.APIConfig{SpecValidation: models.ValidationStrict}).
+		WithMetrics(metrics.NewMetrics()).
+		WithAuthentication(keys, []string{"/health", "/openapi.yaml"}).
+		Routes()
+
+	testCases := []struct {
+		name   string
+		target string
+		header http.Header
+		status int
+		code   models.ErrorCode
+	}{
+		{
+			name:   "Anonymous route",
+			target: "/health/live",
+			status: http.StatusOK,
+		},
+		{
+			name:   "Missing key",
+			target: "/openapi.json",
+			status: http.StatusUnauthorized,
+			code:   models.ErrorCodeUnauthorized,
+		},
+		{
+			name:   "Unknown key",
+			target: "/openapi.json",
+			header: http.Header{"X-Api-Key": {"rx_unknown"}},
+			status: http.StatusUnauthorized,
+			code:   models.ErrorCodeUnauthorized,
+		},
+		{
+			name:   "Revoked key",
+			target: "/openapi.json",
+			header: http.Header{"Authorization": {"Bearer " + revokedSecret}},
+			status: http.StatusUnauthorized,
+			code:   models.ErrorCodeUnauthorized,
+		},
+		{
+			name:   "Key header",
+			target: "/openapi.json",
+			header: http.Header{"X-Api-Key": {reader}},
+			status: http.StatusOK,
+		},
+		{
+			name:   "Bearer token",
+			target: "/openapi.json",
+			header: http.Header{"Authorization": {"Bearer " + reader}},
+			status: http.StatusOK,
+		},
+		{
+			name:   "Missing admin scope",
+			target: "/metrics",
+			header: http.Header{"X-Api-Key": {reader}},
+			status: http.StatusForbidden,
+			code:   models.ErrorCodeForbidden,
+		},
+		{
+			name:   "Admin scope",
+			target: "/metrics",
+			header: http.Header{"X-Api-Key": {admin}},
+			status: http.StatusOK,
+		},
+		{
+			name:   "Admin scope grants rates:read",
+			target: "/openapi.json",
+			header: http.Header{"X-Api-Key": {admin}},
+			status: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			for name := range tc.header {
+				req.Header.Set(name, tc.header.Get(name))
+			}
+			rec := httptest.NewRecorder()
+			routes.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.status, rec.Code, rec.Body.String())
+			if tc.code == "" {
+				return
+			}
+			assert.Equal(t, tc.code, decodeProblem(t, rec).Code)
+			if tc.status == http.StatusUnauthorized {
+				assert.Equal(t, `Bearer realm="rates"`, rec.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestAuthenticationRateLimit(t *testing.T) {
+	ctx := context.Background()
+	keys := apikey.NewService(apikey.NewMemoryStore())
+	_, reader, err := keys.CreateKey(ctx, "reporting", []models.APIKeyScope{models.ScopeRatesRead}, nil)
+	require.NoError(t, err)
+	limiter, err := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), models.RateLimitConfig{
+		Groups: []models.RateLimitGroup{{Name: "docs", Paths: []string{"/openapi.json"}, Requests: 2, Period: time.Minute}},
+	})
+	require.NoError(t, err)
+
+	routes := NewHandler(nil, models.APIConfig{SpecValidation: models.ValidationStrict}).
+		WithRateLimit(limiter).
+		WithAuthentication(keys, nil).
+		Routes()
+
+	serve := func(key string) int {
+		req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+		req.Header.Set("X-Api-Key", key)
+		rec := httptest.NewRecorder()
+		routes.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// Invalid keys are limited by the address of the client, so that guessing keys is throttled.
+	for i := range 2 {
+		assert.Equal(t, http.StatusUnauthorized, serve(fmt.Sprintf("rx_guess%d", i)))
+	}
+	for i := range 3 {
+		assert.Equal(t, http.StatusTooManyRequests, serve(fmt.Sprintf("rx_guess%d", i)))
+	}
+
+	// Valid keys have their own bucket.
+	assert.Equal(t, http.StatusOK, serve(reader))
+	assert.Equal(t, http.StatusOK, serve(reader))
+	assert.Equal(t, http.StatusTooManyRequests, serve(reader))
+}
+
+func TestRequiredScope(t *testing.T) {
+	assert.Equal(t, models.ScopeRatesRead, requiredScope("/rates/latest"))
+	assert.Equal(t, models.ScopeRatesRead, requiredScope("/webhooksx"))
+	assert.Equal(t, models.ScopeAdmin, requiredScope("/webhooks"))
+	assert.Equal(t, models.ScopeAdmin, requiredScope("/webhooks/1/deliveries"))
+	assert.Equal(t, models.ScopeAdmin, requiredScope("/alert-rules/1"))
+	assert.Equal(t, models.ScopeAdmin, requiredScope("/metrics"))
+}
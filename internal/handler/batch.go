@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/light-bringer/rates-exchanger-service/internal/params"
+	"github.com/light-bringer/rates-exchanger-service/models"
+)
+
+// GetRatesBatch handles requests for the exchange rates of many dates at once.
+// Each date is resolved like in GetExchangeRate, and all dates are read with a single query.
+func (h *Handler) GetRatesBatch(w http.ResponseWriter, r *http.Request) {
+	var request models.BatchRateRequest
+	if err := decodeBody(w, r, &request); err != nil {
+		invalidBody(w, r, err)
+		return
+	}
+
+	if len(request.Items) == 0 {
+		badRequest(w, r, paramErrorf("items", "items must not be empty"))
+		return
+	}
+	if len(request.Items) > h.config.MaxBatchItems {
+		writeProblem(w, r, http.StatusBadRequest, models.ErrorCodeTooManyItems, "items",
+			fmt.Sprintf("The batch exceeds the maximum of %d items.", h.config.MaxBatchItems))
+		return
+	}
+
+	if err := h.validateBatchRequest(&request); err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	present, err := parsePresentation(r)
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	results, err := h.service.FetchRatesForDates(r.Context(), request.Items, request.Base, request.Fallback)
+	if err != nil {
+		serviceError(w, r, err, "base")
+		return
+	}
+	for i := range results {
+		results[i].Rates = present.rates(results[i].Rates)
+	}
+
+	writeJSON(w, map[string]interface{}{
+		"base":    request.Base,
+		"results": results,
+	})
+}
+
+// validateBatchRequest validates a batch rate request and normalizes it: the currency codes are upper-cased,
+// duplicate symbols are removed, and the base and fallback default to EUR and the previous publication day.
+func (h *Handler) validateBatchRequest(request *models.BatchRateRequest) error {
+	base, err := params.ParseBase(request.Base)
+	if err != nil {
+		return &paramError{param: "base", err: err}
+	}
+	request.Base = base
+
+	request.Fallback = models.Fallback(strings.ToLower(string(request.Fallback)))
+	switch request.Fallback {
+	case "":
+		request.Fallback = models.FallbackPrevious
+	case models.FallbackPrevious, models.FallbackNext, models.FallbackNone:
+	default:
+		return paramErrorf("fallback", "invalid fallback %q", request.Fallback)
+	}
+
+	for i := range request.Items {
+		item := &request.Items[i]
+
+		if _, err := params.ParseDate("date", item.Date); err != nil {
+			return &paramError{param: fmt.Sprintf("items[%d].date", i), err: err}
+		}
+
+		if item.Symbols == nil {
+			continue
+		}
+		param := fmt.Sprintf("items[%d].symbols", i)
+		symbols, err := normalizeSymbols(param, item.Symbols)
+		if err != nil {
+			return err
+		}
+		if err = h.checkKnownSymbols(param, symbols); err != nil {
+			return err
+		}
+		item.Symbols = symbols
+	}
+
+	return nil
+}
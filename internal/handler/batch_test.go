@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/light-bringer/rates-exchanger-service/internal/currency"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateBatchRequest(t *testing.T) {
+	request := models.BatchRateRequest{
+		Items: []models.BatchRateItem{
+			{Date: "2024-03-30", Symbols: []string{"usd", " gbp", "USD"}},
+			{Date: "2024-04-02"},
+		},
+	}
+
+	require.NoError(t, NewHandler(nil, models.APIConfig{}).validateBatchRequest(&request))
+
+	assert.Equal(t, "EUR", request.Base)
+	assert.Equal(t, models.FallbackPrevious, request.Fallback)
+	assert.Equal(t, []string{"USD", "GBP"}, request.Items[0].Symbols)
+	assert.Nil(t, request.Items[1].Symbols)
+}
+
+func TestGetRatesBatchProblems(t *testing.T) {
+	currencies := currency.NewService(currency.NewMemoryStore())
+	require.NoError(t, currencies.Seed(context.Background()))
+	routes := NewHandler(nil, models.APIConfig{MaxBatchItems: 2}).WithCurrencies(currencies).Routes()
+
+	testCases := []struct {
+		name          string
+		body          string
+		expectedCode  models.ErrorCode
+		expectedParam string
+	}{
+		{
+			name:          "No items",
+			body:          `{"items":[]}`,
+			expectedCode:  models.ErrorCodeInvalidParameter,
+			expectedParam: "items",
+		},
+		{
+			name:          "Too many items",
+			body:          `{"items":[{"date":"2024-03-28"},{"date":"2024-03-29"},{"date":"2024-03-30"}]}`,
+			expectedCode:  models.ErrorCodeTooManyItems,
+			expectedParam: "items",
+		},
+		{
+			name:          "Invalid date",
+			body:          `{"items":[{"date":"2024-03-28"},{"date":"2024-02-30"}]}`,
+			expectedCode:  models.ErrorCodeInvalidParameter,
+			expectedParam: "items[1].date",
+		},
+		{
+			name:          "Invalid symbol",
+			body:          `{"items":[{"date":"2024-03-28","symbols":["US"]}]}`,
+			expectedCode:  models.ErrorCodeInvalidParameter,
+			expectedParam: "items[0].symbols",
+		},
+		{
+			name:          "Unknown symbol",
+			body:          `{"items":[{"date":"2024-03-28"},{"date":"2024-03-29","symbols":["USD","XXX"]}]}`,
+			expectedCode:  models.ErrorCodeUnknownCurrency,
+			expectedParam: "items[1].symbols",
+		},
+		{
+			name:          "Invalid base",
+			body:          `{"base":"EURO","items":[{"date":"2024-03-28"}]}`,
+			expectedCode:  models.ErrorCodeInvalidParameter,
+			expectedParam: "base",
+		},
+		{
+			name:          "Invalid fallback",
+			body:          `{"fallback":"nearest","items":[{"date":"2024-03-28"}]}`,
+			expectedCode:  models.ErrorCodeInvalidParameter,
+			expectedParam: "fallback",
+		},
+		{
+			name:         "Unknown field",
+			body:         `{"dates":["2024-03-28"]}`,
+			expectedCode: models.ErrorCodeInvalidBody,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := serveJSON(t, routes, http.MethodPost, "/rates/batch", tc.body)
+
+			require.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+			problem := decodeProblem(t, rec)
+			assert.Equal(t, tc.expectedCode, problem.Code)
+			assert.Equal(t, tc.expectedParam, problem.Param)
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/light-bringer/rates-exchanger-service/models"
+)
+
+// notModified sets the caching headers of a rate response from the current data version and
+// writes a 304 if the client's cached representation is still current.
+// The lastDate is the last day the response covers, or empty if it follows the latest day;
+// responses that only cover days before the latest publication day are cached for longer.
+// The function reports whether the 304 was written. If the data version cannot be read,
+// the response is served without validators.
+func (h *Handler) notModified(w http.ResponseWriter, r *http.Request, format outputFormat, lastDate string) bool {
+	version, err := h.service.FetchDataVersion(r.Context())
+	if err != nil {
+		requestLogger(r).Error("Failed to fetch data version, serving without validators", "error", err)
+		return false
+	}
+
+	maxAge := h.config.CacheMaxAge
+	if lastDate != "" && version.LatestDay != "" && lastDate < version.LatestDay {
+		maxAge = h.config.HistoricalCacheMaxAge
+	}
+
+	etag := entityTag(version, format)
+	w.Header().Set(etagHeader, etag)
+	if !version.ModifiedAt.IsZero() {
+		w.Header().Set(lastModifiedHeader, version.ModifiedAt.UTC().Format(http.TimeFormat))
+	}
+	h.setCacheControl(w, r, maxAge)
+
+	if !isNotModified(r, etag, version.ModifiedAt) {
+		return false
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// setCacheControl sets the Cache-Control header of a rate response. Responses to requests that need
+// an API key are private, so that shared caches do not serve them to clients without a key, and vary
+// by the headers carrying the key.
+func (h *Handler) setCacheControl(w http.ResponseWriter, r *http.Request, maxAge time.Duration) {
+	visibility := "public"
+	if h.keys != nil && !h.isAnonymous(r.URL.Path) {
+		visibility = "private"
+		w.Header().Add("Vary", authorizationHeader+", "+apiKeyHeader)
+	}
+	w.Header().Set(cacheControlHeader, fmt.Sprintf("%s, max-age=%d", visibility, int(maxAge.Seconds())))
+}
+
+// entityTag returns the ETag of a representation of the given data version.
+// The format is part of the tag, since JSON, CSV and XML representations share a URL.
+func entityTag(version models.DataVersion, format outputFormat) string {
+	return fmt.Sprintf(`"%d-%s-%s"`, version.Sequence, version.LatestDay, format)
+}
+
+// isNotModified evaluates the conditional headers of a GET request against the current validators.
+// If-None-Match takes precedence over If-Modified-Since, as required by RFC 9110.
+func isNotModified(r *http.Request, etag string, modifiedAt time.Time) bool {
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		for _, candidate := range strings.Split(ifNoneMatch, ",") {
+			candidate = strings.TrimSpace(candidate)
+			// GET uses the weak comparison, so a weak tag matches its strong counterpart.
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	ifModifiedSince := r.Header.Get("If-Modified-Since")
+	if ifModifiedSince == "" || modifiedAt.IsZero() {
+		return false
+	}
+
+	since, err := http.ParseTime(ifModifiedSince)
+	if err != nil {
+		return false
+	}
+
+	// Last-Modified has a resolution of one second.
+	return !modifiedAt.Truncate(time.Second).After(since)
+}
+
+// clearValidators removes the caching headers of a response that turned out not to be a representation
+// of the rates, such as a problem.
+func clearValidators(w http.ResponseWriter) {
+	w.Header().Del(etagHeader)
+	w.Header().Del(lastModifiedHeader)
+	w.Header().Del(cacheControlHeader)
+}
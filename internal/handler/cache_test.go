@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/light-bringer/rates-exchanger-service/internal/apikey"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntityTag(t *testing.T) {
+	version := models.DataVersion{LatestDay: "2024-03-28", Sequence: 42}
+
+	assert.Equal(t, `"42-2024-03-28-json"`, entityTag(version, formatJSON))
+	assert.NotEqual(t, entityTag(version, formatJSON), entityTag(version, formatCSV))
+}
+
+func TestIsNotModified(t *testing.T) {
+	etag := `"42-2024-03-28-json"`
+	modifiedAt := time.Date(2024, 3, 28, 16, 5, 30, 500, time.UTC)
+
+	testCases := []struct {
+		name     string
+		headers  map[string]string
+		expected bool
+	}{
+		{name: "No conditions", expected: false},
+		{name: "Matching ETag", headers: map[string]string{"If-None-Match": etag}, expected: true},
+		{name: "Matching weak ETag", headers: map[string]string{"If-None-Match": "W/" + etag}, expected: true},
+		{name: "ETag in list", headers: map[string]string{"If-None-Match": `"41-2024-03-27-json", ` + etag}, expected: true},
+		{name: "Wildcard", headers: map[string]string{"If-None-Match": "*"}, expected: true},
+		{name: "Stale ETag", headers: map[string]string{"If-None-Match": `"41-2024-03-27-json"`}, expected: false},
+		{
+			name:     "Not modified since",
+			headers:  map[string]string{"If-Modified-Since": modifiedAt.Format(http.TimeFormat)},
+			expected: true,
+		},
+		{
+			name:     "Modified since",
+			headers:  map[string]string{"If-Modified-Since": modifiedAt.Add(-time.Hour).Format(http.TimeFormat)},
+			expected: false,
+		},
+		{
+			name: "ETag takes precedence",
+			headers: map[string]string{
+				"If-None-Match":     `"41-2024-03-27-json"`,
+				"If-Modified-Since": modifiedAt.Format(http.TimeFormat),
+			},
+			expected: false,
+		},
+		{name: "Invalid date", headers: map[string]string{"If-Modified-Since": "yesterday"}, expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/rates/latest", nil)
+			for name, value := range tc.headers {
+				req.Header.Set(name, value)
+			}
+
+			assert.Equal(t, tc.expected, isNotModified(req, etag, modifiedAt))
+		})
+	}
+}
+
+func TestSetCacheControl(t *testing.T) {
+	open := NewHandler(nil, models.APIConfig{})
+	authenticated := NewHandler(nil, models.APIConfig{}).
+		WithAuthentication(apikey.NewService(apikey.NewMemoryStore()), []string{"/health", "/rates/public"})
+
+	testCases := []struct {
+		name         string
+		handler      *Handler
+		target       string
+		cacheControl string
+		vary         []string
+	}{
+		{name: "Without authentication", handler: open, target: "/rates/latest", cacheControl: "public, max-age=60"},
+		{
+			name:         "With authentication",
+			handler:      authenticated,
+			target:       "/rates/latest",
+			cacheControl: "private, max-age=60",
+			vary:         []string{"Authorization, X-API-Key"},
+		},
+		{
+			name:         "Anonymous route",
+			handler:      authenticated,
+			target:       "/rates/public/latest",
+			cacheControl: "public, max-age=60",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+
+			tc.handler.setCacheControl(rec, httptest.NewRequest(http.MethodGet, tc.target, nil), time.Minute)
+
+			assert.Equal(t, tc.cacheControl, rec.Header().Get(cacheControlHeader))
+			assert.Equal(t, tc.vary, rec.Header().Values("Vary"))
+		})
+	}
+}
+
+func TestWriteProblemClearsValidators(t *testing.T) {
+	rec := httptest.NewRecorder()
+	rec.Header().Set(etagHeader, `"1-2024-03-28-json"`)
+	rec.Header().Set(cacheControlHeader, "public, max-age=60")
+
+	writeProblem(rec, httptest.NewRequest(http.MethodGet, "/rates/2024-03-30", nil),
+		http.StatusNotFound, models.ErrorCodeRatesNotFound, "", "rates not found")
+
+	assert.Empty(t, rec.Header().Get(etagHeader))
+	assert.Empty(t, rec.Header().Get(cacheControlHeader))
+}
@@ -1,8 +1,41 @@
 package handler
 
+import (
+	"github.com/light-bringer/rates-exchanger-service/internal/params"
+	"github.com/light-bringer/rates-exchanger-service/models"
+)
+
 const (
-	contentType       = "application/json"
-	contentTypeHeader = "Content-Type"
-	baseCurrency      = "EUR"
-	defaultRange      = 10
+	contentType        = "application/json"
+	problemContentType = "application/problem+json"
+	csvContentType     = "text/csv; charset=utf-8; header=present"
+	xmlContentType     = "application/xml; charset=utf-8"
+	yamlContentType    = "application/yaml"
+	htmlContentType    = "text/html; charset=utf-8"
+	problemType        = "about:blank"
+	requestIDHeader    = "X-Request-ID"
+	contentTypeHeader  = "Content-Type"
+	etagHeader         = "ETag"
+	lastModifiedHeader = "Last-Modified"
+	cacheControlHeader = "Cache-Control"
+	baseCurrency       = params.BaseCurrency
+	defaultRange       = params.DefaultRange
+	dateLayout         = params.DateLayout
+	hoursPerDay        = 24
+	// maxPrecision is the largest number of decimals rates can be rounded to.
+	maxPrecision = 10
+	// minWindow and maxWindow bound rolling windows, in publication days; 260 is about a year.
+	minWindow = 2
+	maxWindow = 260
+	// defaultIndicatorWindow is the window of technical indicators unless requested otherwise.
+	defaultIndicatorWindow = 20
+	// defaultVolatilityWindow is the rolling window of volatility requests unless requested otherwise.
+	defaultVolatilityWindow = 30
+	// defaultVolatilityDays is the period of volatility requests without a date range.
+	defaultVolatilityDays = 365
+	// defaultBollingerK and maxBollingerK are the default and largest width of Bollinger bands.
+	defaultBollingerK = 2
+	maxBollingerK     = 10
+
+	defaultRoundingMode = models.RoundHalfEven
 )
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/light-bringer/rates-exchanger-service/models"
+)
+
+// GetCorrelation handles requests for the correlation matrix of the daily log returns of some currencies.
+func (h *Handler) GetCorrelation(w http.ResponseWriter, r *http.Request) {
+	symbols, err := h.parseKnownSymbols(r)
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+	if len(symbols) < 2 {
+		badRequest(w, r, paramErrorf("symbols", "at least two symbols are required"))
+		return
+	}
+
+	start, end, err := parseDateRange(r)
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	if spanDays(start, end) > h.config.MaxTimeSeriesDays {
+		writeProblem(w, r, http.StatusBadRequest, models.ErrorCodeRangeTooLarge, "end",
+			fmt.Sprintf("The date range exceeds the maximum of %d days.", h.config.MaxTimeSeriesDays))
+		return
+	}
+
+	base, err := parseBase(r)
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+	if slices.Contains(symbols, base) {
+		badRequest(w, r, paramErrorf("symbols", "symbols must not include the base currency %s", base))
+		return
+	}
+
+	correlation, err := h.service.FetchCorrelation(r.Context(), base, symbols, start.Format(dateLayout), end.Format(dateLayout))
+	if err != nil {
+		serviceError(w, r, err, "base")
+		return
+	}
+
+	writeJSON(w, correlation)
+}
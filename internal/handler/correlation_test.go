@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCorrelationProblems(t *testing.T) {
+	routes := NewHandler(nil, models.APIConfig{MaxTimeSeriesDays: 366}).Routes()
+
+	const rangeQuery = "start=2024-01-01&end=2024-03-31"
+	testCases := []struct {
+		name          string
+		target        string
+		expectedCode  models.ErrorCode
+		expectedParam string
+	}{
+		{
+			name:          "Missing symbols",
+			target:        "/rates/correlation?" + rangeQuery,
+			expectedCode:  models.ErrorCodeInvalidParameter,
+			expectedParam: "symbols",
+		},
+		{
+			name:          "Single symbol",
+			target:        "/rates/correlation?symbols=USD,usd&" + rangeQuery,
+			expectedCode:  models.ErrorCodeInvalidParameter,
+			expectedParam: "symbols",
+		},
+		{
+			name:          "Base among the symbols",
+			target:        "/rates/correlation?symbols=USD,GBP&base=gbp&" + rangeQuery,
+			expectedCode:  models.ErrorCodeInvalidParameter,
+			expectedParam: "symbols",
+		},
+		{
+			name:          "Missing end",
+			target:        "/rates/correlation?symbols=USD,GBP&start=2024-01-01",
+			expectedCode:  models.ErrorCodeInvalidParameter,
+			expectedParam: "end",
+		},
+		{
+			name:          "Range too large",
+			target:        "/rates/correlation?symbols=USD,GBP&start=2020-01-01&end=2024-03-31",
+			expectedCode:  models.ErrorCodeRangeTooLarge,
+			expectedParam: "end",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := serveJSON(t, routes, http.MethodGet, tc.target, "")
+
+			require.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+			problem := decodeProblem(t, rec)
+			assert.Equal(t, tc.expectedCode, problem.Code)
+			assert.Equal(t, tc.expectedParam, problem.Param)
+		})
+	}
+}
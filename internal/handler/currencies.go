@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/light-bringer/rates-exchanger-service/internal/currency"
+	"github.com/light-bringer/rates-exchanger-service/internal/params"
+	"github.com/light-bringer/rates-exchanger-service/internal/service"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+)
+
+// ListCurrencies handles requests for the ISO 4217 currencies and the dates rates are stored for.
+func (h *Handler) ListCurrencies(w http.ResponseWriter, r *http.Request) {
+	currencies, err := h.currencies.ListCurrencies(r.Context())
+	if err != nil {
+		currencyError(w, r, err)
+		return
+	}
+
+	writeJSON(w, map[string]interface{}{"currencies": currencies})
+}
+
+// GetCurrency handles requests for a currency.
+func (h *Handler) GetCurrency(w http.ResponseWriter, r *http.Request) {
+	code := strings.ToUpper(r.PathValue("code"))
+	if !params.IsCurrencyCode(code) {
+		badRequest(w, r, paramErrorf("code", "invalid currency code %q", r.PathValue("code")))
+		return
+	}
+
+	info, err := h.currencies.GetCurrency(r.Context(), code)
+	if err != nil {
+		currencyError(w, r, err)
+		return
+	}
+
+	writeJSON(w, info)
+}
+
+// parseKnownSymbols parses the optional symbols query parameter like parseSymbols and,
+// if the currencies are available, rejects codes that are not ISO 4217 currencies with ErrUnknownCurrency.
+func (h *Handler) parseKnownSymbols(r *http.Request) ([]string, error) {
+	symbols, err := parseSymbols(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = h.checkKnownSymbols("symbols", symbols); err != nil {
+		return nil, err
+	}
+
+	return symbols, nil
+}
+
+// checkKnownSymbols returns an ErrUnknownCurrency error for the given parameter
+// if the currencies are available and some of the given codes are not ISO 4217 currencies.
+func (h *Handler) checkKnownSymbols(param string, symbols []string) error {
+	if h.currencies == nil {
+		return nil
+	}
+
+	if unknown := h.currencies.Unknown(symbols); len(unknown) > 0 {
+		return &paramError{
+			param: param,
+			err:   errors.Wrapf(service.ErrUnknownCurrency, "%s %s", param, strings.Join(unknown, ", ")),
+		}
+	}
+
+	return nil
+}
+
+// currencyError writes the problem matching an error returned by the currency service.
+func currencyError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, currency.ErrCurrencyNotFound) {
+		writeProblem(w, r, http.StatusNotFound, models.ErrorCodeNotFound, "", err.Error())
+		return
+	}
+	serviceError(w, r, err, "")
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/light-bringer/rates-exchanger-service/internal/currency"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCurrencyRoutes(t *testing.T) http.Handler {
+	t.Helper()
+
+	store := currency.NewMemoryStore()
+	currencies := currency.NewService(store)
+	require.NoError(t, currencies.Seed(context.Background()))
+	store.SetDataRange("USD", "2024-01-02", "2024-06-03")
+
+	config := models.APIConfig{SpecValidation: models.ValidationStrict, MaxTimeSeriesDays: 366}
+	return NewHandler(nil, config).
+		WithCurrencies(currencies).
+		Routes()
+}
+
+func TestCurrencyRoutes(t *testing.T) {
+	routes := newCurrencyRoutes(t)
+
+	rec := serveJSON(t, routes, http.MethodGet, "/currencies", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var list struct {
+		Currencies []models.Currency `json:"currencies"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	require.NotEmpty(t, list.Currencies)
+	assert.Equal(t, "AED", list.Currencies[0].Code)
+
+	rec = serveJSON(t, routes, http.MethodGet, "/currencies/usd", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var usd models.Currency
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &usd))
+	assert.Equal(t, "USD", usd.Code)
+	assert.Equal(t, "840", usd.NumericCode)
+	require.NotNil(t, usd.MinorUnits)
+	assert.Equal(t, 2, *usd.MinorUnits)
+	assert.Contains(t, usd.Countries, "United States of America")
+	assert.Equal(t, "2024-01-02", usd.FirstDate)
+	assert.Equal(t, "2024-06-03", usd.LastDate)
+
+	rec = serveJSON(t, routes, http.MethodGet, "/currencies/XAU", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.NotContains(t, rec.Body.String(), "minor_units")
+	assert.NotContains(t, rec.Body.String(), "first_date")
+}
+
+func TestCurrencyRoutesProblems(t *testing.T) {
+	routes := newCurrencyRoutes(t)
+
+	testCases := []struct {
+		name           string
+		target         string
+		expectedStatus int
+		expectedCode   models.ErrorCode
+		expectedParam  string
+	}{
+		{
+			name:           "Unknown currency",
+			target:         "/currencies/XXX",
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   models.ErrorCodeNotFound,
+		},
+		{
+			name:           "Invalid code",
+			target:         "/currencies/US1",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   models.ErrorCodeInvalidParameter,
+			expectedParam:  "code",
+		},
+		{
+			name:           "Unknown symbol",
+			target:         "/rates/analyze?symbols=USD,XXX",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   models.ErrorCodeUnknownCurrency,
+			expectedParam:  "symbols",
+		},
+		{
+			name:           "Unknown symbols in a time series",
+			target:         "/rates/timeseries?start=2024-01-02&end=2024-01-05&symbols=abc,usd",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   models.ErrorCodeUnknownCurrency,
+			expectedParam:  "symbols",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := serveJSON(t, routes, http.MethodGet, tc.target, "")
+
+			require.Equal(t, tc.expectedStatus, rec.Code, rec.Body.String())
+			problem := decodeProblem(t, rec)
+			assert.Equal(t, tc.expectedCode, problem.Code)
+			assert.Equal(t, tc.expectedParam, problem.Param)
+		})
+	}
+}
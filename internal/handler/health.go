@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/light-bringer/rates-exchanger-service/models"
+)
+
+// LiveCheck handles requests for the liveness probe. It reports the process as alive without checking
+// its dependencies, so that an unreachable database does not get healthy instances restarted.
+func (h *Handler) LiveCheck(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set(cacheControlHeader, "no-store")
+	writeJSON(w, models.Health{Status: models.HealthOK, Checks: map[string]models.HealthCheck{}})
+}
+
+// ReadyCheck handles requests for the readiness probe. It runs the readiness checks and answers
+// with 503 if any of them fails, so that no traffic is routed to instances with unusable data.
+func (h *Handler) ReadyCheck(w http.ResponseWriter, r *http.Request) {
+	health := h.health.Check(r.Context())
+
+	status := http.StatusOK
+	if health.Status != models.HealthOK {
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set(cacheControlHeader, "no-store")
+	writeJSONStatus(w, status, health)
+}
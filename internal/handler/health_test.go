@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/light-bringer/rates-exchanger-service/internal/health"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// healthDeps fakes the dependencies of the readiness checks.
+type healthDeps struct {
+	pingErr   error
+	latestDay string
+	status    models.SyncStatus
+}
+
+func (d healthDeps) Ping(context.Context) error {
+	return d.pingErr
+}
+
+func (d healthDeps) FetchDataVersion(context.Context) (models.DataVersion, error) {
+	return models.DataVersion{LatestDay: d.latestDay}, d.pingErr
+}
+
+func (d healthDeps) Status() models.SyncStatus {
+	return d.status
+}
+
+func TestHealthRoutes(t *testing.T) {
+	config := models.APIConfig{SpecValidation: models.ValidationStrict}
+	synced := models.SyncStatus{LastRun: time.Now(), LastSuccess: time.Now()}
+
+	testCases := []struct {
+		name   string
+		deps   healthDeps
+		code   int
+		status models.HealthStatus
+		failed string
+	}{
+		{
+			name:   "Ready",
+			deps:   healthDeps{latestDay: "2999-12-31", status: synced},
+			code:   http.StatusOK,
+			status: models.HealthOK,
+		},
+		{
+			name:   "Database down",
+			deps:   healthDeps{pingErr: errors.New("connection refused"), status: synced},
+			code:   http.StatusServiceUnavailable,
+			status: models.HealthDegraded,
+			failed: health.CheckDatabase,
+		},
+		{
+			name:   "Startup sync running",
+			deps:   healthDeps{latestDay: "2999-12-31"},
+			code:   http.StatusServiceUnavailable,
+			status: models.HealthDegraded,
+			failed: health.CheckStartupSync,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			checker := health.NewChecker(tc.deps, tc.deps, tc.deps, models.HealthConfig{})
+			routes := NewHandler(nil, config).WithHealth(checker).Routes()
+
+			rec := serveJSON(t, routes, http.MethodGet, "/health/ready", "")
+			require.Equal(t, tc.code, rec.Code, rec.Body.String())
+			assert.Equal(t, "no-store", rec.Header().Get(cacheControlHeader))
+
+			var result models.Health
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+			assert.Equal(t, tc.status, result.Status)
+			assert.Len(t, result.Checks, 3)
+			if tc.failed != "" {
+				assert.Equal(t, models.HealthFail, result.Checks[tc.failed].Status)
+				assert.NotEmpty(t, result.Checks[tc.failed].Detail)
+			}
+
+			rec = serveJSON(t, routes, http.MethodGet, "/health/live", "")
+			require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+			assert.JSONEq(t, `{"status": "ok", "checks": {}}`, rec.Body.String())
+		})
+	}
+
+	t.Run("Readiness not configured", func(t *testing.T) {
+		// The specification does not document a 404 for the readiness probe, so responses are not validated.
+		routes := NewHandler(nil, models.APIConfig{SpecValidation: models.ValidationOff}).Routes()
+
+		rec := serveJSON(t, routes, http.MethodGet, "/health/ready", "")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+
+		rec = serveJSON(t, routes, http.MethodGet, "/health/live", "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/light-bringer/rates-exchanger-service/internal/params"
+	"github.com/light-bringer/rates-exchanger-service/models"
+)
+
+// GetIndicator handles requests for a technical indicator of a currency over a date range.
+func (h *Handler) GetIndicator(w http.ResponseWriter, r *http.Request) {
+	currency := strings.ToUpper(r.PathValue("currency"))
+	if !params.IsCurrencyCode(currency) {
+		badRequest(w, r, paramErrorf("currency", "invalid currency %q", currency))
+		return
+	}
+	if err := h.checkKnownSymbols("currency", []string{currency}); err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	params, err := parseIndicatorParams(r)
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	start, end, err := parseDateRange(r)
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	if spanDays(start, end) > h.config.MaxTimeSeriesDays {
+		writeProblem(w, r, http.StatusBadRequest, models.ErrorCodeRangeTooLarge, "end",
+			fmt.Sprintf("The date range exceeds the maximum of %d days.", h.config.MaxTimeSeriesDays))
+		return
+	}
+
+	base, err := parseBase(r)
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+	if base == currency {
+		badRequest(w, r, paramErrorf("base", "base must differ from the currency %s", currency))
+		return
+	}
+
+	indicator, err := h.service.FetchIndicator(r.Context(), currency, base, start.Format(dateLayout), end.Format(dateLayout), params)
+	if err != nil {
+		serviceError(w, r, err, "currency")
+		return
+	}
+
+	writeJSON(w, indicator)
+}
+
+// parseIndicatorParams parses the optional type, window and k query parameters of an indicator request.
+// The indicator defaults to a simple moving average over defaultIndicatorWindow days.
+func parseIndicatorParams(r *http.Request) (models.IndicatorParams, error) {
+	params := models.IndicatorParams{
+		Type: models.IndicatorType(strings.ToLower(r.URL.Query().Get("type"))),
+		K:    defaultBollingerK,
+	}
+
+	switch params.Type {
+	case "":
+		params.Type = models.IndicatorSMA
+	case models.IndicatorSMA, models.IndicatorEMA, models.IndicatorMin, models.IndicatorMax, models.IndicatorBollinger:
+	default:
+		return models.IndicatorParams{}, paramErrorf("type", "invalid indicator type %q, expected sma, ema, min, "+
+			"max or bollinger", params.Type)
+	}
+
+	window, err := parseWindow(r, defaultIndicatorWindow)
+	if err != nil {
+		return models.IndicatorParams{}, err
+	}
+	params.Window = window
+
+	if kStr := r.URL.Query().Get("k"); kStr != "" {
+		params.K, err = strconv.ParseFloat(kStr, 64)
+		if err != nil || math.IsNaN(params.K) || params.K <= 0 || params.K > maxBollingerK {
+			return models.IndicatorParams{}, paramErrorf("k", "k must be a number above 0 and at most %d", maxBollingerK)
+		}
+	}
+
+	return params, nil
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/light-bringer/rates-exchanger-service/internal/currency"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIndicatorParams(t *testing.T) {
+	testCases := []struct {
+		name     string
+		query    string
+		expected models.IndicatorParams
+	}{
+		{
+			name:     "Defaults",
+			query:    "",
+			expected: models.IndicatorParams{Type: models.IndicatorSMA, Window: 20, K: 2},
+		},
+		{
+			name:     "Exponential moving average",
+			query:    "type=EMA&window=50",
+			expected: models.IndicatorParams{Type: models.IndicatorEMA, Window: 50, K: 2},
+		},
+		{
+			name:     "Bollinger bands",
+			query:    "type=bollinger&window=20&k=2.5",
+			expected: models.IndicatorParams{Type: models.IndicatorBollinger, Window: 20, K: 2.5},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/rates/USD/indicators?"+tc.query, nil)
+
+			params, err := parseIndicatorParams(r)
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, params)
+		})
+	}
+}
+
+func TestGetIndicatorProblems(t *testing.T) {
+	currencies := currency.NewService(currency.NewMemoryStore())
+	require.NoError(t, currencies.Seed(context.Background()))
+	routes := NewHandler(nil, models.APIConfig{MaxTimeSeriesDays: 366}).WithCurrencies(currencies).Routes()
+
+	const rangeQuery = "start=2024-01-01&end=2024-03-31"
+	testCases := []struct {
+		name          string
+		target        string
+		expectedCode  models.ErrorCode
+		expectedParam string
+	}{
+		{
+			name:          "Invalid currency",
+			target:        "/rates/US1/indicators?" + rangeQuery,
+			expectedCode:  models.ErrorCodeInvalidParameter,
+			expectedParam: "currency",
+		},
+		{
+			name:          "Unknown currency",
+			target:        "/rates/XXX/indicators?" + rangeQuery,
+			expectedCode:  models.ErrorCodeUnknownCurrency,
+			expectedParam: "currency",
+		},
+		{
+			name:          "Invalid type",
+			target:        "/rates/USD/indicators?type=macd&" + rangeQuery,
+			expectedCode:  models.ErrorCodeInvalidParameter,
+			expectedParam: "type",
+		},
+		{
+			name:          "Window too small",
+			target:        "/rates/USD/indicators?window=1&" + rangeQuery,
+			expectedCode:  models.ErrorCodeInvalidParameter,
+			expectedParam: "window",
+		},
+		{
+			name:          "Window too large",
+			target:        "/rates/USD/indicators?window=261&" + rangeQuery,
+			expectedCode:  models.ErrorCodeInvalidParameter,
+			expectedParam: "window",
+		},
+		{
+			name:          "Invalid band width",
+			target:        "/rates/USD/indicators?type=bollinger&k=0&" + rangeQuery,
+			expectedCode:  models.ErrorCodeInvalidParameter,
+			expectedParam: "k",
+		},
+		{
+			name:          "Missing start",
+			target:        "/rates/USD/indicators?end=2024-03-31",
+			expectedCode:  models.ErrorCodeInvalidParameter,
+			expectedParam: "start",
+		},
+		{
+			name:          "Range too large",
+			target:        "/rates/USD/indicators?start=2022-01-01&end=2024-03-31",
+			expectedCode:  models.ErrorCodeRangeTooLarge,
+			expectedParam: "end",
+		},
+		{
+			name:          "Same currency and base",
+			target:        "/rates/usd/indicators?base=USD&" + rangeQuery,
+			expectedCode:  models.ErrorCodeInvalidParameter,
+			expectedParam: "base",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := serveJSON(t, routes, http.MethodGet, tc.target, "")
+
+			require.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+			problem := decodeProblem(t, rec)
+			assert.Equal(t, tc.expectedCode, problem.Code)
+			assert.Equal(t, tc.expectedParam, problem.Param)
+		})
+	}
+}
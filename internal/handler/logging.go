@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/light-bringer/rates-exchanger-service/internal/logging"
+)
+
+// withRequestLogging serves requests with a request ID and a request-scoped logger, and writes one
+// access log line per request. A valid client supplied X-Request-ID header is reused; otherwise a new ID
+// is generated. The ID is returned in the X-Request-ID response header and carried by the logger,
+// which handlers and services read from the request context.
+// The access log names the route returned by route, e.g. the pattern of the matching http.ServeMux route.
+func withRequestLogging(next http.Handler, route func(r *http.Request) string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started := time.Now()
+
+		id := r.Header.Get(requestIDHeader)
+		if !logging.IsValidRequestID(id) {
+			id = logging.NewRequestID()
+		}
+		r.Header.Set(requestIDHeader, id)
+		w.Header().Set(requestIDHeader, id)
+
+		logger := slog.Default().With("request_id", id)
+		r = r.WithContext(logging.NewContext(r.Context(), logger))
+
+		recorder := &accessRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		logger.Info("Request served",
+			"method", r.Method,
+			"route", route(r),
+			"path", r.URL.Path,
+			"status", recorder.status,
+			"bytes", recorder.bytes,
+			"duration", time.Since(started),
+			"client_ip", clientIP(r))
+	})
+}
+
+// requestLogger returns the logger of the request, which carries its request ID.
+func requestLogger(r *http.Request) *slog.Logger {
+	return logging.FromContext(r.Context())
+}
+
+// clientIP returns the IP address of the client, without the port.
+// Forwarding headers are not trusted, since any client could set them.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// accessRecorder records the status and the size of a response for the access log.
+type accessRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int
+	wroteHeader bool
+}
+
+func (a *accessRecorder) WriteHeader(status int) {
+	if !a.wroteHeader {
+		a.status = status
+		a.wroteHeader = true
+	}
+	a.ResponseWriter.WriteHeader(status)
+}
+
+func (a *accessRecorder) Write(b []byte) (int, error) {
+	a.wroteHeader = true
+	n, err := a.ResponseWriter.Write(b)
+	a.bytes += n
+	return n, err //nolint:wrapcheck // transparent writer
+}
+
+// Flush sends buffered data to the client.
+func (a *accessRecorder) Flush() {
+	if flusher, ok := a.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap returns the underlying writer, so that http.ResponseController can reach it.
+func (a *accessRecorder) Unwrap() http.ResponseWriter {
+	return a.ResponseWriter
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/light-bringer/rates-exchanger-service/internal/logging"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// captureLogs redirects the default logger to a buffer of JSON lines for the duration of the test.
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+	return &buf
+}
+
+// logLines decodes the captured JSON log lines.
+func logLines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+
+	lines := make([]map[string]interface{}, 0)
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		lines = append(lines, entry)
+	}
+	return lines
+}
+
+func TestRequestLogging(t *testing.T) {
+	routes := NewHandler(nil, models.APIConfig{SpecValidation: models.ValidationOff}).Routes()
+
+	testCases := []struct {
+		name      string
+		requestID string
+		reused    bool
+	}{
+		{name: "Client ID", requestID: "client-id_1.2:3", reused: true},
+		{name: "No ID"},
+		{name: "Invalid ID", requestID: "forged\nline"},
+		{name: "Long ID", requestID: strings.Repeat("a", logging.MaxRequestIDLength+1)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := captureLogs(t)
+
+			req := httptest.NewRequest(http.MethodGet, "/rates/2024-13-01", nil)
+			req.RemoteAddr = "192.0.2.10:53124"
+			if tc.requestID != "" {
+				req.Header.Set(requestIDHeader, tc.requestID)
+			}
+			rec := httptest.NewRecorder()
+			routes.ServeHTTP(rec, req)
+			require.Equal(t, http.StatusBadRequest, rec.Code)
+			size := rec.Body.Len()
+
+			id := rec.Header().Get(requestIDHeader)
+			if tc.reused {
+				assert.Equal(t, tc.requestID, id)
+			} else {
+				assert.Len(t, id, len(logging.NewRequestID()))
+			}
+			assert.Equal(t, id, decodeProblem(t, rec).RequestID)
+
+			lines := logLines(t, buf)
+			require.NotEmpty(t, lines)
+			for _, line := range lines {
+				assert.Equal(t, id, line["request_id"], line["msg"])
+			}
+
+			access := lines[len(lines)-1]
+			assert.Equal(t, "Request served", access["msg"])
+			assert.Equal(t, "GET", access["method"])
+			assert.Equal(t, "GET /rates/{calculationDay}", access["route"])
+			assert.Equal(t, "/rates/2024-13-01", access["path"])
+			assert.InDelta(t, http.StatusBadRequest, access["status"], 0)
+			assert.InDelta(t, size, access["bytes"], 0)
+			assert.Contains(t, access, "duration")
+			assert.Equal(t, "192.0.2.10", access["client_ip"])
+		})
+	}
+}
+
+func TestRequestLoggingContextLogger(t *testing.T) {
+	buf := captureLogs(t)
+
+	handler := withRequestLogging(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logging.FromContext(r.Context()).Info("Handled")
+		w.WriteHeader(http.StatusNoContent)
+	}), func(*http.Request) string { return "GET /test" })
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set(requestIDHeader, "abc")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	lines := logLines(t, buf)
+	require.Len(t, lines, 2)
+	assert.Equal(t, "Handled", lines[0]["msg"])
+	assert.Equal(t, "abc", lines[0]["request_id"])
+	assert.InDelta(t, http.StatusNoContent, lines[1]["status"], 0)
+	assert.Equal(t, "GET /test", lines[1]["route"])
+}
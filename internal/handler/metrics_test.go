@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/light-bringer/rates-exchanger-service/internal/metrics"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsRoute(t *testing.T) {
+	config := models.APIConfig{SpecValidation: models.ValidationStrict}
+	routes := NewHandler(nil, config).WithMetrics(metrics.NewMetrics()).Routes()
+
+	rec := serveJSON(t, routes, http.MethodGet, "/health/live", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	rec = serveJSON(t, routes, http.MethodGet, "/rates/latest?limit=ten", "")
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = serveJSON(t, routes, http.MethodGet, "/nowhere", "")
+	require.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	routes.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Header().Get(contentTypeHeader), "text/plain")
+
+	body := rec.Body.String()
+	assert.Contains(t, body, `rates_http_requests_total{method="GET",route="/health/live",status="200"} 1`)
+	assert.Contains(t, body, `rates_http_requests_total{method="GET",route="/rates/latest",status="400"} 1`)
+	assert.Contains(t, body, `rates_http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+}
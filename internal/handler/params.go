@@ -0,0 +1,232 @@
+package handler
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/light-bringer/rates-exchanger-service/internal/params"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+)
+
+// paramError is returned by the parameter parsers and names the offending request parameter.
+type paramError struct {
+	param string
+	err   error
+}
+
+func (e *paramError) Error() string {
+	return e.err.Error()
+}
+
+func (e *paramError) Unwrap() error {
+	return e.err
+}
+
+// paramErrorf returns a paramError for the given parameter with a formatted message.
+func paramErrorf(param string, format string, args ...interface{}) error {
+	return &paramError{param: param, err: fmt.Errorf(format, args...)}
+}
+
+// parseLimit parses the optional limit query parameter. Zero means no limit.
+func parseLimit(r *http.Request) (uint64, error) {
+	return parseUintParam(r, "limit", 0)
+}
+
+// parseUintParam parses an optional unsigned integer query parameter.
+// If the parameter is absent, the given default is returned.
+func parseUintParam(r *http.Request, name string, defaultValue uint64) (uint64, error) {
+	valueStr := r.URL.Query().Get(name)
+	if valueStr == "" {
+		return defaultValue, nil
+	}
+
+	value, err := strconv.ParseUint(valueStr, 10, 64)
+	if err != nil {
+		return 0, paramErrorf(name, "invalid %s %q", name, valueStr)
+	}
+
+	return value, nil
+}
+
+// parseWindow parses the optional window query parameter, a number of publication days
+// between minWindow and maxWindow.
+func parseWindow(r *http.Request, defaultValue uint64) (int, error) {
+	window, err := parseUintParam(r, "window", defaultValue)
+	if err != nil {
+		return 0, err
+	}
+
+	if window < minWindow || window > maxWindow {
+		return 0, paramErrorf("window", "window must be between %d and %d", minWindow, maxWindow)
+	}
+
+	return int(window), nil
+}
+
+// parseDatePath parses a required date path parameter in YYYY-MM-DD format.
+func parseDatePath(r *http.Request, name string) (string, error) {
+	date := r.PathValue(name)
+	if _, err := params.ParseDate("date", date); err != nil {
+		return "", &paramError{param: "date", err: err}
+	}
+
+	return date, nil
+}
+
+// parseBase parses the optional base currency query parameter.
+// If the parameter is absent, the default base currency is returned.
+func parseBase(r *http.Request) (string, error) {
+	base, err := params.ParseBase(r.URL.Query().Get("base"))
+	if err != nil {
+		return "", &paramError{param: "base", err: err}
+	}
+
+	return base, nil
+}
+
+// parseCurrency parses a required currency code query parameter.
+func parseCurrency(r *http.Request, name string) (string, error) {
+	code := strings.ToUpper(strings.TrimSpace(r.URL.Query().Get(name)))
+	if code == "" {
+		return "", paramErrorf(name, "missing %s currency", name)
+	}
+
+	if !params.IsCurrencyCode(code) {
+		return "", paramErrorf(name, "invalid %s currency %q", name, code)
+	}
+
+	return code, nil
+}
+
+// parseAmount parses the required amount query parameter.
+func parseAmount(r *http.Request) (float64, error) {
+	amountStr := r.URL.Query().Get("amount")
+	if amountStr == "" {
+		return 0, paramErrorf("amount", "missing amount")
+	}
+
+	amount, err := strconv.ParseFloat(amountStr, 64)
+	if err != nil || math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return 0, paramErrorf("amount", "invalid amount %q", amountStr)
+	}
+
+	return amount, nil
+}
+
+// parseOptionalDate parses an optional date query parameter in YYYY-MM-DD format.
+// An empty string is returned if the parameter is absent.
+func parseOptionalDate(r *http.Request, name string) (string, error) {
+	date := r.URL.Query().Get(name)
+	if date == "" {
+		return "", nil
+	}
+
+	if _, err := params.ParseDate(name+" date", date); err != nil {
+		return "", &paramError{param: name, err: err}
+	}
+
+	return date, nil
+}
+
+// parseRoundingMode parses the optional rounding query parameter.
+// If the parameter is absent, the default rounding mode is returned.
+func parseRoundingMode(r *http.Request) (models.RoundingMode, error) {
+	mode := models.RoundingMode(strings.ToLower(r.URL.Query().Get("rounding")))
+	switch mode {
+	case "":
+		return defaultRoundingMode, nil
+	case models.RoundHalfEven, models.RoundHalfUp:
+		return mode, nil
+	default:
+		return "", paramErrorf("rounding", "invalid rounding mode %q", mode)
+	}
+}
+
+// parseSymbols parses the optional comma-separated symbols query parameter.
+// Duplicate codes are removed; a nil slice is returned if the parameter is absent.
+func parseSymbols(r *http.Request) ([]string, error) {
+	symbolsStr := r.URL.Query().Get("symbols")
+	if symbolsStr == "" {
+		return nil, nil
+	}
+
+	return normalizeSymbols("symbols", strings.Split(symbolsStr, ","))
+}
+
+// normalizeSymbols upper-cases the given currency codes and removes duplicates.
+// The function returns an error for the given parameter if a code is invalid.
+func normalizeSymbols(param string, codes []string) ([]string, error) {
+	symbols, err := params.ParseSymbols(codes)
+	if err != nil {
+		return nil, &paramError{param: param, err: err}
+	}
+
+	return symbols, nil
+}
+
+// parseDateRange parses the required start and end date query parameters.
+// The function returns an error if either date is missing or invalid, or if end is before start.
+func parseDateRange(r *http.Request) (time.Time, time.Time, error) {
+	startStr := r.URL.Query().Get("start")
+	start, err := params.ParseDate("start date", startStr)
+	if err != nil {
+		return time.Time{}, time.Time{}, &paramError{param: "start", err: err}
+	}
+
+	endStr := r.URL.Query().Get("end")
+	end, err := params.ParseDate("end date", endStr)
+	if err != nil {
+		return time.Time{}, time.Time{}, &paramError{param: "end", err: err}
+	}
+
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, paramErrorf("end", "end date %s is before start date %s", endStr, startStr)
+	}
+
+	return start, end, nil
+}
+
+// spanDays returns the number of calendar days covered by the inclusive range [start, end].
+func spanDays(start, end time.Time) int {
+	return int(end.Sub(start).Hours()/hoursPerDay) + 1
+}
+
+// parseFallback parses the optional fallback query parameter.
+// If the parameter is absent, the previous publication day is used.
+func parseFallback(r *http.Request) (models.Fallback, error) {
+	fallback := models.Fallback(strings.ToLower(r.URL.Query().Get("fallback")))
+	switch fallback {
+	case "":
+		return models.FallbackPrevious, nil
+	case models.FallbackPrevious, models.FallbackNext, models.FallbackNone:
+		return fallback, nil
+	default:
+		return "", paramErrorf("fallback", "invalid fallback %q", fallback)
+	}
+}
+
+// rejectParams returns an error for the first of the given query parameters present in a request.
+// It is used for presentation parameters an endpoint does not support, which would otherwise be ignored.
+func rejectParams(r *http.Request, names ...string) error {
+	query := r.URL.Query()
+	for _, name := range names {
+		if query.Has(name) {
+			return paramErrorf(name, "%s is not supported by this endpoint", name)
+		}
+	}
+	return nil
+}
+
+// paramName returns the name of the parameter an error refers to, if any.
+func paramName(err error) string {
+	var paramErr *paramError
+	if errors.As(err, &paramErr) {
+		return paramErr.param
+	}
+	return ""
+}
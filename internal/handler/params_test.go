@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBase(t *testing.T) {
+	testCases := []struct {
+		name          string
+		target        string
+		expectedError bool
+		expectedValue string
+	}{
+		{name: "Default base", target: "/rates/latest", expectedValue: "EUR"},
+		{name: "Explicit base", target: "/rates/latest?base=USD", expectedValue: "USD"},
+		{name: "Lower case base", target: "/rates/latest?base=gbp", expectedValue: "GBP"},
+		{name: "Too long base", target: "/rates/latest?base=USDT", expectedError: true},
+		{name: "Non letter base", target: "/rates/latest?base=U5D", expectedError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			base, err := parseBase(httptest.NewRequest("GET", tc.target, nil))
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedValue, base)
+		})
+	}
+}
+
+func TestParseSymbols(t *testing.T) {
+	symbols, err := parseSymbols(httptest.NewRequest("GET", "/rates/timeseries?symbols=usd,JPY,%20usd", nil))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"USD", "JPY"}, symbols)
+
+	symbols, err = parseSymbols(httptest.NewRequest("GET", "/rates/timeseries", nil))
+	require.NoError(t, err)
+	assert.Nil(t, symbols)
+
+	_, err = parseSymbols(httptest.NewRequest("GET", "/rates/timeseries?symbols=USD,,JPY", nil))
+	require.Error(t, err)
+}
+
+func TestParseDateRange(t *testing.T) {
+	testCases := []struct {
+		name          string
+		target        string
+		expectedError bool
+		expectedDays  int
+	}{
+		{name: "Single day", target: "/?start=2024-03-01&end=2024-03-01", expectedDays: 1},
+		{name: "Leap month", target: "/?start=2024-02-01&end=2024-02-29", expectedDays: 29},
+		{name: "Missing end", target: "/?start=2024-03-01", expectedError: true},
+		{name: "Invalid start", target: "/?start=01-03-2024&end=2024-03-01", expectedError: true},
+		{name: "End before start", target: "/?start=2024-03-02&end=2024-03-01", expectedError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			start, end, err := parseDateRange(httptest.NewRequest("GET", tc.target, nil))
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedDays, spanDays(start, end))
+		})
+	}
+}
+
+func TestParseFallback(t *testing.T) {
+	fallback, err := parseFallback(httptest.NewRequest("GET", "/rates/2024-03-30", nil))
+	require.NoError(t, err)
+	assert.Equal(t, models.FallbackPrevious, fallback)
+
+	fallback, err = parseFallback(httptest.NewRequest("GET", "/rates/2024-03-30?fallback=next", nil))
+	require.NoError(t, err)
+	assert.Equal(t, models.FallbackNext, fallback)
+
+	_, err = parseFallback(httptest.NewRequest("GET", "/rates/2024-03-30?fallback=closest", nil))
+	require.Error(t, err)
+}
@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/light-bringer/rates-exchanger-service/models"
+)
+
+// rateSort selects the key the rates of a day are sorted by.
+type rateSort string
+
+const (
+	sortByCurrency rateSort = "currency"
+	sortByRate     rateSort = "rate"
+)
+
+// presentation holds the options shared by the rate endpoints to present the rates.
+// The zero value presents the rates unchanged, sorted by currency in ascending order.
+type presentation struct {
+	sort rateSort
+	desc bool
+	// round enables rounding the rates to precision decimals.
+	round     bool
+	precision int
+	// invert quotes the rates as units of the base currency per unit of each currency.
+	invert bool
+	// limit keeps the first rates of a day once they are sorted; zero keeps all of them.
+	// It is set by the endpoints accepting the limit parameter.
+	limit uint64
+}
+
+// parsePresentation parses the optional sort, order, precision and invert query parameters.
+func parsePresentation(r *http.Request) (presentation, error) {
+	query := r.URL.Query()
+	var present presentation
+
+	present.sort = rateSort(strings.ToLower(query.Get("sort")))
+	switch present.sort {
+	case "":
+		present.sort = sortByCurrency
+	case sortByCurrency, sortByRate:
+	default:
+		return presentation{}, paramErrorf("sort", "invalid sort %q, expected currency or rate", present.sort)
+	}
+
+	switch order := strings.ToLower(query.Get("order")); order {
+	case "", "asc":
+	case "desc":
+		present.desc = true
+	default:
+		return presentation{}, paramErrorf("order", "invalid order %q, expected asc or desc", order)
+	}
+
+	var err error
+	if present.round, present.precision, err = parsePrecision(r); err != nil {
+		return presentation{}, err
+	}
+
+	if invertStr := query.Get("invert"); invertStr != "" {
+		invert, err := strconv.ParseBool(invertStr)
+		if err != nil {
+			return presentation{}, paramErrorf("invert", "invalid invert %q, expected true or false", invertStr)
+		}
+		present.invert = invert
+	}
+
+	return present, nil
+}
+
+// parsePrecision parses the optional precision query parameter. It reports whether rounding is requested.
+func parsePrecision(r *http.Request) (bool, int, error) {
+	precisionStr := r.URL.Query().Get("precision")
+	if precisionStr == "" {
+		return false, 0, nil
+	}
+
+	precision, err := strconv.Atoi(precisionStr)
+	if err != nil || precision < 0 || precision > maxPrecision {
+		return false, 0, paramErrorf("precision", "precision must be an integer between 0 and %d", maxPrecision)
+	}
+	return true, precision, nil
+}
+
+// rate returns a single rate inverted and rounded as requested.
+func (p presentation) rate(rate float64) float64 {
+	return p.roundValue(p.invertRate(rate))
+}
+
+// invertRate returns the rate inverted if requested.
+func (p presentation) invertRate(rate float64) float64 {
+	if p.invert && rate != 0 {
+		return 1 / rate
+	}
+	return rate
+}
+
+// roundValue returns a value rounded to the requested precision, if any.
+func (p presentation) roundValue(value float64) float64 {
+	if !p.round {
+		return value
+	}
+	// Formatting rounds the decimal representation correctly, which scaling by powers of ten does not.
+	value, _ = strconv.ParseFloat(strconv.FormatFloat(value, 'f', p.precision, 64), 64)
+	return value
+}
+
+// rates returns a copy of the rates of a day, inverted, rounded, sorted and limited as requested.
+// Rates sorted by rate keep the currency order among equal rates.
+func (p presentation) rates(rates models.LatestExchangeRates) models.LatestExchangeRates {
+	presented := make(models.LatestExchangeRates, 0, len(rates))
+	for _, rate := range rates {
+		presented = append(presented, models.LatestExchangeRate{Currency: rate.Currency, Rate: p.rate(rate.Rate)})
+	}
+
+	less := func(a, b models.LatestExchangeRate) bool {
+		if p.sort == sortByRate && a.Rate != b.Rate {
+			return a.Rate < b.Rate
+		}
+		return a.Currency < b.Currency
+	}
+	sort.SliceStable(presented, func(i, j int) bool {
+		if p.desc {
+			return less(presented[j], presented[i])
+		}
+		return less(presented[i], presented[j])
+	})
+
+	if p.limit > 0 && uint64(len(presented)) > p.limit {
+		presented = presented[:p.limit]
+	}
+
+	return presented
+}
+
+// seriesRates returns the rates of a day of a time series, presented as requested.
+func (p presentation) seriesRates(rates map[string]float64) models.LatestExchangeRates {
+	dayRates := make(models.LatestExchangeRates, 0, len(rates))
+	for currency, rate := range rates {
+		dayRates = append(dayRates, models.LatestExchangeRate{Currency: currency, Rate: rate})
+	}
+	return p.rates(dayRates)
+}
+
+// fluctuations returns a copy of the fluctuations with the rates inverted and rounded as requested.
+// The changes of inverted rates are computed from the inverted rates before rounding; percentages are not rounded.
+func (p presentation) fluctuations(fluctuations models.Fluctuations) models.Fluctuations {
+	presented := models.Fluctuations{
+		StartDate: fluctuations.StartDate,
+		EndDate:   fluctuations.EndDate,
+		Rates:     make(map[string]models.Fluctuation, len(fluctuations.Rates)),
+	}
+
+	for currency, fluctuation := range fluctuations.Rates {
+		if p.invert {
+			start, end := p.invertRate(fluctuation.StartRate), p.invertRate(fluctuation.EndRate)
+			fluctuation = models.Fluctuation{StartRate: start, EndRate: end, Change: end - start}
+			if start != 0 {
+				fluctuation.ChangePercent = fluctuation.Change / start * models.Percent
+			}
+		}
+
+		fluctuation.StartRate = p.roundValue(fluctuation.StartRate)
+		fluctuation.EndRate = p.roundValue(fluctuation.EndRate)
+		fluctuation.Change = p.roundValue(fluctuation.Change)
+		presented.Rates[currency] = fluctuation
+	}
+
+	return presented
+}
+
+// fluctuationOrder returns the currencies of the fluctuations in the requested order, sorting by the end rate
+// if the rates are sorted by rate.
+func (p presentation) fluctuationOrder(rates map[string]models.Fluctuation) []string {
+	endRates := make(models.LatestExchangeRates, 0, len(rates))
+	for currency, fluctuation := range rates {
+		endRates = append(endRates, models.LatestExchangeRate{Currency: currency, Rate: fluctuation.EndRate})
+	}
+
+	// The rates are already presented, so they are only sorted.
+	order := presentation{sort: p.sort, desc: p.desc}
+	currencies := make([]string, 0, len(rates))
+	for _, rate := range order.rates(endRates) {
+		currencies = append(currencies, rate.Currency)
+	}
+	return currencies
+}
+
+// statistics returns a copy of the statistics with the rate values rounded as requested.
+// Counts, percentages and dates are kept.
+func (p presentation) statistics(stats models.RateStatisticsMap) models.RateStatisticsMap {
+	presented := make(models.RateStatisticsMap, len(stats))
+	for currency, stat := range stats {
+		stat.AvgRate = p.roundValue(stat.AvgRate)
+		stat.MinRate = p.roundValue(stat.MinRate)
+		stat.MaxRate = p.roundValue(stat.MaxRate)
+		stat.MedianRate = p.roundValue(stat.MedianRate)
+		stat.StdDev = p.roundValue(stat.StdDev)
+		stat.FirstRate = p.roundValue(stat.FirstRate)
+		stat.LastRate = p.roundValue(stat.LastRate)
+		stat.Change = p.roundValue(stat.Change)
+		presented[currency] = stat
+	}
+	return presented
+}
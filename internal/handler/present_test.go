@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePresentation(t *testing.T) {
+	testCases := []struct {
+		name          string
+		query         string
+		expected      presentation
+		expectedParam string
+	}{
+		{name: "Defaults", query: "", expected: presentation{sort: sortByCurrency}},
+		{name: "Sort by rate descending", query: "sort=RATE&order=desc", expected: presentation{sort: sortByRate, desc: true}},
+		{name: "Precision", query: "precision=0", expected: presentation{sort: sortByCurrency, round: true}},
+		{name: "Invert", query: "invert=true", expected: presentation{sort: sortByCurrency, invert: true}},
+		{name: "Invalid sort", query: "sort=name", expectedParam: "sort"},
+		{name: "Invalid order", query: "order=up", expectedParam: "order"},
+		{name: "Negative precision", query: "precision=-1", expectedParam: "precision"},
+		{name: "Precision too large", query: "precision=11", expectedParam: "precision"},
+		{name: "Invalid invert", query: "invert=yes", expectedParam: "invert"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			present, err := parsePresentation(httptest.NewRequest(http.MethodGet, "/rates/latest?"+tc.query, nil))
+
+			if tc.expectedParam != "" {
+				require.Error(t, err)
+				assert.Equal(t, tc.expectedParam, paramName(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, present)
+		})
+	}
+}
+
+func TestPresentationRates(t *testing.T) {
+	rates := models.LatestExchangeRates{
+		{Currency: "GBP", Rate: 0.8551},
+		{Currency: "JPY", Rate: 162.15},
+		{Currency: "USD", Rate: 1.0811},
+		{Currency: "CHF", Rate: 1.0811},
+	}
+
+	testCases := []struct {
+		name     string
+		present  presentation
+		expected models.LatestExchangeRates
+	}{
+		{
+			name:    "Currency order",
+			present: presentation{},
+			expected: models.LatestExchangeRates{
+				{Currency: "CHF", Rate: 1.0811},
+				{Currency: "GBP", Rate: 0.8551},
+				{Currency: "JPY", Rate: 162.15},
+				{Currency: "USD", Rate: 1.0811},
+			},
+		},
+		{
+			name:    "Rate order descending",
+			present: presentation{sort: sortByRate, desc: true},
+			expected: models.LatestExchangeRates{
+				{Currency: "JPY", Rate: 162.15},
+				{Currency: "USD", Rate: 1.0811},
+				{Currency: "CHF", Rate: 1.0811},
+				{Currency: "GBP", Rate: 0.8551},
+			},
+		},
+		{
+			name:    "Inverted and rounded",
+			present: presentation{sort: sortByRate, invert: true, round: true, precision: 4},
+			expected: models.LatestExchangeRates{
+				{Currency: "JPY", Rate: 0.0062},
+				{Currency: "CHF", Rate: 0.925},
+				{Currency: "USD", Rate: 0.925},
+				{Currency: "GBP", Rate: 1.1695},
+			},
+		},
+		{
+			name:    "Limited after sorting",
+			present: presentation{sort: sortByRate, limit: 2},
+			expected: models.LatestExchangeRates{
+				{Currency: "GBP", Rate: 0.8551},
+				{Currency: "CHF", Rate: 1.0811},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.present.rates(rates))
+		})
+	}
+
+	// The given rates are left unchanged.
+	assert.Equal(t, "GBP", rates[0].Currency)
+	assert.InDelta(t, 0.8551, rates[0].Rate, 0)
+}
+
+func TestPresentationFluctuations(t *testing.T) {
+	fluctuations := models.Fluctuations{
+		StartDate: "2024-03-01",
+		EndDate:   "2024-03-28",
+		Rates: map[string]models.Fluctuation{
+			"USD": {StartRate: 1.25, EndRate: 1.0, Change: -0.25, ChangePercent: -20},
+			"GBP": {StartRate: 0.8, EndRate: 0.85, Change: 0.05, ChangePercent: 6.25},
+		},
+	}
+
+	present := presentation{sort: sortByRate, desc: true, invert: true, round: true, precision: 2}
+	presented := present.fluctuations(fluctuations)
+
+	assert.Equal(t, "2024-03-01", presented.StartDate)
+	usd := presented.Rates["USD"]
+	assert.InDelta(t, 0.8, usd.StartRate, 0)
+	assert.InDelta(t, 1.0, usd.EndRate, 0)
+	assert.InDelta(t, 0.2, usd.Change, 0)
+	assert.InDelta(t, 25, usd.ChangePercent, 1e-9)
+	gbp := presented.Rates["GBP"]
+	assert.InDelta(t, 1.25, gbp.StartRate, 0)
+	assert.InDelta(t, 1.18, gbp.EndRate, 0)
+	assert.InDelta(t, -0.07, gbp.Change, 0)
+
+	assert.Equal(t, []string{"GBP", "USD"}, present.fluctuationOrder(presented.Rates))
+	assert.Equal(t, []string{"USD", "GBP"}, presentation{sort: sortByRate}.fluctuationOrder(presented.Rates))
+
+	// The given fluctuations are left unchanged.
+	assert.InDelta(t, 1.25, fluctuations.Rates["USD"].StartRate, 0)
+}
+
+func TestPresentationStatistics(t *testing.T) {
+	stats := models.RateStatisticsMap{
+		"USD": {AvgRate: 1.08456, MinRate: 1.07001, MaxRate: 1.09999, StdDev: 0.00812, Count: 20, ChangePercent: 1.23456},
+	}
+
+	presented := presentation{round: true, precision: 2}.statistics(stats)
+
+	usd := presented["USD"]
+	assert.InDelta(t, 1.08, usd.AvgRate, 0)
+	assert.InDelta(t, 1.07, usd.MinRate, 0)
+	assert.InDelta(t, 1.1, usd.MaxRate, 0)
+	assert.InDelta(t, 0.01, usd.StdDev, 0)
+	assert.Equal(t, 20, usd.Count)
+	assert.InDelta(t, 1.23456, usd.ChangePercent, 0)
+	assert.InDelta(t, 1.08456, stats["USD"].AvgRate, 0)
+}
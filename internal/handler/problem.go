@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/light-bringer/rates-exchanger-service/internal/logging"
+	"github.com/light-bringer/rates-exchanger-service/internal/service"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+)
+
+// writeProblem writes an RFC 7807 problem details response.
+// Client errors are logged at debug level, server errors at error level.
+func writeProblem(
+	w http.ResponseWriter,
+	r *http.Request,
+	status int,
+	code models.ErrorCode,
+	param string,
+	detail string,
+) {
+	problem := models.Problem{
+		Type:      problemType,
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    detail,
+		Instance:  r.URL.Path,
+		Code:      code,
+		Param:     param,
+		RequestID: requestID(r),
+	}
+
+	if status >= http.StatusInternalServerError {
+		requestLogger(r).Error("Request failed", "status", status, "code", code)
+	} else {
+		requestLogger(r).Debug("Request rejected", "status", status, "code", code, "param", param, "detail", detail)
+	}
+
+	clearValidators(w)
+	w.Header().Set(contentTypeHeader, problemContentType)
+	w.Header().Set(requestIDHeader, problem.RequestID)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(problem)
+}
+
+// badRequest writes a 400 problem for an invalid query or path parameter.
+func badRequest(w http.ResponseWriter, r *http.Request, err error) {
+	code := models.ErrorCodeInvalidParameter
+	if errors.Is(err, service.ErrUnknownCurrency) {
+		code = models.ErrorCodeUnknownCurrency
+	}
+	writeProblem(w, r, http.StatusBadRequest, code, paramName(err), err.Error())
+}
+
+// serviceError writes the problem matching an error returned by the RatesService.
+// Known client errors are reported with their message; any other error is logged
+// and reported as an opaque internal error, so that no internal details reach the client.
+// The param names the request parameter carrying the currency, if an unknown currency is reported.
+func serviceError(w http.ResponseWriter, r *http.Request, err error, param string) {
+	switch {
+	case errors.Is(err, service.ErrUnknownCurrency):
+		writeProblem(w, r, http.StatusBadRequest, models.ErrorCodeUnknownCurrency, param, err.Error())
+	case errors.Is(err, service.ErrRatesNotFound):
+		writeProblem(w, r, http.StatusNotFound, models.ErrorCodeRatesNotFound, "", err.Error())
+	default:
+		requestLogger(r).Error("Internal error", "error", err)
+		writeProblem(w, r, http.StatusInternalServerError, models.ErrorCodeInternal, "",
+			"An internal error occurred. Please retry later and quote the request ID if the problem persists.")
+	}
+}
+
+// requestID returns the ID of the request, as set by withRequestLogging.
+// Requests served without it get a new ID, stored on the request so that all problems
+// of the same request carry the same ID.
+func requestID(r *http.Request) string {
+	if id := r.Header.Get(requestIDHeader); id != "" {
+		return id
+	}
+
+	id := logging.NewRequestID()
+	r.Header.Set(requestIDHeader, id)
+	return id
+}
+
+// problemInterceptor rewrites the plain text 404 and 405 responses of http.ServeMux into problems.
+type problemInterceptor struct {
+	http.ResponseWriter
+	r           *http.Request
+	intercepted bool
+}
+
+func (p *problemInterceptor) WriteHeader(status int) {
+	switch status {
+	case http.StatusNotFound:
+		p.intercepted = true
+		writeProblem(p.ResponseWriter, p.r, status, models.ErrorCodeNotFound, "", "No route matches the request path.")
+	case http.StatusMethodNotAllowed:
+		p.intercepted = true
+		writeProblem(p.ResponseWriter, p.r, status, models.ErrorCodeMethodNotAllowed, "",
+			"Method "+p.r.Method+" is not allowed; allowed methods: "+p.ResponseWriter.Header().Get("Allow")+".")
+	default:
+		p.ResponseWriter.WriteHeader(status)
+	}
+}
+
+func (p *problemInterceptor) Write(b []byte) (int, error) {
+	if p.intercepted {
+		// Discard the plain text body written by the mux.
+		return len(b), nil
+	}
+	return p.ResponseWriter.Write(b) //nolint:wrapcheck // transparent writer
+}
+
+// withProblems serves requests with the mux, reporting unmatched routes and methods as problems.
+func withProblems(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, pattern := mux.Handler(r); pattern != "" {
+			mux.ServeHTTP(w, r)
+			return
+		}
+		mux.ServeHTTP(&problemInterceptor{ResponseWriter: w, r: r}, r)
+	})
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeProblem(t *testing.T, rec *httptest.ResponseRecorder) models.Problem {
+	t.Helper()
+	assert.Equal(t, problemContentType, rec.Header().Get(contentTypeHeader))
+
+	var problem models.Problem
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&problem))
+	return problem
+}
+
+func TestRoutesProblems(t *testing.T) {
+	routes := NewHandler(nil, models.APIConfig{
+		MaxTimeSeriesDays: 31,
+		SpecValidation:    models.ValidationStrict,
+	}).Routes()
+
+	testCases := []struct {
+		name           string
+		method         string
+		target         string
+		expectedStatus int
+		expectedCode   models.ErrorCode
+		expectedParam  string
+	}{
+		{
+			name:           "Method not allowed",
+			method:         http.MethodPost,
+			target:         "/rates/latest",
+			expectedStatus: http.StatusMethodNotAllowed,
+			expectedCode:   models.ErrorCodeMethodNotAllowed,
+		},
+		{
+			name:           "Unknown route",
+			method:         http.MethodGet,
+			target:         "/currencies/unknown/route",
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   models.ErrorCodeNotFound,
+		},
+		{
+			name:           "Invalid limit",
+			method:         http.MethodGet,
+			target:         "/rates/latest?limit=ten",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   models.ErrorCodeInvalidParameter,
+			expectedParam:  "limit",
+		},
+		{
+			name:           "Invalid date",
+			method:         http.MethodGet,
+			target:         "/rates/2024-13-01",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   models.ErrorCodeInvalidParameter,
+			expectedParam:  "date",
+		},
+		{
+			name:           "Range too large",
+			method:         http.MethodGet,
+			target:         "/rates/timeseries?start=2024-01-01&end=2024-03-01",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   models.ErrorCodeRangeTooLarge,
+			expectedParam:  "end",
+		},
+		{
+			name:           "Unsupported presentation",
+			method:         http.MethodGet,
+			target:         "/rates/analyze?invert=true",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   models.ErrorCodeInvalidParameter,
+			expectedParam:  "invert",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(tc.method, tc.target, nil)
+			req.Header.Set(requestIDHeader, "test-request")
+
+			routes.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			problem := decodeProblem(t, rec)
+			assert.Equal(t, tc.expectedStatus, problem.Status)
+			assert.Equal(t, tc.expectedCode, problem.Code)
+			assert.Equal(t, tc.expectedParam, problem.Param)
+			assert.Equal(t, "test-request", problem.RequestID)
+		})
+	}
+}
+
+func TestRoutesMethodNotAllowedAllowHeader(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewHandler(nil, models.APIConfig{}).Routes().ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/convert", nil))
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, "GET, HEAD", rec.Header().Get("Allow"))
+}
+
+func TestServiceErrorHidesInternalErrors(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/rates/latest", nil)
+
+	serviceError(rec, req, errors.New("failed to execute query: password authentication failed"), "base")
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	problem := decodeProblem(t, rec)
+	assert.Equal(t, models.ErrorCodeInternal, problem.Code)
+	assert.NotContains(t, problem.Detail, "password")
+	assert.NotEmpty(t, problem.RequestID)
+	assert.Equal(t, problem.RequestID, rec.Header().Get(requestIDHeader))
+}
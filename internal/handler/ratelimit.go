@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/light-bringer/rates-exchanger-service/internal/ratelimit"
+	"github.com/light-bringer/rates-exchanger-service/models"
+)
+
+// withRateLimit takes a token of the client from the bucket of the route group of each request,
+// and answers with a 429 if none is left. Responses of limited routes carry the RateLimit headers
+// of the IETF draft. Requests are served unlimited if the store fails, so that an unavailable
+// shared store does not take the API down.
+func (h *Handler) withRateLimit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		policy, limited := h.limiter.Policy(r.URL.Path)
+		if !limited {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		result, err := h.limiter.Take(r.Context(), policy, rateLimitClient(r))
+		if err != nil {
+			requestLogger(r).Error("Failed to take rate limit token, serving unlimited", "error", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		setRateLimitHeaders(w.Header(), result)
+		if !result.Allowed {
+			retryAfter := max(ceilSeconds(result.RetryAfter), 1)
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			writeProblem(w, r, http.StatusTooManyRequests, models.ErrorCodeRateLimited, "",
+				fmt.Sprintf("Rate limit of %s exceeded, retry in %d seconds", policy.Group, retryAfter))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// rateLimitClient identifies the client of a request by the ID of its API key, if the key was authenticated,
+// or else by its IP address. Keys that were not authenticated are ignored, so that clients cannot escape
+// the limit of their address, nor fill the store, by sending made-up keys.
+func rateLimitClient(r *http.Request) string {
+	if key, ok := authenticatedKey(r.Context()); ok {
+		return "key:" + strconv.FormatInt(key.ID, 10)
+	}
+	return "ip:" + clientIP(r)
+}
+
+// setRateLimitHeaders sets the RateLimit headers of a response: the capacity of the bucket,
+// the requests left, the seconds until it is full again and the policy of the route group.
+func setRateLimitHeaders(header http.Header, result ratelimit.Result) {
+	header.Set("RateLimit-Limit", strconv.Itoa(result.Policy.Burst))
+	header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	header.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+	header.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d;burst=%d",
+		result.Policy.Requests, ceilSeconds(result.Policy.Period), result.Policy.Burst))
+}
+
+// ceilSeconds returns the duration in whole seconds, rounded up.
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
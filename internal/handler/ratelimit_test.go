@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/light-bringer/rates-exchanger-service/internal/ratelimit"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimit(t *testing.T) {
+	limiter, err := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), models.RateLimitConfig{
+		Groups: []models.RateLimitGroup{
+			{Name: "health", Paths: []string{"/health"}, Requests: 2, Period: time.Minute},
+			{Name: "docs", Paths: []string{"/openapi.yaml"}},
+		},
+	})
+	require.NoError(t, err)
+	routes := NewHandler(nil, models.APIConfig{SpecValidation: models.ValidationStrict}).
+		WithRateLimit(limiter).
+		Routes()
+
+	serve := func(target string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		for name := range header {
+			req.Header.Set(name, header.Get(name))
+		}
+		rec := httptest.NewRecorder()
+		routes.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve("/health/live", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", rec.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "30", rec.Header().Get("RateLimit-Reset"))
+	assert.Equal(t, "2;w=60;burst=2", rec.Header().Get("RateLimit-Policy"))
+
+	rec = serve("/health", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+
+	rec = serve("/health/live", nil)
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "30", rec.Header().Get("Retry-After"))
+	assert.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+	problem := decodeProblem(t, rec)
+	assert.Equal(t, models.ErrorCodeRateLimited, problem.Code)
+	assert.NotEmpty(t, problem.RequestID)
+
+	// Keys that were not authenticated do not escape the limit of the address.
+	for i := range 5 {
+		rec = serve("/health/live", http.Header{"X-Api-Key": {fmt.Sprintf("rx_random%d", i)}})
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	}
+
+	// Routes of groups without a limit and of no group are not limited.
+	rec = serve("/openapi.yaml", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("RateLimit-Limit"))
+	rec = serve("/openapi.json", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("RateLimit-Limit"))
+}
+
+func TestRateLimitClient(t *testing.T) {
+	testCases := []struct {
+		name   string
+		header http.Header
+		key    *models.APIKey
+		client string
+	}{
+		{
+			name:   "Client IP",
+			client: "ip:192.0.2.1",
+		},
+		{
+			name:   "Unauthenticated API key",
+			header: http.Header{"X-Api-Key": {"secret"}},
+			client: "ip:192.0.2.1",
+		},
+		{
+			name:   "Authenticated API key",
+			header: http.Header{"Authorization": {"Bearer secret"}},
+			key:    &models.APIKey{ID: 7},
+			client: "key:7",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/rates/latest", nil)
+			for name := range tc.header {
+				req.Header.Set(name, tc.header.Get(name))
+			}
+			if tc.key != nil {
+				req = req.WithContext(context.WithValue(req.Context(), apiKeyContextKey{}, *tc.key))
+			}
+			assert.Equal(t, tc.client, rateLimitClient(req))
+		})
+	}
+}
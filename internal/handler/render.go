@@ -0,0 +1,390 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+)
+
+// outputFormat is a response representation a client can negotiate.
+type outputFormat string
+
+const (
+	formatJSON outputFormat = "json"
+	formatCSV  outputFormat = "csv"
+	formatXML  outputFormat = "xml"
+)
+
+// errNotAcceptable is returned when none of the accepted media types can be produced.
+var errNotAcceptable = errors.New("none of the accepted media types can be produced")
+
+// mediaTypeFormats maps the supported media types to their output format.
+//
+//nolint:gochecknoglobals // read-only lookup table
+var mediaTypeFormats = map[string]outputFormat{
+	"application/json": formatJSON,
+	"text/csv":         formatCSV,
+	"application/xml":  formatXML,
+	"text/xml":         formatXML,
+}
+
+// negotiateFormat selects the response format of a request among the supported formats.
+// A format query parameter takes precedence over the Accept header. Without either,
+// or for wildcard media ranges, the first supported format is used.
+// The function returns a parameter error for an unsupported format parameter,
+// and errNotAcceptable if no accepted media type is supported.
+func negotiateFormat(r *http.Request, supported ...outputFormat) (outputFormat, error) {
+	if formatStr := r.URL.Query().Get("format"); formatStr != "" {
+		requested := outputFormat(strings.ToLower(formatStr))
+		for _, format := range supported {
+			if format == requested {
+				return format, nil
+			}
+		}
+		return "", paramErrorf("format", "unsupported format %q", formatStr)
+	}
+
+	accept := r.Header.Get("Accept")
+	if accept == "" {
+		return supported[0], nil
+	}
+
+	type mediaRange struct {
+		mediaType string
+		quality   float64
+	}
+
+	ranges := make([]mediaRange, 0)
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+		if quality > 0 {
+			ranges = append(ranges, mediaRange{mediaType: mediaType, quality: quality})
+		}
+	}
+
+	// Higher quality first; equal qualities keep the client's order.
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].quality > ranges[j].quality })
+
+	for _, accepted := range ranges {
+		if accepted.mediaType == "*/*" || accepted.mediaType == "application/*" {
+			return supported[0], nil
+		}
+		for _, format := range supported {
+			if mediaTypeFormats[accepted.mediaType] == format {
+				return format, nil
+			}
+		}
+	}
+
+	return "", errNotAcceptable
+}
+
+// negotiate selects the response format of a request and writes a problem if none is acceptable.
+// The function reports whether the request can be served.
+func negotiate(w http.ResponseWriter, r *http.Request, supported ...outputFormat) (outputFormat, bool) {
+	w.Header().Add("Vary", "Accept")
+
+	format, err := negotiateFormat(r, supported...)
+	if errors.Is(err, errNotAcceptable) {
+		writeProblem(w, r, http.StatusNotAcceptable, models.ErrorCodeNotAcceptable, "", err.Error())
+		return "", false
+	}
+	if err != nil {
+		badRequest(w, r, err)
+		return "", false
+	}
+
+	return format, true
+}
+
+// writeJSON writes the response as JSON.
+func writeJSON(w http.ResponseWriter, response interface{}) {
+	w.Header().Set(contentTypeHeader, contentType)
+	json.NewEncoder(w).Encode(response)
+}
+
+// writeJSONStatus writes the response as JSON with the given status.
+func writeJSONStatus(w http.ResponseWriter, status int, response interface{}) {
+	w.Header().Set(contentTypeHeader, contentType)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
+
+// writeXML writes the envelope as an XML document.
+func writeXML(w http.ResponseWriter, envelope models.Envelope) {
+	w.Header().Set(contentTypeHeader, xmlContentType)
+	w.Write([]byte(xml.Header))
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	encoder.Encode(envelope)
+}
+
+// csvWriter writes CSV output using the decimal separator requested by the client.
+type csvWriter struct {
+	writer           *csv.Writer
+	decimalSeparator string
+}
+
+// newCSVWriter returns a csvWriter for the request.
+// The decimal_separator query parameter overrides the configured decimal separator.
+// With a decimal comma, fields are separated by semicolons, as spreadsheet applications expect.
+func (h *Handler) newCSVWriter(w http.ResponseWriter, r *http.Request) (*csvWriter, error) {
+	separator := h.config.CSVDecimalSeparator
+	if override := r.URL.Query().Get("decimal_separator"); override != "" {
+		separator = override
+	}
+
+	writer := csv.NewWriter(w)
+	switch separator {
+	case "", ".":
+		separator = "."
+	case ",":
+		writer.Comma = ';'
+	default:
+		return nil, paramErrorf("decimal_separator", "unsupported decimal separator %q", separator)
+	}
+
+	w.Header().Set(contentTypeHeader, csvContentType)
+	return &csvWriter{writer: writer, decimalSeparator: separator}, nil
+}
+
+// number formats a number with the writer's decimal separator.
+func (c *csvWriter) number(value float64) string {
+	formatted := strconv.FormatFloat(value, 'f', -1, 64)
+	if c.decimalSeparator != "." {
+		formatted = strings.Replace(formatted, ".", c.decimalSeparator, 1)
+	}
+	return formatted
+}
+
+// write writes a record.
+func (c *csvWriter) write(record ...string) {
+	c.writer.Write(record)
+}
+
+// flush flushes the buffered records to the response.
+func (c *csvWriter) flush() {
+	c.writer.Flush()
+}
+
+// ratesEnvelope returns the rates in the shape of the ECB reference rate document.
+func ratesEnvelope(base string, cubes []models.DateCube) models.Envelope {
+	subject := "Reference rates"
+	if base != baseCurrency {
+		subject += " (base " + base + ")"
+	}
+
+	return models.Envelope{
+		Subject: subject,
+		Sender:  models.Sender{Name: "European Central Bank"},
+		Cube:    models.StructuredCube{Cubes: cubes},
+	}
+}
+
+// dateCube converts the rates of a day into a cube.
+func dateCube(date string, rates models.LatestExchangeRates) models.DateCube {
+	cube := models.DateCube{Time: date, Entries: make([]models.RateEntry, 0, len(rates))}
+	for _, rate := range rates {
+		cube.Entries = append(cube.Entries, models.RateEntry{
+			Currency: rate.Currency,
+			Rate:     strconv.FormatFloat(rate.Rate, 'f', -1, 64),
+		})
+	}
+	return cube
+}
+
+// renderDayRates writes the rates of a single day in the negotiated format, presented as requested.
+// The rates are added to the JSON response.
+func (h *Handler) renderDayRates(
+	w http.ResponseWriter,
+	r *http.Request,
+	format outputFormat,
+	present presentation,
+	response map[string]interface{},
+	base string,
+	date string,
+	rates models.LatestExchangeRates,
+) {
+	rates = present.rates(rates)
+
+	switch format {
+	case formatCSV:
+		writer, err := h.newCSVWriter(w, r)
+		if err != nil {
+			badRequest(w, r, err)
+			return
+		}
+		writer.write("date", "base", "currency", "rate")
+		for _, rate := range rates {
+			writer.write(date, base, rate.Currency, writer.number(rate.Rate))
+		}
+		writer.flush()
+	case formatXML:
+		writeXML(w, ratesEnvelope(base, []models.DateCube{dateCube(date, rates)}))
+	case formatJSON:
+		fallthrough
+	default:
+		response["rates"] = rates
+		writeJSON(w, response)
+	}
+}
+
+// renderTimeSeries writes a time series in the negotiated format, with the rates of each day presented
+// as requested. CSV rows are ordered by date; XML cubes are ordered newest first, like the ECB history.
+// The rates are added to the JSON response.
+func (h *Handler) renderTimeSeries(
+	w http.ResponseWriter,
+	r *http.Request,
+	format outputFormat,
+	present presentation,
+	response map[string]interface{},
+	base string,
+	series models.TimeSeries,
+) {
+	dates := sortedKeys(series)
+
+	dayRates := func(date string) models.LatestExchangeRates {
+		return present.seriesRates(series[date])
+	}
+
+	switch format {
+	case formatCSV:
+		writer, err := h.newCSVWriter(w, r)
+		if err != nil {
+			badRequest(w, r, err)
+			return
+		}
+		writer.write("date", "base", "currency", "rate")
+		for _, date := range dates {
+			for _, rate := range dayRates(date) {
+				writer.write(date, base, rate.Currency, writer.number(rate.Rate))
+			}
+		}
+		writer.flush()
+	case formatXML:
+		cubes := make([]models.DateCube, 0, len(dates))
+		for i := len(dates) - 1; i >= 0; i-- {
+			cubes = append(cubes, dateCube(dates[i], dayRates(dates[i])))
+		}
+		writeXML(w, ratesEnvelope(base, cubes))
+	case formatJSON:
+		fallthrough
+	default:
+		rates := make(map[string]models.LatestExchangeRates, len(dates))
+		for _, date := range dates {
+			rates[date] = dayRates(date)
+		}
+		response["rates"] = rates
+		writeJSON(w, response)
+	}
+}
+
+// renderStatisticsCSV writes the rate statistics as CSV, one row per currency.
+func (h *Handler) renderStatisticsCSV(
+	w http.ResponseWriter,
+	r *http.Request,
+	base string,
+	stats models.RateStatisticsMap,
+) {
+	writer, err := h.newCSVWriter(w, r)
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	writer.write("base", "currency", "avg", "min", "max", "median", "std_dev", "count", "first", "last",
+		"change", "change_percent", "first_date", "last_date", "min_date", "max_date")
+	for _, currency := range sortedKeys(stats) {
+		stat := stats[currency]
+		writer.write(base, currency,
+			writer.number(stat.AvgRate), writer.number(stat.MinRate), writer.number(stat.MaxRate),
+			writer.number(stat.MedianRate), writer.number(stat.StdDev), strconv.Itoa(stat.Count),
+			writer.number(stat.FirstRate), writer.number(stat.LastRate),
+			writer.number(stat.Change), writer.number(stat.ChangePercent),
+			stat.FirstDate, stat.LastDate, stat.MinDate, stat.MaxDate)
+	}
+	writer.flush()
+}
+
+// renderFluctuationCSV writes the fluctuations as CSV, one row per currency in the requested order.
+func (h *Handler) renderFluctuationCSV(
+	w http.ResponseWriter,
+	r *http.Request,
+	present presentation,
+	base string,
+	fluctuations models.Fluctuations,
+) {
+	writer, err := h.newCSVWriter(w, r)
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	writer.write("base", "currency", "start_date", "end_date", "start_rate", "end_rate", "change", "change_percent")
+	for _, currency := range present.fluctuationOrder(fluctuations.Rates) {
+		fluctuation := fluctuations.Rates[currency]
+		writer.write(base, currency, fluctuations.StartDate, fluctuations.EndDate,
+			writer.number(fluctuation.StartRate), writer.number(fluctuation.EndRate),
+			writer.number(fluctuation.Change), writer.number(fluctuation.ChangePercent))
+	}
+	writer.flush()
+}
+
+// orderedFluctuations encodes fluctuations as an object keyed by currency in the given order,
+// like models.LatestExchangeRates encodes the rates of a day.
+type orderedFluctuations struct {
+	order []string
+	rates map[string]models.Fluctuation
+}
+
+// MarshalJSON encodes the fluctuations in the order of their currencies.
+func (f orderedFluctuations) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, currency := range f.order {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(currency)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to encode currency")
+		}
+		value, err := json.Marshal(f.rates[currency])
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to encode fluctuation")
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// sortedKeys returns the keys of a map in ascending order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiateFormat(t *testing.T) {
+	testCases := []struct {
+		name          string
+		target        string
+		accept        string
+		expectedError error
+		expected      outputFormat
+	}{
+		{name: "Default", target: "/rates/latest", expected: formatJSON},
+		{name: "Wildcard", target: "/rates/latest", accept: "*/*", expected: formatJSON},
+		{name: "CSV", target: "/rates/latest", accept: "text/csv", expected: formatCSV},
+		{name: "XML", target: "/rates/latest", accept: "text/xml", expected: formatXML},
+		{name: "Quality order", target: "/rates/latest", accept: "application/json;q=0.5, text/csv", expected: formatCSV},
+		{name: "Format overrides Accept", target: "/rates/latest?format=xml", accept: "text/csv", expected: formatXML},
+		{name: "Not acceptable", target: "/rates/latest", accept: "image/png", expectedError: errNotAcceptable},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			req.Header.Set("Accept", tc.accept)
+
+			format, err := negotiateFormat(req, formatJSON, formatCSV, formatXML)
+			if tc.expectedError != nil {
+				require.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, format)
+		})
+	}
+
+	_, err := negotiateFormat(httptest.NewRequest(http.MethodGet, "/rates/analyze?format=xml", nil), formatJSON, formatCSV)
+	require.Error(t, err)
+	assert.Equal(t, "format", paramName(err))
+}
+
+func TestRenderDayRates(t *testing.T) {
+	rates := models.LatestExchangeRates{
+		{Currency: "JPY", Rate: 162.15},
+		{Currency: "USD", Rate: 1.0833},
+	}
+
+	t.Run("CSV with decimal point", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		h := NewHandler(nil, models.APIConfig{CSVDecimalSeparator: "."})
+
+		h.renderDayRates(rec, httptest.NewRequest(http.MethodGet, "/", nil), formatCSV, presentation{}, nil, "EUR", "2024-03-01", rates)
+
+		assert.Equal(t, csvContentType, rec.Header().Get(contentTypeHeader))
+		assert.Equal(t, "date,base,currency,rate\n2024-03-01,EUR,JPY,162.15\n2024-03-01,EUR,USD,1.0833\n", rec.Body.String())
+	})
+
+	t.Run("CSV with decimal comma", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		h := NewHandler(nil, models.APIConfig{CSVDecimalSeparator: "."})
+		req := httptest.NewRequest(http.MethodGet, "/?decimal_separator=,", nil)
+
+		h.renderDayRates(rec, req, formatCSV, presentation{}, nil, "EUR", "2024-03-01", rates)
+
+		assert.Equal(t, "date;base;currency;rate\n2024-03-01;EUR;JPY;162,15\n2024-03-01;EUR;USD;1,0833\n", rec.Body.String())
+	})
+
+	t.Run("XML round trip", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		h := NewHandler(nil, models.APIConfig{})
+
+		h.renderDayRates(rec, httptest.NewRequest(http.MethodGet, "/", nil), formatXML, presentation{}, nil, "EUR", "2024-03-01", rates)
+
+		var envelope models.Envelope
+		require.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &envelope))
+		require.Len(t, envelope.Cube.Cubes, 1)
+		assert.Equal(t, "2024-03-01", envelope.Cube.Cubes[0].Time)
+		assert.Equal(t, []models.RateEntry{{Currency: "JPY", Rate: "162.15"}, {Currency: "USD", Rate: "1.0833"}},
+			envelope.Cube.Cubes[0].Entries)
+	})
+}
+
+func TestOrderedFluctuationsJSON(t *testing.T) {
+	rates := map[string]models.Fluctuation{
+		"USD": {StartRate: 1.08, EndRate: 1.09, Change: 0.01, ChangePercent: 0.9259},
+		"GBP": {StartRate: 0.86, EndRate: 0.85, Change: -0.01, ChangePercent: -1.1628},
+		"JPY": {StartRate: 160, EndRate: 162, Change: 2, ChangePercent: 1.25},
+	}
+	present := presentation{sort: sortByRate, desc: true}
+
+	data, err := json.Marshal(orderedFluctuations{order: present.fluctuationOrder(rates), rates: rates})
+	require.NoError(t, err)
+
+	assert.Equal(t, `{"JPY":{"start_rate":160,"end_rate":162,"change":2,"change_percent":1.25},`+
+		`"USD":{"start_rate":1.08,"end_rate":1.09,"change":0.01,"change_percent":0.9259},`+
+		`"GBP":{"start_rate":0.86,"end_rate":0.85,"change":-0.01,"change_percent":-1.1628}}`, string(data))
+}
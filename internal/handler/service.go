@@ -1,43 +1,58 @@
 package handler
 
 import (
-	"encoding/json"
-	"log/slog"
+	"fmt"
 	"net/http"
-	"strconv"
-	"time"
+
+	"github.com/light-bringer/rates-exchanger-service/models"
 )
 
 // Handler represents an HTTP handler for exchange rates.
 
 // GetLatestRates handles requests for the latest exchange rates.
 func (h *Handler) GetLatestRates(w http.ResponseWriter, r *http.Request) {
-	limit := uint64(0)
-	var err error
-	limitStr := r.URL.Query().Get("limit")
-	if limitStr != "" {
-		limit, err = strconv.ParseUint(limitStr, 10, 64)
-		if err != nil {
-			slog.Error("Invalid limit", "error", err)
-			http.Error(w, "Invalid limit", http.StatusBadRequest)
-			return
-		}
+	format, ok := negotiate(w, r, formatJSON, formatCSV, formatXML)
+	if !ok {
+		return
+	}
+
+	limit, err := parseLimit(r)
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	base, err := parseBase(r)
+	if err != nil {
+		badRequest(w, r, err)
+		return
 	}
 
-	rates, err := h.service.FetchLatestExchangeRates(limit)
+	present, err := parsePresentation(r)
 	if err != nil {
-		slog.Error("Failed to fetch latest exchange rates", "error", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		badRequest(w, r, err)
+		return
+	}
+	present.limit = limit
+
+	if h.notModified(w, r, format, "") {
+		return
+	}
+
+	rates, date, err := h.service.FetchLatestExchangeRates(r.Context(), base)
+	if err != nil {
+		serviceError(w, r, err, "base")
 		return
 	}
 
 	response := map[string]interface{}{
-		"base":  baseCurrency,
-		"rates": rates,
+		"base": base,
+	}
+	if date != "" {
+		response["date"] = date
 	}
 
-	w.Header().Set(contentTypeHeader, contentType)
-	json.NewEncoder(w).Encode(response)
+	h.renderDayRates(w, r, format, present, response, base, date, rates)
 }
 
 // HealthCheck handles requests for the health check.
@@ -47,92 +62,393 @@ func (h *Handler) HealthCheck(w http.ResponseWriter, _ *http.Request) {
 
 // GetExchangeRate handles requests for the exchange rate for a specific date.
 func (h *Handler) GetExchangeRate(w http.ResponseWriter, r *http.Request) {
-	date := r.PathValue("calculationDay")
-
-	slog.Debug("fetching exchange rate for date", "date", date)
+	format, ok := negotiate(w, r, formatJSON, formatCSV, formatXML)
+	if !ok {
+		return
+	}
 
 	// Validate date format (YYYY-MM-DD)
-	_, err := time.Parse("2006-01-02", date)
+	date, err := parseDatePath(r, "calculationDay")
 	if err != nil {
-		slog.Error("Invalid date format", "error", err)
-		http.Error(w, "Invalid date format", http.StatusBadRequest)
+		badRequest(w, r, err)
 		return
 	}
 
-	limit := uint64(0)
-	limitStr := r.URL.Query().Get("limit")
-	if limitStr != "" {
-		limit, err = strconv.ParseUint(limitStr, 10, 64)
-		if err != nil {
-			slog.Error("Invalid limit", "error", err)
-			http.Error(w, "Invalid limit", http.StatusBadRequest)
-			return
-		}
+	requestLogger(r).Debug("fetching exchange rate for date", "date", date)
+
+	limit, err := parseLimit(r)
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	base, err := parseBase(r)
+	if err != nil {
+		badRequest(w, r, err)
+		return
 	}
 
-	rates, err := h.service.FetchRatesForDate(date, limit)
+	fallback, err := parseFallback(r)
 	if err != nil {
-		slog.Error("Failed to fetch latest exchange rates", "error", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		badRequest(w, r, err)
+		return
+	}
+
+	present, err := parsePresentation(r)
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+	present.limit = limit
+
+	if h.notModified(w, r, format, date) {
+		return
+	}
+
+	rates, effectiveDate, err := h.service.FetchRatesForDate(r.Context(), date, base, fallback)
+	if err != nil {
+		serviceError(w, r, err, "base")
 		return
 	}
 
 	response := map[string]interface{}{
-		"date":  date,
-		"base":  baseCurrency,
-		"rates": rates,
+		"date":           effectiveDate,
+		"requested_date": date,
+		"base":           base,
 	}
 
-	w.Header().Set(contentTypeHeader, contentType)
-	json.NewEncoder(w).Encode(response)
+	h.renderDayRates(w, r, format, present, response, base, effectiveDate, rates)
 }
 
 // GetStatistics handles requests for the exchange rate statistics.
 func (h *Handler) GetStatistics(w http.ResponseWriter, r *http.Request) {
-	slog.Debug("logging statistics for exchange rates", "request", r.Header)
-
-	days := uint64(defaultRange)
-	var err error
-	dayStr := r.URL.Query().Get("range")
-	if dayStr != "" {
-		days, err = strconv.ParseUint(dayStr, 10, 64)
-		if err != nil {
-			slog.Error("Invalid limit", "error", err)
-			http.Error(w, "Invalid limit", http.StatusBadRequest)
+	format, ok := negotiate(w, r, formatJSON, formatCSV)
+	if !ok {
+		return
+	}
+
+	days, err := parseUintParam(r, "range", defaultRange)
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	base, err := parseBase(r)
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	symbols, err := h.parseKnownSymbols(r)
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	// Only the precision applies to statistics: the statistics of inverted rates are not the inverted
+	// statistics, and there is no single rate to sort the currencies by.
+	if err = rejectParams(r, "sort", "order", "invert"); err != nil {
+		badRequest(w, r, err)
+		return
+	}
+	var present presentation
+	if present.round, present.precision, err = parsePrecision(r); err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	// An explicit start/end range takes precedence over the relative range.
+	var start, end string
+	if r.URL.Query().Has("start") || r.URL.Query().Has("end") {
+		startDay, endDay, rangeErr := parseDateRange(r)
+		if rangeErr != nil {
+			badRequest(w, r, rangeErr)
 			return
 		}
+		start, end = startDay.Format(dateLayout), endDay.Format(dateLayout)
 	}
 
-	stats, err := h.service.GetRateStatistics(days)
+	if h.notModified(w, r, format, end) {
+		return
+	}
+
+	stats, err := h.service.GetRateStatistics(r.Context(), base, symbols, start, end, days)
 	if err != nil {
-		slog.Error("Failed to fetch rate statistics", "error", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		serviceError(w, r, err, "base")
+		return
+	}
+	stats = present.statistics(stats)
+
+	if format == formatCSV {
+		h.renderStatisticsCSV(w, r, base, stats)
 		return
 	}
 
 	detailedStats := make(map[string]interface{})
 	for currency, stat := range stats {
 		detailedStats[currency] = map[string]interface{}{
-			"average": stat.AvgRate,
-			"min":     stat.MinRate,
-			"max":     stat.MaxRate,
+			"avg":            stat.AvgRate,
+			"min":            stat.MinRate,
+			"max":            stat.MaxRate,
+			"median":         stat.MedianRate,
+			"std_dev":        stat.StdDev,
+			"count":          stat.Count,
+			"first":          stat.FirstRate,
+			"last":           stat.LastRate,
+			"change":         stat.Change,
+			"change_percent": stat.ChangePercent,
+			"first_date":     stat.FirstDate,
+			"last_date":      stat.LastDate,
+			"min_date":       stat.MinDate,
+			"max_date":       stat.MaxDate,
 		}
 	}
 
 	response := map[string]interface{}{
-		"base":          baseCurrency,
+		"base":          base,
 		"rates_analyze": detailedStats,
 	}
 
-	w.Header().Set(contentTypeHeader, contentType)
-	json.NewEncoder(w).Encode(response)
+	writeJSON(w, response)
+}
+
+// GetTimeSeries handles requests for the exchange rates over a range of dates.
+func (h *Handler) GetTimeSeries(w http.ResponseWriter, r *http.Request) {
+	format, ok := negotiate(w, r, formatJSON, formatCSV, formatXML)
+	if !ok {
+		return
+	}
+
+	start, end, err := parseDateRange(r)
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	if spanDays(start, end) > h.config.MaxTimeSeriesDays {
+		writeProblem(w, r, http.StatusBadRequest, models.ErrorCodeRangeTooLarge, "end",
+			fmt.Sprintf("The date range exceeds the maximum of %d days.", h.config.MaxTimeSeriesDays))
+		return
+	}
+
+	symbols, err := h.parseKnownSymbols(r)
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	base, err := parseBase(r)
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	present, err := parsePresentation(r)
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	if h.notModified(w, r, format, end.Format(dateLayout)) {
+		return
+	}
+
+	series, err := h.service.FetchTimeSeries(r.Context(), start.Format(dateLayout), end.Format(dateLayout), base, symbols)
+	if err != nil {
+		serviceError(w, r, err, "base")
+		return
+	}
+
+	response := map[string]interface{}{
+		"base":       base,
+		"start_date": start.Format(dateLayout),
+		"end_date":   end.Format(dateLayout),
+	}
+
+	h.renderTimeSeries(w, r, format, present, response, base, series)
+}
+
+// GetFluctuation handles requests for the change in exchange rates between two dates.
+func (h *Handler) GetFluctuation(w http.ResponseWriter, r *http.Request) {
+	format, ok := negotiate(w, r, formatJSON, formatCSV)
+	if !ok {
+		return
+	}
+
+	start, end, err := parseDateRange(r)
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	symbols, err := h.parseKnownSymbols(r)
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	base, err := parseBase(r)
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	present, err := parsePresentation(r)
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	if h.notModified(w, r, format, end.Format(dateLayout)) {
+		return
+	}
+
+	fluctuations, err := h.service.FetchFluctuation(r.Context(), start.Format(dateLayout), end.Format(dateLayout), base, symbols)
+	if err != nil {
+		serviceError(w, r, err, "base")
+		return
+	}
+	fluctuations = present.fluctuations(fluctuations)
+
+	if format == formatCSV {
+		h.renderFluctuationCSV(w, r, present, base, fluctuations)
+		return
+	}
+
+	response := map[string]interface{}{
+		"base":                 base,
+		"requested_start_date": start.Format(dateLayout),
+		"requested_end_date":   end.Format(dateLayout),
+		"start_date":           fluctuations.StartDate,
+		"end_date":             fluctuations.EndDate,
+		"rates":                orderedFluctuations{order: present.fluctuationOrder(fluctuations.Rates), rates: fluctuations.Rates},
+	}
+
+	writeJSON(w, response)
 }
 
+// ConvertAmount handles requests for converting an amount between two currencies.
+func (h *Handler) ConvertAmount(w http.ResponseWriter, r *http.Request) {
+	if _, ok := negotiate(w, r, formatJSON); !ok {
+		return
+	}
+
+	from, err := parseCurrency(r, "from")
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	to, err := parseCurrency(r, "to")
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	amount, err := parseAmount(r)
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	date, err := parseOptionalDate(r, "date")
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	fallback, err := parseFallback(r)
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	mode, err := parseRoundingMode(r)
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	conversion, err := h.service.ConvertAmount(r.Context(), from, to, amount, date, fallback, mode)
+	if err != nil {
+		serviceError(w, r, err, "")
+		return
+	}
+
+	writeJSON(w, conversion)
+}
+
+// Routes returns the HTTP handler serving all API routes.
+// Routes are registered per method, so that other methods are answered with 405 and an Allow header.
+// Unmatched paths and methods are reported as problems, and requests and responses are validated
+// against the OpenAPI specification as configured. With metrics enabled, all requests are counted and timed.
+// Every request gets a request ID and a request-scoped logger, and is written to the access log.
 func (h *Handler) Routes() http.Handler {
 	mux := http.NewServeMux()
-	mux.HandleFunc("/rates/latest", h.GetLatestRates)
-	mux.HandleFunc("/rates/{calculationDay}", h.GetExchangeRate)
-	mux.HandleFunc("/rates/analyze", h.GetStatistics)
-	mux.HandleFunc("/health", h.HealthCheck)
-	return mux
+	mux.HandleFunc("GET /rates/latest", h.GetLatestRates)
+	mux.HandleFunc("GET /rates/{calculationDay}", h.GetExchangeRate)
+	mux.HandleFunc("POST /rates/batch", h.GetRatesBatch)
+	mux.HandleFunc("GET /rates/analyze", h.GetStatistics)
+	mux.HandleFunc("GET /rates/timeseries", h.GetTimeSeries)
+	mux.HandleFunc("GET /rates/fluctuation", h.GetFluctuation)
+	mux.HandleFunc("GET /rates/correlation", h.GetCorrelation)
+	mux.HandleFunc("GET /rates/volatility", h.GetVolatility)
+	mux.HandleFunc("GET /rates/{currency}/indicators", h.GetIndicator)
+	mux.HandleFunc("GET /rates/stream", h.StreamRates)
+	mux.HandleFunc("GET /convert", h.ConvertAmount)
+	mux.HandleFunc("GET /health", h.HealthCheck)
+	mux.HandleFunc("GET /health/live", h.LiveCheck)
+	if h.health != nil {
+		mux.HandleFunc("GET /health/ready", h.ReadyCheck)
+	}
+	mux.HandleFunc("GET /openapi.yaml", h.GetSpecYAML)
+	mux.HandleFunc("GET /openapi.json", h.GetSpecJSON)
+	if h.config.SwaggerUI {
+		mux.HandleFunc("GET /docs", h.GetDocs)
+	}
+	if h.webhooks != nil {
+		mux.HandleFunc("POST /webhooks", h.CreateWebhook)
+		mux.HandleFunc("GET /webhooks", h.ListWebhooks)
+		mux.HandleFunc("GET /webhooks/dead-letters", h.ListDeadLetters)
+		mux.HandleFunc("GET /webhooks/{id}", h.GetWebhook)
+		mux.HandleFunc("PATCH /webhooks/{id}", h.UpdateWebhook)
+		mux.HandleFunc("DELETE /webhooks/{id}", h.DeleteWebhook)
+		mux.HandleFunc("GET /webhooks/{id}/deliveries", h.ListWebhookDeliveries)
+		mux.HandleFunc("POST /webhooks/deliveries/{id}/retry", h.RetryWebhookDelivery)
+	}
+	if h.currencies != nil {
+		mux.HandleFunc("GET /currencies", h.ListCurrencies)
+		mux.HandleFunc("GET /currencies/{code}", h.GetCurrency)
+	}
+	if h.alerts != nil {
+		mux.HandleFunc("POST /alert-rules", h.CreateAlertRule)
+		mux.HandleFunc("GET /alert-rules", h.ListAlertRules)
+		mux.HandleFunc("GET /alert-rules/{id}", h.GetAlertRule)
+		mux.HandleFunc("PATCH /alert-rules/{id}", h.UpdateAlertRule)
+		mux.HandleFunc("DELETE /alert-rules/{id}", h.DeleteAlertRule)
+	}
+	if h.metrics != nil {
+		mux.Handle("GET /metrics", h.metrics.Handler())
+	}
+
+	route := func(r *http.Request) string {
+		_, pattern := mux.Handler(r)
+		return pattern
+	}
+
+	routes := h.withValidation(withProblems(mux))
+	if h.limiter != nil {
+		// Throttled requests are neither validated nor served, but they are counted and logged.
+		routes = h.withRateLimit(routes)
+	}
+	if h.keys != nil {
+		// Keys are checked before rate limiting, so that authenticated clients are limited by their key.
+		// Rejected requests are rate limited by IP address, so that attempts to guess keys are throttled.
+		routes = h.withAuthentication(routes)
+	}
+	if h.metrics != nil {
+		// Requests are labelled with the route pattern rather than the path, to bound the number of series.
+		routes = h.metrics.Instrument(routes, route)
+	}
+	return withRequestLogging(routes, route)
 }
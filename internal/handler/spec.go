@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/light-bringer/rates-exchanger-service/api"
+	"github.com/light-bringer/rates-exchanger-service/models"
+)
+
+// swaggerUIPage renders the specification served at /openapi.json with Swagger UI loaded from a CDN.
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Rates API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = () => {
+      window.ui = SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui" });
+    };
+  </script>
+</body>
+</html>
+`
+
+// GetSpecYAML handles requests for the OpenAPI specification as YAML.
+func (h *Handler) GetSpecYAML(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set(contentTypeHeader, yamlContentType)
+	w.Write(api.SpecYAML())
+}
+
+// GetSpecJSON handles requests for the OpenAPI specification as JSON.
+func (h *Handler) GetSpecJSON(w http.ResponseWriter, r *http.Request) {
+	spec, err := api.SpecJSON()
+	if err != nil {
+		requestLogger(r).Error("Failed to convert OpenAPI specification", "error", err)
+		writeProblem(w, r, http.StatusInternalServerError, models.ErrorCodeInternal, "",
+			"The specification is not available.")
+		return
+	}
+
+	w.Header().Set(contentTypeHeader, contentType)
+	w.Write(spec)
+}
+
+// GetDocs handles requests for the Swagger UI page.
+func (h *Handler) GetDocs(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set(contentTypeHeader, htmlContentType)
+	w.Write([]byte(swaggerUIPage))
+}
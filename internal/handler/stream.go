@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/light-bringer/rates-exchanger-service/internal/logging"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+)
+
+const (
+	eventStreamContentType = "text/event-stream"
+	lastEventIDHeader      = "Last-Event-ID"
+	// rateEventName is the SSE event type of rate updates.
+	rateEventName = "rates"
+	// eventBatchSize bounds the number of sync events read per query when a stream catches up.
+	eventBatchSize = 100
+	// streamRetry is the reconnection delay suggested to clients.
+	streamRetry = 5 * time.Second
+)
+
+// StreamRates handles Server-Sent Events streams of rate updates.
+// An event is sent for every sync that inserted or changed rates, carrying its sync sequence as event ID.
+// A client reconnecting with Last-Event-ID first receives the events it missed.
+// Heartbeat comments keep idle connections open through proxies.
+func (h *Handler) StreamRates(w http.ResponseWriter, r *http.Request) {
+	lastID, resume, err := parseLastEventID(r)
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	notify, unsubscribe := h.hub.Subscribe()
+	defer unsubscribe()
+
+	if !resume {
+		version, versionErr := h.service.FetchDataVersion(r.Context())
+		if versionErr != nil {
+			serviceError(w, r, versionErr, "")
+			return
+		}
+		lastID = version.Sequence
+	}
+
+	// Streams outlive the server's write timeout.
+	controller := http.NewResponseController(w)
+	if deadlineErr := controller.SetWriteDeadline(time.Time{}); deadlineErr != nil {
+		requestLogger(r).Debug("Failed to clear write deadline", "error", deadlineErr)
+	}
+
+	w.Header().Set(contentTypeHeader, eventStreamContentType)
+	w.Header().Set(cacheControlHeader, "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetry.Milliseconds())
+	if err = controller.Flush(); err != nil {
+		requestLogger(r).Error("Streaming is not supported", "error", err)
+		return
+	}
+
+	requestLogger(r).Debug("Rate stream opened", "last_event_id", lastID, "resume", resume)
+
+	if resume {
+		if lastID, err = h.sendRateEvents(r.Context(), w, lastID); err != nil {
+			return
+		}
+		controller.Flush()
+	}
+
+	heartbeat := time.NewTicker(h.config.StreamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-h.ctx.Done():
+			return
+		case <-notify:
+			if lastID, err = h.sendRateEvents(r.Context(), w, lastID); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err = io.WriteString(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+
+		if err = controller.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// sendRateEvents writes the sync events following the given sequence and returns the last sequence sent.
+// Failures to read the events are logged and retried with the next notification;
+// only failures to write to the client are returned.
+func (h *Handler) sendRateEvents(ctx context.Context, w io.Writer, after int64) (int64, error) {
+	for {
+		events, err := h.service.FetchSyncEvents(ctx, after, eventBatchSize)
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to fetch sync events", "error", err)
+			return after, nil
+		}
+
+		for _, event := range events {
+			if err = writeRateEvent(w, event); err != nil {
+				return after, err
+			}
+			after = event.Sequence
+		}
+
+		if len(events) < eventBatchSize {
+			return after, nil
+		}
+	}
+}
+
+// writeRateEvent writes a sync event as a Server-Sent Event.
+func writeRateEvent(w io.Writer, event models.SyncEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode event")
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.Sequence, rateEventName, data)
+	return errors.Wrap(err, "failed to write event")
+}
+
+// parseLastEventID parses the Last-Event-ID header a reconnecting client sends,
+// falling back to the last_event_id query parameter for clients that cannot set headers.
+// The function reports whether an ID was given.
+func parseLastEventID(r *http.Request) (int64, bool, error) {
+	value := strings.TrimSpace(r.Header.Get(lastEventIDHeader))
+	param := lastEventIDHeader
+	if value == "" {
+		value = r.URL.Query().Get("last_event_id")
+		param = "last_event_id"
+	}
+	if value == "" {
+		return 0, false, nil
+	}
+
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id < 0 {
+		return 0, false, paramErrorf(param, "invalid last event ID %q", value)
+	}
+
+	return id, true, nil
+}
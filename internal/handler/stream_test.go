@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLastEventID(t *testing.T) {
+	testCases := []struct {
+		name           string
+		header         string
+		target         string
+		expectedID     int64
+		expectedResume bool
+		expectedParam  string
+	}{
+		{name: "New stream", target: "/rates/stream"},
+		{name: "Header", header: "42", target: "/rates/stream", expectedID: 42, expectedResume: true},
+		{name: "Query", target: "/rates/stream?last_event_id=7", expectedID: 7, expectedResume: true},
+		{name: "Header takes precedence", header: "42", target: "/rates/stream?last_event_id=7", expectedID: 42, expectedResume: true},
+		{name: "Invalid header", header: "abc", target: "/rates/stream", expectedParam: lastEventIDHeader},
+		{name: "Negative query", target: "/rates/stream?last_event_id=-1", expectedParam: "last_event_id"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			req.Header.Set(lastEventIDHeader, tc.header)
+
+			id, resume, err := parseLastEventID(req)
+			if tc.expectedParam != "" {
+				require.Error(t, err)
+				assert.Equal(t, tc.expectedParam, paramName(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedID, id)
+			assert.Equal(t, tc.expectedResume, resume)
+		})
+	}
+}
+
+func TestWriteRateEvent(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := writeRateEvent(&buf, models.SyncEvent{
+		Sequence:   42,
+		Date:       "2024-03-28",
+		Currencies: []string{"JPY", "USD"},
+		Days:       []string{"2024-03-28"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "id: 42\nevent: rates\n"+
+		`data: {"sequence":42,"date":"2024-03-28","currencies":["JPY","USD"],"days":["2024-03-28"]}`+"\n\n",
+		buf.String())
+}
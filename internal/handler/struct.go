@@ -1,12 +1,112 @@
 package handler
 
-import "github.com/light-bringer/rates-exchanger-service/internal/service"
+import (
+	"context"
+
+	"github.com/light-bringer/rates-exchanger-service/internal/alert"
+	"github.com/light-bringer/rates-exchanger-service/internal/apikey"
+	"github.com/light-bringer/rates-exchanger-service/internal/currency"
+	"github.com/light-bringer/rates-exchanger-service/internal/health"
+	"github.com/light-bringer/rates-exchanger-service/internal/metrics"
+	"github.com/light-bringer/rates-exchanger-service/internal/ratelimit"
+	"github.com/light-bringer/rates-exchanger-service/internal/service"
+	"github.com/light-bringer/rates-exchanger-service/internal/webhook"
+	"github.com/light-bringer/rates-exchanger-service/models"
+)
 
 type Handler struct {
 	service *service.RatesService
+	config  models.APIConfig
+	// webhooks serves the webhook routes; they are not registered if it is nil.
+	webhooks *webhook.Service
+	// alerts serves the alert rule routes; they are not registered if it is nil.
+	alerts *alert.Service
+	// currencies serves the currency routes and rejects unknown symbols; neither is done if it is nil.
+	currencies *currency.Service
+	// health runs the readiness checks; the readiness route is not registered if it is nil.
+	health *health.Checker
+	// metrics instruments the routes and serves /metrics; neither is done if it is nil.
+	metrics *metrics.Metrics
+	// limiter limits the requests of each client; they are not limited if it is nil.
+	limiter *ratelimit.Limiter
+	// keys authenticates the API keys of the requests; no key is required if it is nil.
+	keys *apikey.Service
+	// anonymous are the path prefixes of the routes served without an API key.
+	anonymous []string
+
+	// ctx is cancelled by Close to end the open streams.
+	ctx    context.Context
+	cancel context.CancelFunc
+	// hub notifies the open streams of new rates.
+	hub *service.VersionHub
+}
+
+// NewHandler returns a new Handler with the given RatesService and API configuration.
+// The streams are notified through a VersionHub of their own unless one is shared with WithVersionHub.
+func NewHandler(ratesService *service.RatesService, config models.APIConfig) *Handler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Handler{
+		service: ratesService,
+		config:  config,
+		ctx:     ctx,
+		cancel:  cancel,
+		hub:     service.NewVersionHub(ctx, ratesService, config.StreamPollInterval),
+	}
+}
+
+// WithVersionHub notifies the rate streams through the given VersionHub, shared with the gRPC streams,
+// instead of a watch of their own.
+func (h *Handler) WithVersionHub(hub *service.VersionHub) *Handler {
+	h.hub = hub
+	return h
+}
+
+// WithWebhooks enables the webhook routes, served by the given webhook Service.
+func (h *Handler) WithWebhooks(webhooks *webhook.Service) *Handler {
+	h.webhooks = webhooks
+	return h
+}
+
+// WithAlerts enables the alert rule routes, served by the given alert Service.
+func (h *Handler) WithAlerts(alerts *alert.Service) *Handler {
+	h.alerts = alerts
+	return h
+}
+
+// WithCurrencies enables the currency routes and the rejection of unknown symbols,
+// served by the given currency Service.
+func (h *Handler) WithCurrencies(currencies *currency.Service) *Handler {
+	h.currencies = currencies
+	return h
+}
+
+// WithHealth enables the readiness route, answered with the checks of the given Checker.
+func (h *Handler) WithHealth(checker *health.Checker) *Handler {
+	h.health = checker
+	return h
+}
+
+// WithMetrics enables the instrumentation of the routes and the metrics route, served by the given Metrics.
+func (h *Handler) WithMetrics(m *metrics.Metrics) *Handler {
+	h.metrics = m
+	return h
+}
+
+// WithRateLimit enables the rate limiting of the requests of each client by the given Limiter.
+func (h *Handler) WithRateLimit(limiter *ratelimit.Limiter) *Handler {
+	h.limiter = limiter
+	return h
+}
+
+// WithAuthentication requires an API key, authenticated by the given apikey Service, on all routes
+// but those below the anonymous path prefixes.
+func (h *Handler) WithAuthentication(keys *apikey.Service, anonymous []string) *Handler {
+	h.keys = keys
+	h.anonymous = anonymous
+	return h
 }
 
-// NewHandler returns a new Handler with the given RatesService.
-func NewHandler(service *service.RatesService) *Handler {
-	return &Handler{service: service}
+// Close ends the open rate streams, which would otherwise keep the server from shutting down.
+func (h *Handler) Close() {
+	h.cancel()
 }
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"bytes"
+	"log/slog"
+	"math/rand"
+	"net/http"
+
+	"github.com/light-bringer/rates-exchanger-service/api"
+	"github.com/light-bringer/rates-exchanger-service/internal/openapi"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+)
+
+// withValidation validates requests and responses against the OpenAPI specification.
+// In log mode, mismatches are logged and the exchange is served unchanged. Validating a JSON body means
+// recording it, so only a sample of the bodies, of at most SpecValidationMaxBody bytes, is validated;
+// the status of every response is. In strict mode, invalid requests are rejected with a 400 problem and
+// invalid JSON responses are replaced by a 500 problem; strict mode buffers all JSON responses for this,
+// so it is meant for tests.
+func (h *Handler) withValidation(next http.Handler) http.Handler {
+	mode := h.config.SpecValidation
+	if mode != models.ValidationLog && mode != models.ValidationStrict {
+		return next
+	}
+
+	doc, err := openapi.Load(api.SpecYAML())
+	if err != nil {
+		slog.Error("Failed to load OpenAPI specification, validation disabled", "error", err)
+		return next
+	}
+
+	strict := mode == models.ValidationStrict
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := doc.ValidateRequest(r); err != nil {
+			requestLogger(r).Warn("Request does not match the API specification", "method", r.Method,
+				"path", r.URL.Path, "error", err)
+			if strict {
+				var validationErr *openapi.ValidationError
+				param := ""
+				if errors.As(err, &validationErr) {
+					param = validationErr.Param
+				}
+				writeProblem(w, r, http.StatusBadRequest, models.ErrorCodeInvalidParameter, param, err.Error())
+				return
+			}
+		}
+
+		recorder := &validationRecorder{ResponseWriter: w, strict: strict}
+		if !strict {
+			recorder.sampled = rand.Float64() < h.config.SpecValidationSampleRate //nolint:gosec // sampling only
+			recorder.maxBody = h.config.SpecValidationMaxBody
+		}
+		next.ServeHTTP(recorder, r)
+
+		if !recorder.wroteHeader {
+			recorder.WriteHeader(http.StatusOK)
+		}
+
+		if err := doc.ValidateResponse(r, recorder.status, w.Header(), recorder.body.Bytes()); err != nil {
+			requestLogger(r).Error("Response does not match the API specification", "method", r.Method,
+				"path", r.URL.Path, "status", recorder.status, "error", err)
+			if recorder.buffered {
+				writeProblem(w, r, http.StatusInternalServerError, models.ErrorCodeInternal, "",
+					"The response does not match the API specification.")
+				return
+			}
+		}
+
+		recorder.flushBuffer()
+	})
+}
+
+// validationRecorder records the status and the JSON body of a response for validation.
+// In strict mode, JSON responses are buffered until they are validated; other responses,
+// such as CSV or event streams, are always written through. In log mode, the JSON bodies of
+// sampled responses are recorded up to maxBody bytes; larger bodies are dropped.
+type validationRecorder struct {
+	http.ResponseWriter
+	strict      bool
+	sampled     bool
+	maxBody     int
+	wroteHeader bool
+	buffered    bool
+	record      bool
+	status      int
+	body        bytes.Buffer
+}
+
+func (v *validationRecorder) WriteHeader(status int) {
+	if v.wroteHeader {
+		return
+	}
+	v.wroteHeader = true
+	v.status = status
+	v.record = (v.strict || v.sampled) && openapi.IsJSON(v.Header().Get(contentTypeHeader))
+	v.buffered = v.strict && v.record
+
+	if !v.buffered {
+		v.ResponseWriter.WriteHeader(status)
+	}
+}
+
+func (v *validationRecorder) Write(b []byte) (int, error) {
+	if !v.wroteHeader {
+		v.WriteHeader(http.StatusOK)
+	}
+	if v.record {
+		if !v.buffered && v.body.Len()+len(b) > v.maxBody {
+			// The body is too large to be validated; release what was recorded.
+			v.record = false
+			v.body = bytes.Buffer{}
+		} else {
+			v.body.Write(b)
+		}
+	}
+	if v.buffered {
+		return len(b), nil
+	}
+	return v.ResponseWriter.Write(b) //nolint:wrapcheck // transparent writer
+}
+
+// Flush sends buffered data to the client, unless the response is held back for validation.
+func (v *validationRecorder) Flush() {
+	if v.buffered {
+		return
+	}
+	if flusher, ok := v.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap returns the underlying writer, so that http.ResponseController can reach it.
+func (v *validationRecorder) Unwrap() http.ResponseWriter {
+	return v.ResponseWriter
+}
+
+// flushBuffer writes a response that was held back for validation.
+func (v *validationRecorder) flushBuffer() {
+	if !v.buffered {
+		return
+	}
+	v.ResponseWriter.WriteHeader(v.status)
+	v.ResponseWriter.Write(v.body.Bytes())
+}
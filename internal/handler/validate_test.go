@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/light-bringer/rates-exchanger-service/api"
+	"github.com/light-bringer/rates-exchanger-service/internal/openapi"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpecification(t *testing.T) {
+	_, err := openapi.Load(api.SpecYAML())
+	require.NoError(t, err)
+}
+
+func TestSpecRoutes(t *testing.T) {
+	h := NewHandler(nil, models.APIConfig{SpecValidation: models.ValidationStrict})
+	routes := h.Routes()
+
+	rec := httptest.NewRecorder()
+	routes.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var spec map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &spec))
+	assert.Equal(t, "3.0.3", spec["openapi"])
+
+	rec = httptest.NewRecorder()
+	routes.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.yaml", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, api.SpecYAML(), rec.Body.Bytes())
+
+	rec = httptest.NewRecorder()
+	routes.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	h = NewHandler(nil, models.APIConfig{SpecValidation: models.ValidationStrict, SwaggerUI: true})
+	rec = httptest.NewRecorder()
+	h.Routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "/openapi.json")
+}
+
+func TestWithValidation(t *testing.T) {
+	testCases := []struct {
+		name           string
+		mode           models.ValidationMode
+		target         string
+		body           string
+		expectedStatus int
+		expectedParam  string
+	}{
+		{
+			name:           "Strict rejects invalid request",
+			mode:           models.ValidationStrict,
+			target:         "/rates/latest?limit=ten",
+			expectedStatus: http.StatusBadRequest,
+			expectedParam:  "limit",
+		},
+		{
+			name:           "Strict passes valid response",
+			mode:           models.ValidationStrict,
+			target:         "/rates/latest",
+			body:           `{"base":"EUR","date":"2024-03-28","rates":{"USD":1.0811}}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Strict replaces invalid response",
+			mode:           models.ValidationStrict,
+			target:         "/rates/latest",
+			body:           `{"base":"EUR","rates":[{"currency":"USD","rate":1.0811}]}`,
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "Log serves invalid response",
+			mode:           models.ValidationLog,
+			target:         "/rates/latest",
+			body:           `{"base":"EUR","rates":[]}`,
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewHandler(nil, models.APIConfig{SpecValidation: tc.mode})
+			next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set(contentTypeHeader, contentType)
+				w.Write([]byte(tc.body))
+			})
+
+			rec := httptest.NewRecorder()
+			h.withValidation(next).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.target, nil))
+
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			if tc.expectedStatus == http.StatusOK {
+				assert.Equal(t, tc.body, rec.Body.String())
+				return
+			}
+
+			var problem models.Problem
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+			assert.Equal(t, tc.expectedParam, problem.Param)
+		})
+	}
+}
+
+func TestValidationRecorder(t *testing.T) {
+	testCases := []struct {
+		name           string
+		sampled        bool
+		maxBody        int
+		expectedRecord string
+	}{
+		{name: "Sampled", sampled: true, maxBody: 64, expectedRecord: `{"base":"EUR","rates":{}}`},
+		{name: "Not sampled", maxBody: 64},
+		{name: "Too large", sampled: true, maxBody: 16},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			recorder := &validationRecorder{ResponseWriter: rec, sampled: tc.sampled, maxBody: tc.maxBody}
+			recorder.Header().Set(contentTypeHeader, contentType)
+			recorder.Write([]byte(`{"base":"EUR",`))
+			recorder.Write([]byte(`"rates":{}}`))
+
+			assert.Equal(t, tc.expectedRecord, recorder.body.String())
+			assert.Equal(t, `{"base":"EUR","rates":{}}`, rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/light-bringer/rates-exchanger-service/models"
+)
+
+// GetVolatility handles requests for the historical volatility of the daily log returns of some currencies.
+// Without a start and end date, the volatility covers the last defaultVolatilityDays days,
+// or the maximum range if that is shorter.
+func (h *Handler) GetVolatility(w http.ResponseWriter, r *http.Request) {
+	symbols, err := h.parseKnownSymbols(r)
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	window, err := parseWindow(r, defaultVolatilityWindow)
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	end := time.Now().UTC().Truncate(hoursPerDay * time.Hour)
+	start := end.AddDate(0, 0, 1-min(defaultVolatilityDays, h.config.MaxTimeSeriesDays))
+	if r.URL.Query().Has("start") || r.URL.Query().Has("end") {
+		if start, end, err = parseDateRange(r); err != nil {
+			badRequest(w, r, err)
+			return
+		}
+	}
+
+	if spanDays(start, end) > h.config.MaxTimeSeriesDays {
+		writeProblem(w, r, http.StatusBadRequest, models.ErrorCodeRangeTooLarge, "end",
+			fmt.Sprintf("The date range exceeds the maximum of %d days.", h.config.MaxTimeSeriesDays))
+		return
+	}
+
+	base, err := parseBase(r)
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+	if slices.Contains(symbols, base) {
+		badRequest(w, r, paramErrorf("symbols", "symbols must not include the base currency %s", base))
+		return
+	}
+
+	volatility, err := h.service.FetchVolatility(r.Context(), base, symbols, start.Format(dateLayout), end.Format(dateLayout), window)
+	if err != nil {
+		serviceError(w, r, err, "base")
+		return
+	}
+
+	writeJSON(w, volatility)
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetVolatilityProblems(t *testing.T) {
+	routes := NewHandler(nil, models.APIConfig{MaxTimeSeriesDays: 366}).Routes()
+
+	testCases := []struct {
+		name          string
+		target        string
+		expectedCode  models.ErrorCode
+		expectedParam string
+	}{
+		{
+			name:          "Invalid symbol",
+			target:        "/rates/volatility?symbols=US",
+			expectedCode:  models.ErrorCodeInvalidParameter,
+			expectedParam: "symbols",
+		},
+		{
+			name:          "Window too small",
+			target:        "/rates/volatility?symbols=USD&window=1",
+			expectedCode:  models.ErrorCodeInvalidParameter,
+			expectedParam: "window",
+		},
+		{
+			name:          "Invalid window",
+			target:        "/rates/volatility?symbols=USD&window=month",
+			expectedCode:  models.ErrorCodeInvalidParameter,
+			expectedParam: "window",
+		},
+		{
+			name:          "Start without end",
+			target:        "/rates/volatility?symbols=USD&start=2024-01-01",
+			expectedCode:  models.ErrorCodeInvalidParameter,
+			expectedParam: "end",
+		},
+		{
+			name:          "Range too large",
+			target:        "/rates/volatility?symbols=USD&start=2020-01-01&end=2024-03-31",
+			expectedCode:  models.ErrorCodeRangeTooLarge,
+			expectedParam: "end",
+		},
+		{
+			name:          "Base among the symbols",
+			target:        "/rates/volatility?symbols=USD,JPY&base=JPY",
+			expectedCode:  models.ErrorCodeInvalidParameter,
+			expectedParam: "symbols",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := serveJSON(t, routes, http.MethodGet, tc.target, "")
+
+			require.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+			problem := decodeProblem(t, rec)
+			assert.Equal(t, tc.expectedCode, problem.Code)
+			assert.Equal(t, tc.expectedParam, problem.Param)
+		})
+	}
+}
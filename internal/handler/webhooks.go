@@ -0,0 +1,251 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/light-bringer/rates-exchanger-service/internal/webhook"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+)
+
+const (
+	// maxWebhookBody bounds the size of webhook request bodies.
+	maxWebhookBody = 64 << 10
+	// minSecretLength is the minimum length of a client supplied webhook secret.
+	minSecretLength = 16
+	// defaultDeliveryLimit is the number of deliveries listed if no limit is requested.
+	defaultDeliveryLimit = 100
+)
+
+// CreateWebhook handles requests to create a webhook.
+// The response is the only one that includes the signing secret.
+func (h *Handler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var input models.WebhookInput
+	if err := decodeBody(w, r, &input); err != nil {
+		invalidBody(w, r, err)
+		return
+	}
+
+	if input.URL == nil {
+		badRequest(w, r, paramErrorf("url", "url is required"))
+		return
+	}
+	if err := h.validateWebhookInput(input); err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	hook := models.Webhook{URL: *input.URL, Active: true}
+	if input.Description != nil {
+		hook.Description = *input.Description
+	}
+	if input.Secret != nil {
+		hook.Secret = *input.Secret
+	}
+	if input.Active != nil {
+		hook.Active = *input.Active
+	}
+
+	created, err := h.webhooks.CreateWebhook(r.Context(), hook)
+	if err != nil {
+		webhookError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", "/webhooks/"+strconv.FormatInt(created.ID, 10))
+	writeJSONStatus(w, http.StatusCreated, created)
+}
+
+// ListWebhooks handles requests for all webhooks.
+func (h *Handler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	webhooks, err := h.webhooks.ListWebhooks(r.Context())
+	if err != nil {
+		webhookError(w, r, err)
+		return
+	}
+
+	writeJSON(w, map[string]interface{}{"webhooks": webhooks})
+}
+
+// GetWebhook handles requests for a webhook.
+func (h *Handler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDPath(r, "id")
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	hook, err := h.webhooks.GetWebhook(r.Context(), id)
+	if err != nil {
+		webhookError(w, r, err)
+		return
+	}
+
+	writeJSON(w, hook)
+}
+
+// UpdateWebhook handles requests to change some fields of a webhook.
+func (h *Handler) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDPath(r, "id")
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	var input models.WebhookInput
+	if err = decodeBody(w, r, &input); err != nil {
+		invalidBody(w, r, err)
+		return
+	}
+	if err = h.validateWebhookInput(input); err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	hook, err := h.webhooks.UpdateWebhook(r.Context(), id, input)
+	if err != nil {
+		webhookError(w, r, err)
+		return
+	}
+
+	writeJSON(w, hook)
+}
+
+// DeleteWebhook handles requests to delete a webhook together with its deliveries.
+func (h *Handler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDPath(r, "id")
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	if err = h.webhooks.DeleteWebhook(r.Context(), id); err != nil {
+		webhookError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListWebhookDeliveries handles requests for the delivery log of a webhook, newest first.
+func (h *Handler) ListWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDPath(r, "id")
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	status, err := parseDeliveryStatus(r)
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	h.listDeliveries(w, r, webhook.DeliveryFilter{WebhookID: id, Status: status})
+}
+
+// ListDeadLetters handles requests for the deliveries of all webhooks that exhausted their attempts.
+func (h *Handler) ListDeadLetters(w http.ResponseWriter, r *http.Request) {
+	h.listDeliveries(w, r, webhook.DeliveryFilter{Status: models.DeliveryDead})
+}
+
+// listDeliveries writes the deliveries matching the filter, limited by the limit query parameter.
+func (h *Handler) listDeliveries(w http.ResponseWriter, r *http.Request, filter webhook.DeliveryFilter) {
+	limit, err := parseUintParam(r, "limit", defaultDeliveryLimit)
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+	filter.Limit = limit
+
+	deliveries, err := h.webhooks.ListDeliveries(r.Context(), filter)
+	if err != nil {
+		webhookError(w, r, err)
+		return
+	}
+
+	writeJSON(w, map[string]interface{}{"deliveries": deliveries})
+}
+
+// RetryWebhookDelivery handles requests to retry a dead letter with a fresh set of attempts.
+func (h *Handler) RetryWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDPath(r, "id")
+	if err != nil {
+		badRequest(w, r, err)
+		return
+	}
+
+	delivery, err := h.webhooks.RetryDelivery(r.Context(), id)
+	if err != nil {
+		webhookError(w, r, err)
+		return
+	}
+
+	writeJSONStatus(w, http.StatusAccepted, delivery)
+}
+
+// validateWebhookInput validates the fields given to create or update a webhook.
+func (h *Handler) validateWebhookInput(input models.WebhookInput) error {
+	if input.URL != nil {
+		if err := h.webhooks.CheckURL(*input.URL); err != nil {
+			return &paramError{param: "url", err: err}
+		}
+	}
+
+	if input.Secret != nil && len(*input.Secret) < minSecretLength {
+		return paramErrorf("secret", "secret must be at least %d characters long", minSecretLength)
+	}
+
+	return nil
+}
+
+// parseIDPath parses a required positive integer path parameter.
+func parseIDPath(r *http.Request, name string) (int64, error) {
+	value := r.PathValue(name)
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, paramErrorf(name, "invalid %s %q", name, value)
+	}
+	return id, nil
+}
+
+// parseDeliveryStatus parses the optional status query parameter of delivery listings.
+func parseDeliveryStatus(r *http.Request) (models.DeliveryStatus, error) {
+	status := models.DeliveryStatus(strings.ToLower(r.URL.Query().Get("status")))
+	switch status {
+	case "", models.DeliveryPending, models.DeliveryDelivered, models.DeliveryDead:
+		return status, nil
+	default:
+		return "", paramErrorf("status", "invalid status %q, expected pending, delivered or dead", status)
+	}
+}
+
+// decodeBody decodes a JSON request body of bounded size, rejecting unknown fields.
+func decodeBody(w http.ResponseWriter, r *http.Request, dst interface{}) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxWebhookBody))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(dst); err != nil {
+		return errors.Wrap(err, "invalid JSON body")
+	}
+	return nil
+}
+
+// invalidBody writes a 400 problem for a request body that cannot be decoded.
+func invalidBody(w http.ResponseWriter, r *http.Request, err error) {
+	writeProblem(w, r, http.StatusBadRequest, models.ErrorCodeInvalidBody, "", err.Error())
+}
+
+// webhookError writes the problem matching an error returned by the webhook service.
+func webhookError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, webhook.ErrWebhookNotFound), errors.Is(err, webhook.ErrDeliveryNotFound):
+		writeProblem(w, r, http.StatusNotFound, models.ErrorCodeNotFound, "", err.Error())
+	case errors.Is(err, webhook.ErrDeliveryNotDead):
+		writeProblem(w, r, http.StatusConflict, models.ErrorCodeConflict, "", err.Error())
+	default:
+		serviceError(w, r, err, "")
+	}
+}
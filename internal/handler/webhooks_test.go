@@ -0,0 +1,263 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/light-bringer/rates-exchanger-service/internal/webhook"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveJSON(t *testing.T, routes http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, target, reader)
+	if body != "" {
+		req.Header.Set(contentTypeHeader, contentType)
+	}
+
+	rec := httptest.NewRecorder()
+	routes.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestWebhookRoutes(t *testing.T) {
+	var failing atomic.Bool
+	var received atomic.Int32
+	const secret = "0123456789abcdef"
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.True(t, webhook.Verify(secret, r.Header.Get(webhook.TimestampHeader),
+			r.Header.Get(webhook.SignatureHeader), body))
+		received.Add(1)
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	webhooks := webhook.NewService(webhook.NewMemoryStore(), models.WebhookConfig{
+		Timeout:        time.Second,
+		MaxAttempts:    1,
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Second,
+		// The receiver listens on the loopback interface.
+		AllowPrivateNetworks: true,
+	})
+	routes := NewHandler(nil, models.APIConfig{SpecValidation: models.ValidationStrict}).
+		WithWebhooks(webhooks).
+		Routes()
+
+	// Create
+	rec := serveJSON(t, routes, http.MethodPost, "/webhooks",
+		`{"url":"`+receiver.URL+`","description":"ledger","secret":"`+secret+`"}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var created models.Webhook
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, secret, created.Secret)
+	assert.True(t, created.Active)
+	id := strconv.FormatInt(created.ID, 10)
+	assert.Equal(t, "/webhooks/"+id, rec.Header().Get("Location"))
+
+	// Read
+	rec = serveJSON(t, routes, http.MethodGet, "/webhooks/"+id, "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.NotContains(t, rec.Body.String(), secret)
+
+	rec = serveJSON(t, routes, http.MethodGet, "/webhooks", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), `"description":"ledger"`)
+
+	// Deliver
+	event := models.SyncEvent{Sequence: 7, Date: "2024-06-03", Currencies: []string{"USD"}, Days: []string{"2024-06-03"}}
+	_, err := webhooks.Enqueue(context.Background(), event)
+	require.NoError(t, err)
+	_, err = webhooks.ProcessDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), received.Load())
+
+	rec = serveJSON(t, routes, http.MethodGet, "/webhooks/"+id+"/deliveries?status=delivered", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var log struct {
+		Deliveries []models.WebhookDelivery `json:"deliveries"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &log))
+	require.Len(t, log.Deliveries, 1)
+	assert.Equal(t, int64(7), log.Deliveries[0].Sequence)
+
+	// Dead letter and retry
+	failing.Store(true)
+	event.Sequence = 8
+	_, err = webhooks.Enqueue(context.Background(), event)
+	require.NoError(t, err)
+	_, err = webhooks.ProcessDue(context.Background())
+	require.NoError(t, err)
+
+	rec = serveJSON(t, routes, http.MethodGet, "/webhooks/dead-letters", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &log))
+	require.Len(t, log.Deliveries, 1)
+	dead := log.Deliveries[0]
+	assert.Equal(t, models.DeliveryDead, dead.Status)
+	assert.Equal(t, http.StatusInternalServerError, dead.LastStatusCode)
+
+	retryTarget := "/webhooks/deliveries/" + strconv.FormatInt(dead.ID, 10) + "/retry"
+	rec = serveJSON(t, routes, http.MethodPost, retryTarget, "")
+	require.Equal(t, http.StatusAccepted, rec.Code, rec.Body.String())
+	rec = serveJSON(t, routes, http.MethodPost, retryTarget, "")
+	require.Equal(t, http.StatusConflict, rec.Code, rec.Body.String())
+	assert.Equal(t, models.ErrorCodeConflict, decodeProblem(t, rec).Code)
+
+	// Update
+	rec = serveJSON(t, routes, http.MethodPatch, "/webhooks/"+id, `{"active":false}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), `"active":false`)
+
+	// Delete
+	rec = serveJSON(t, routes, http.MethodDelete, "/webhooks/"+id, "")
+	require.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+	rec = serveJSON(t, routes, http.MethodGet, "/webhooks/"+id, "")
+	require.Equal(t, http.StatusNotFound, rec.Code, rec.Body.String())
+	assert.Equal(t, models.ErrorCodeNotFound, decodeProblem(t, rec).Code)
+}
+
+func TestWebhookRoutesProblems(t *testing.T) {
+	webhooks := webhook.NewService(webhook.NewMemoryStore(), models.WebhookConfig{})
+	routes := NewHandler(nil, models.APIConfig{}).WithWebhooks(webhooks).Routes()
+
+	testCases := []struct {
+		name           string
+		method         string
+		target         string
+		body           string
+		expectedStatus int
+		expectedCode   models.ErrorCode
+		expectedParam  string
+	}{
+		{
+			name:           "Missing url",
+			method:         http.MethodPost,
+			target:         "/webhooks",
+			body:           `{"description":"ledger"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   models.ErrorCodeInvalidParameter,
+			expectedParam:  "url",
+		},
+		{
+			name:           "Relative url",
+			method:         http.MethodPost,
+			target:         "/webhooks",
+			body:           `{"url":"/hooks"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   models.ErrorCodeInvalidParameter,
+			expectedParam:  "url",
+		},
+		{
+			name:           "Private url",
+			method:         http.MethodPost,
+			target:         "/webhooks",
+			body:           `{"url":"http://169.254.169.254/latest/meta-data"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   models.ErrorCodeInvalidParameter,
+			expectedParam:  "url",
+		},
+		{
+			name:           "Loopback url update",
+			method:         http.MethodPatch,
+			target:         "/webhooks/1",
+			body:           `{"url":"http://localhost:8080/hooks"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   models.ErrorCodeInvalidParameter,
+			expectedParam:  "url",
+		},
+		{
+			name:           "Short secret",
+			method:         http.MethodPost,
+			target:         "/webhooks",
+			body:           `{"url":"https://example.com/hooks","secret":"short"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   models.ErrorCodeInvalidParameter,
+			expectedParam:  "secret",
+		},
+		{
+			name:           "Unknown field",
+			method:         http.MethodPost,
+			target:         "/webhooks",
+			body:           `{"url":"https://example.com/hooks","events":["all"]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   models.ErrorCodeInvalidBody,
+		},
+		{
+			name:           "Malformed body",
+			method:         http.MethodPatch,
+			target:         "/webhooks/1",
+			body:           `{"active":`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   models.ErrorCodeInvalidBody,
+		},
+		{
+			name:           "Invalid id",
+			method:         http.MethodGet,
+			target:         "/webhooks/abc",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   models.ErrorCodeInvalidParameter,
+			expectedParam:  "id",
+		},
+		{
+			name:           "Unknown webhook",
+			method:         http.MethodGet,
+			target:         "/webhooks/42/deliveries",
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   models.ErrorCodeNotFound,
+		},
+		{
+			name:           "Invalid status",
+			method:         http.MethodGet,
+			target:         "/webhooks/42/deliveries?status=failed",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   models.ErrorCodeInvalidParameter,
+			expectedParam:  "status",
+		},
+		{
+			name:           "Unknown delivery",
+			method:         http.MethodPost,
+			target:         "/webhooks/deliveries/42/retry",
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   models.ErrorCodeNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := serveJSON(t, routes, tc.method, tc.target, tc.body)
+
+			require.Equal(t, tc.expectedStatus, rec.Code, rec.Body.String())
+			problem := decodeProblem(t, rec)
+			assert.Equal(t, tc.expectedCode, problem.Code)
+			assert.Equal(t, tc.expectedParam, problem.Param)
+		})
+	}
+}
+
+func TestWebhookRoutesDisabled(t *testing.T) {
+	routes := NewHandler(nil, models.APIConfig{}).Routes()
+
+	rec := serveJSON(t, routes, http.MethodGet, "/webhooks", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
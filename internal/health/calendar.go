@@ -0,0 +1,73 @@
+package health
+
+import "time"
+
+// IsBusinessDay reports whether the ECB publishes reference rates on the given day, i.e. whether it is
+// a TARGET business day: a weekday other than New Year's Day, Good Friday, Easter Monday, Labour Day,
+// Christmas Day and the 26th of December.
+func IsBusinessDay(day time.Time) bool {
+	switch day.Weekday() {
+	case time.Saturday, time.Sunday:
+		return false
+	}
+
+	year, month, dayOfMonth := day.Date()
+	switch {
+	case month == time.January && dayOfMonth == 1,
+		month == time.May && dayOfMonth == 1,
+		month == time.December && (dayOfMonth == 25 || dayOfMonth == 26):
+		return false
+	}
+
+	easter := easterSunday(year)
+	date := time.Date(year, month, dayOfMonth, 0, 0, 0, 0, time.UTC)
+	return !date.Equal(easter.AddDate(0, 0, -2)) && !date.Equal(easter.AddDate(0, 0, 1))
+}
+
+// easterSunday returns the date of Easter Sunday in the Gregorian calendar,
+// computed with the anonymous Gregorian algorithm.
+//
+//nolint:gomnd // the constants are those of the algorithm
+func easterSunday(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+// ExpectedPublicationDay returns the latest business day whose rates should be stored at the given time.
+// The rates of a business day are expected from the cutoff, a time of day in UTC, onwards.
+func ExpectedPublicationDay(now time.Time, cutoff time.Duration) time.Time {
+	now = now.UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if now.Sub(day) < cutoff {
+		day = day.AddDate(0, 0, -1)
+	}
+	for !IsBusinessDay(day) {
+		day = day.AddDate(0, 0, -1)
+	}
+	return day
+}
+
+// businessDaysBetween returns the number of business days after from up to and including to,
+// or zero if to is not after from.
+func businessDaysBetween(from, to time.Time) int {
+	days := 0
+	for day := from.AddDate(0, 0, 1); !day.After(to); day = day.AddDate(0, 0, 1) {
+		if IsBusinessDay(day) {
+			days++
+		}
+	}
+	return days
+}
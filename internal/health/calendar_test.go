@@ -0,0 +1,87 @@
+package health
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func day(t *testing.T, date string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse(dateLayout, date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+func TestIsBusinessDay(t *testing.T) {
+	testCases := []struct {
+		date     string
+		business bool
+	}{
+		{date: "2024-03-27", business: true},  // Wednesday
+		{date: "2024-03-23", business: false}, // Saturday
+		{date: "2024-03-24", business: false}, // Sunday
+		{date: "2024-01-01", business: false}, // New Year's Day
+		{date: "2024-03-29", business: false}, // Good Friday
+		{date: "2024-04-01", business: false}, // Easter Monday
+		{date: "2024-05-01", business: false}, // Labour Day
+		{date: "2024-12-25", business: false}, // Christmas Day
+		{date: "2024-12-26", business: false}, // 26 December
+		{date: "2024-12-24", business: true},  // Christmas Eve
+		{date: "2025-04-18", business: false}, // Good Friday
+		{date: "2025-04-21", business: false}, // Easter Monday
+		{date: "2025-04-22", business: true},
+		{date: "2038-04-23", business: false}, // Good Friday of a late Easter
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.date, func(t *testing.T) {
+			assert.Equal(t, tc.business, IsBusinessDay(day(t, tc.date)))
+		})
+	}
+}
+
+func TestExpectedPublicationDay(t *testing.T) {
+	cutoff := 16 * time.Hour
+
+	testCases := []struct {
+		name     string
+		now      time.Time
+		expected string
+	}{
+		{
+			name:     "Business day after the cutoff",
+			now:      time.Date(2024, time.March, 27, 16, 30, 0, 0, time.UTC),
+			expected: "2024-03-27",
+		},
+		{
+			name:     "Business day before the cutoff",
+			now:      time.Date(2024, time.March, 27, 9, 0, 0, 0, time.UTC),
+			expected: "2024-03-26",
+		},
+		{
+			name:     "Monday morning",
+			now:      time.Date(2024, time.March, 25, 9, 0, 0, 0, time.UTC),
+			expected: "2024-03-22",
+		},
+		{
+			name:     "Easter weekend",
+			now:      time.Date(2024, time.April, 1, 18, 0, 0, 0, time.UTC),
+			expected: "2024-03-28",
+		},
+		{
+			name:     "Cutoff in another time zone",
+			now:      time.Date(2024, time.March, 27, 17, 30, 0, 0, time.FixedZone("CET", 3600)),
+			expected: "2024-03-27",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ExpectedPublicationDay(tc.now, cutoff).Format(dateLayout))
+		})
+	}
+}
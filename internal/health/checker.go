@@ -0,0 +1,135 @@
+// Package health checks whether the service is ready to serve rates: whether the database is reachable,
+// the startup sync finished and the stored rates are up to date with the TARGET calendar.
+package health
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/light-bringer/rates-exchanger-service/models"
+)
+
+const (
+	// CheckDatabase pings the database.
+	CheckDatabase = "database"
+	// CheckStartupSync confirms the first sync finished.
+	CheckStartupSync = "startup_sync"
+	// CheckDataFreshness compares the latest stored day with the expected publication day.
+	CheckDataFreshness = "data_freshness"
+
+	dateLayout = "2006-01-02"
+)
+
+// Pinger checks the connection to the database. It is implemented by pgxpool.Pool.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// VersionSource provides the latest stored day. It is implemented by service.RatesService.
+type VersionSource interface {
+	FetchDataVersion(ctx context.Context) (models.DataVersion, error)
+}
+
+// SyncStatusSource provides the outcome of the syncs. It is implemented by sync.ExchangeRateSync.
+type SyncStatusSource interface {
+	Status() models.SyncStatus
+}
+
+// Checker runs the readiness checks.
+type Checker struct {
+	db       Pinger
+	versions VersionSource
+	syncs    SyncStatusSource
+	config   models.HealthConfig
+	now      func() time.Time
+}
+
+// NewChecker returns a new Checker with the given database, version source, sync status source and settings.
+func NewChecker(db Pinger, versions VersionSource, syncs SyncStatusSource, config models.HealthConfig) *Checker {
+	return &Checker{db: db, versions: versions, syncs: syncs, config: config, now: time.Now}
+}
+
+// Check runs the readiness checks. The service is degraded if any check fails.
+// The database checks are bounded by the configured timeout.
+func (c *Checker) Check(ctx context.Context) models.Health {
+	if c.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.config.Timeout)
+		defer cancel()
+	}
+
+	health := models.Health{
+		Status: models.HealthOK,
+		Checks: map[string]models.HealthCheck{
+			CheckDatabase:      c.checkDatabase(ctx),
+			CheckStartupSync:   c.checkStartupSync(),
+			CheckDataFreshness: c.checkDataFreshness(ctx),
+		},
+	}
+
+	for _, check := range health.Checks {
+		if check.Status != models.HealthOK {
+			health.Status = models.HealthDegraded
+		}
+	}
+
+	return health
+}
+
+// checkDatabase pings the database.
+func (c *Checker) checkDatabase(ctx context.Context) models.HealthCheck {
+	if err := c.db.Ping(ctx); err != nil {
+		return failed("database unreachable: %v", err)
+	}
+	return models.HealthCheck{Status: models.HealthOK}
+}
+
+// checkStartupSync confirms the first sync finished. A failed sync counts as finished, since the
+// freshness check reports whether the stored rates are still usable.
+func (c *Checker) checkStartupSync() models.HealthCheck {
+	status := c.syncs.Status()
+	if status.LastRun.IsZero() {
+		return failed("the startup sync has not finished")
+	}
+
+	check := models.HealthCheck{Status: models.HealthOK}
+	if status.LastError != "" {
+		check.Detail = "last sync failed: " + status.LastError
+	}
+	return check
+}
+
+// checkDataFreshness compares the latest stored day with the expected publication day.
+// The check fails if the latest day is more than the configured number of business days behind.
+func (c *Checker) checkDataFreshness(ctx context.Context) models.HealthCheck {
+	version, err := c.versions.FetchDataVersion(ctx)
+	if err != nil {
+		return failed("latest day unavailable: %v", err)
+	}
+	if version.LatestDay == "" {
+		return failed("no rates stored")
+	}
+
+	latest, err := time.Parse(dateLayout, version.LatestDay)
+	if err != nil {
+		return failed("invalid latest day %q", version.LatestDay)
+	}
+
+	expected := ExpectedPublicationDay(c.now(), c.config.PublicationCutoff)
+	lag := businessDaysBetween(latest, expected)
+	detail := fmt.Sprintf("latest day %s, expected %s", version.LatestDay, expected.Format(dateLayout))
+	if lag > c.config.MaxLagDays {
+		return models.HealthCheck{
+			Status: models.HealthFail,
+			Detail: fmt.Sprintf("%s: %d business days behind", detail, lag),
+		}
+	}
+
+	return models.HealthCheck{Status: models.HealthOK, Detail: detail}
+}
+
+// failed returns a failing check with a formatted detail.
+func failed(format string, args ...interface{}) models.HealthCheck {
+	return models.HealthCheck{Status: models.HealthFail, Detail: fmt.Sprintf(format, args...)}
+}
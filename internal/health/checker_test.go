@@ -0,0 +1,124 @@
+package health
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakePinger fails with err if it is set.
+type fakePinger struct {
+	err error
+}
+
+func (f fakePinger) Ping(context.Context) error {
+	return f.err
+}
+
+// fakeVersions returns a fixed latest day, or err if it is set.
+type fakeVersions struct {
+	latestDay string
+	err       error
+}
+
+func (f fakeVersions) FetchDataVersion(context.Context) (models.DataVersion, error) {
+	return models.DataVersion{LatestDay: f.latestDay}, f.err
+}
+
+// fakeSyncs returns a fixed sync status.
+type fakeSyncs models.SyncStatus
+
+func (f fakeSyncs) Status() models.SyncStatus {
+	return models.SyncStatus(f)
+}
+
+func TestChecker_Check(t *testing.T) {
+	// Thursday after the cutoff; the expected publication day is 2024-03-28.
+	now := time.Date(2024, time.March, 28, 17, 0, 0, 0, time.UTC)
+	synced := fakeSyncs{LastRun: now, LastSuccess: now}
+	config := models.HealthConfig{PublicationCutoff: 16 * time.Hour}
+
+	testCases := []struct {
+		name       string
+		db         Pinger
+		versions   VersionSource
+		syncs      SyncStatusSource
+		maxLagDays int
+		status     models.HealthStatus
+		failed     []string
+	}{
+		{
+			name:     "Ready",
+			db:       fakePinger{},
+			versions: fakeVersions{latestDay: "2024-03-28"},
+			syncs:    synced,
+			status:   models.HealthOK,
+		},
+		{
+			name:     "Database unreachable",
+			db:       fakePinger{err: errors.New("connection refused")},
+			versions: fakeVersions{err: errors.New("connection refused")},
+			syncs:    synced,
+			status:   models.HealthDegraded,
+			failed:   []string{CheckDatabase, CheckDataFreshness},
+		},
+		{
+			name:     "Startup sync running",
+			db:       fakePinger{},
+			versions: fakeVersions{},
+			syncs:    fakeSyncs{},
+			status:   models.HealthDegraded,
+			failed:   []string{CheckStartupSync, CheckDataFreshness},
+		},
+		{
+			name:     "Stale data",
+			db:       fakePinger{},
+			versions: fakeVersions{latestDay: "2024-03-26"},
+			syncs:    fakeSyncs{LastRun: now, LastError: "unexpected status 503"},
+			status:   models.HealthDegraded,
+			failed:   []string{CheckDataFreshness},
+		},
+		{
+			name:       "Lag within the limit",
+			db:         fakePinger{},
+			versions:   fakeVersions{latestDay: "2024-03-27"},
+			syncs:      synced,
+			maxLagDays: 1,
+			status:     models.HealthOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			checkConfig := config
+			checkConfig.MaxLagDays = tc.maxLagDays
+			checker := NewChecker(tc.db, tc.versions, tc.syncs, checkConfig)
+			checker.now = func() time.Time { return now }
+
+			health := checker.Check(context.Background())
+
+			assert.Equal(t, tc.status, health.Status)
+			assert.Len(t, health.Checks, 3)
+			failed := make([]string, 0)
+			for _, name := range []string{CheckDatabase, CheckStartupSync, CheckDataFreshness} {
+				if health.Checks[name].Status == models.HealthFail {
+					failed = append(failed, name)
+				}
+			}
+			assert.ElementsMatch(t, tc.failed, failed)
+		})
+	}
+
+	t.Run("Freshness detail", func(t *testing.T) {
+		checker := NewChecker(fakePinger{}, fakeVersions{latestDay: "2024-03-26"}, synced, config)
+		checker.now = func() time.Time { return now }
+
+		check := checker.Check(context.Background()).Checks[CheckDataFreshness]
+
+		assert.Equal(t, "latest day 2024-03-26, expected 2024-03-28: 2 business days behind", check.Detail)
+	})
+}
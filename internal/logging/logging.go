@@ -0,0 +1,59 @@
+// Package logging carries a request-scoped logger in a context, so that everything logged while
+// serving a request can be correlated, e.g. by its request ID.
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+)
+
+const (
+	// MaxRequestIDLength bounds the length of client supplied request IDs.
+	MaxRequestIDLength = 128
+	// requestIDBytes is the size of generated request IDs.
+	requestIDBytes = 16
+)
+
+// contextKey is the key of the logger in a context.
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the given logger.
+func NewContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger carried by ctx, or the default logger if it carries none.
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// NewRequestID returns a random 128-bit hex encoded request ID.
+func NewRequestID() string {
+	buf := make([]byte, requestIDBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(buf)
+}
+
+// IsValidRequestID reports whether a client supplied request ID can be reused. IDs are limited to
+// letters, digits and a few separators, so that they cannot forge log lines or response headers.
+func IsValidRequestID(id string) bool {
+	if id == "" || len(id) > MaxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,16 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromContext(t *testing.T) {
+	assert.Same(t, slog.Default(), FromContext(context.Background()))
+
+	logger := slog.Default().With("request_id", "abc")
+	assert.Same(t, logger, FromContext(NewContext(context.Background(), logger)))
+}
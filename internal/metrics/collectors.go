@@ -0,0 +1,154 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	dateLayout  = "2006-01-02"
+	hoursPerDay = 24
+)
+
+// PoolStats provides the statistics of a connection pool. It is implemented by pgxpool.Pool.
+type PoolStats interface {
+	Stat() *pgxpool.Stat
+}
+
+// poolMetric is a metric derived from the statistics of a connection pool.
+type poolMetric struct {
+	desc      *prometheus.Desc
+	valueType prometheus.ValueType
+	value     func(stat *pgxpool.Stat) float64
+}
+
+// poolCollector collects the statistics of a connection pool on every scrape.
+type poolCollector struct {
+	pool    PoolStats
+	metrics []poolMetric
+}
+
+// NewPoolCollector returns a collector of the statistics of the given connection pool.
+func NewPoolCollector(pool PoolStats) prometheus.Collector {
+	gauge := func(name, help string, value func(stat *pgxpool.Stat) float64) poolMetric {
+		return poolMetric{
+			desc:      prometheus.NewDesc(prometheus.BuildFQName(namespace, "db_pool", name), help, nil, nil),
+			valueType: prometheus.GaugeValue,
+			value:     value,
+		}
+	}
+	counter := func(name, help string, value func(stat *pgxpool.Stat) float64) poolMetric {
+		metric := gauge(name, help, value)
+		metric.valueType = prometheus.CounterValue
+		return metric
+	}
+
+	return &poolCollector{
+		pool: pool,
+		metrics: []poolMetric{
+			gauge("acquired_connections", "Number of connections currently in use.",
+				func(s *pgxpool.Stat) float64 { return float64(s.AcquiredConns()) }),
+			gauge("idle_connections", "Number of idle connections.",
+				func(s *pgxpool.Stat) float64 { return float64(s.IdleConns()) }),
+			gauge("constructing_connections", "Number of connections being established.",
+				func(s *pgxpool.Stat) float64 { return float64(s.ConstructingConns()) }),
+			gauge("total_connections", "Number of open and constructing connections.",
+				func(s *pgxpool.Stat) float64 { return float64(s.TotalConns()) }),
+			gauge("max_connections", "Maximum number of connections.",
+				func(s *pgxpool.Stat) float64 { return float64(s.MaxConns()) }),
+			counter("acquires_total", "Number of successful connection acquisitions.",
+				func(s *pgxpool.Stat) float64 { return float64(s.AcquireCount()) }),
+			counter("acquire_duration_seconds_total", "Total time spent acquiring connections.",
+				func(s *pgxpool.Stat) float64 { return s.AcquireDuration().Seconds() }),
+			counter("empty_acquires_total", "Number of acquisitions that waited for a connection.",
+				func(s *pgxpool.Stat) float64 { return float64(s.EmptyAcquireCount()) }),
+			counter("canceled_acquires_total", "Number of acquisitions canceled by their context.",
+				func(s *pgxpool.Stat) float64 { return float64(s.CanceledAcquireCount()) }),
+			counter("new_connections_total", "Number of connections opened.",
+				func(s *pgxpool.Stat) float64 { return float64(s.NewConnsCount()) }),
+			counter("max_lifetime_destroys_total", "Number of connections closed for exceeding their maximum lifetime.",
+				func(s *pgxpool.Stat) float64 { return float64(s.MaxLifetimeDestroyCount()) }),
+			counter("max_idle_destroys_total", "Number of connections closed for exceeding their maximum idle time.",
+				func(s *pgxpool.Stat) float64 { return float64(s.MaxIdleDestroyCount()) }),
+		},
+	}
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, metric := range c.metrics {
+		ch <- metric.desc
+	}
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.pool.Stat()
+	for _, metric := range c.metrics {
+		ch <- prometheus.MustNewConstMetric(metric.desc, metric.valueType, metric.value(stat))
+	}
+}
+
+// VersionSource provides the latest stored day. It is implemented by service.RatesService.
+type VersionSource interface {
+	FetchDataVersion(ctx context.Context) (models.DataVersion, error)
+}
+
+// dataCollector collects the age of the stored rates on every scrape.
+type dataCollector struct {
+	versions   VersionSource
+	timeout    time.Duration
+	age        *prometheus.Desc
+	scrapeFail *prometheus.Desc
+	now        func() time.Time
+}
+
+// NewDataCollector returns a collector of the age of the newest rate stored, read from the given source.
+// Each read is bounded by the given timeout, so that an unavailable database does not stall the scrape.
+func NewDataCollector(versions VersionSource, timeout time.Duration) prometheus.Collector {
+	return &dataCollector{
+		versions: versions,
+		timeout:  timeout,
+		age: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "newest_rate_age_days"),
+			"Number of days between the newest stored rate and today (UTC).", nil, nil),
+		scrapeFail: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "newest_rate_scrape_error"),
+			"1 if the newest stored rate could not be read during the scrape, 0 otherwise.", nil, nil),
+		now: time.Now,
+	}
+}
+
+func (c *dataCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.age
+	ch <- c.scrapeFail
+}
+
+// Collect reports the age of the newest rate and whether it could be read. The age is not reported
+// while no rates are stored or the database is unavailable, so that the metric goes absent rather than
+// reporting a wrong age.
+func (c *dataCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	version, err := c.versions.FetchDataVersion(ctx)
+	var latest time.Time
+	if err == nil && version.LatestDay != "" {
+		latest, err = time.Parse(dateLayout, version.LatestDay)
+	}
+
+	failed := 0.0
+	if err != nil {
+		slog.Warn("Failed to read the newest rate for the metrics", "error", err)
+		failed = 1
+	}
+	ch <- prometheus.MustNewConstMetric(c.scrapeFail, prometheus.GaugeValue, failed)
+	if err != nil || latest.IsZero() {
+		return
+	}
+
+	now := c.now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	ch <- prometheus.MustNewConstMetric(c.age, prometheus.GaugeValue, today.Sub(latest).Hours()/hoursPerDay)
+}
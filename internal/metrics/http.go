@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// unmatchedRoute labels the requests that match no route, so that unknown paths cannot create
+// an unbounded number of series.
+const unmatchedRoute = "unmatched"
+
+// Instrument counts and times the requests served by next. The requests are labelled with the route
+// returned by route, e.g. the pattern of the matching http.ServeMux route, or "unmatched" if it is empty.
+// The method of a pattern is dropped from the route, as the requests are labelled with their method.
+func (m *Metrics) Instrument(next http.Handler, route func(r *http.Request) string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(recorder, r)
+
+		name := route(r)
+		if _, path, ok := strings.Cut(name, " "); ok {
+			name = path
+		}
+		if name == "" {
+			name = unmatchedRoute
+		}
+		status := strconv.Itoa(recorder.status)
+		m.requests.WithLabelValues(name, r.Method, status).Inc()
+		m.requestDuration.WithLabelValues(name, r.Method, status).Observe(time.Since(started).Seconds())
+	})
+}
+
+// statusRecorder records the status of a response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	if !s.wroteHeader {
+		s.status = status
+		s.wroteHeader = true
+	}
+	s.ResponseWriter.WriteHeader(status)
+}
+
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	s.wroteHeader = true
+	return s.ResponseWriter.Write(b) //nolint:wrapcheck // transparent writer
+}
+
+// Flush sends buffered data to the client.
+func (s *statusRecorder) Flush() {
+	if flusher, ok := s.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap returns the underlying writer, so that http.ResponseController can reach it.
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
@@ -0,0 +1,105 @@
+// Package metrics exposes the metrics of the service in the Prometheus text format: the HTTP requests,
+// the database pool, the sync and cleanup jobs, and the age of the stored rates.
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "rates"
+
+// Metrics collects the metrics of the service in its own registry.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+
+	jobRuns        *prometheus.CounterVec
+	jobDuration    *prometheus.HistogramVec
+	jobRows        *prometheus.CounterVec
+	jobLastSuccess *prometheus.GaugeVec
+}
+
+// NewMetrics returns a new Metrics collecting the HTTP and job metrics, the Go runtime and process
+// metrics, and the metrics of the given collectors, e.g. those of NewPoolCollector and NewDataCollector.
+func NewMetrics(extra ...prometheus.Collector) *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Number of HTTP requests by route, method and status.",
+		}, []string{"route", "method", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of HTTP requests by route, method and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method", "status"}),
+		jobRuns: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "job",
+			Name:      "runs_total",
+			Help:      "Number of sync and cleanup runs by job and result.",
+		}, []string{"job", "result"}),
+		jobDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "job",
+			Name:      "duration_seconds",
+			Help:      "Duration of sync and cleanup runs by job.",
+			Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+		}, []string{"job"}),
+		jobRows: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "job",
+			Name:      "rows_total",
+			Help:      "Number of rates inserted or changed by syncs, or deleted by cleanups.",
+		}, []string{"job"}),
+		jobLastSuccess: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "job",
+			Name:      "last_success_timestamp_seconds",
+			Help:      "Unix time of the end of the last successful run by job.",
+		}, []string{"job"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests, m.requestDuration,
+		m.jobRuns, m.jobDuration, m.jobRows, m.jobLastSuccess,
+	)
+	m.registry.MustRegister(extra...)
+
+	return m
+}
+
+// Handler returns the handler serving the metrics in the Prometheus text format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// ObserveRun records a finished sync or cleanup run. It is a sync.RunObserver.
+func (m *Metrics) ObserveRun(run models.JobRun) {
+	job := string(run.Job)
+	result := "success"
+	if run.Err != nil {
+		result = "failure"
+	}
+
+	m.jobRuns.WithLabelValues(job, result).Inc()
+	m.jobDuration.WithLabelValues(job).Observe(run.Duration.Seconds())
+	m.jobRows.WithLabelValues(job).Add(float64(run.Rows))
+	if run.Err == nil {
+		m.jobLastSuccess.WithLabelValues(job).Set(float64(run.Started.Add(run.Duration).UnixNano()) / float64(time.Second))
+	}
+}
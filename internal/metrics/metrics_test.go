@@ -0,0 +1,130 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeVersions returns a fixed latest day, or err if it is set.
+type fakeVersions struct {
+	latestDay string
+	err       error
+}
+
+func (f fakeVersions) FetchDataVersion(context.Context) (models.DataVersion, error) {
+	return models.DataVersion{LatestDay: f.latestDay}, f.err
+}
+
+func TestInstrument(t *testing.T) {
+	m := NewMetrics()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /rates/{calculationDay}", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("GET /rates/latest", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("{}"))
+	})
+	route := func(r *http.Request) string {
+		_, pattern := mux.Handler(r)
+		return pattern
+	}
+	handler := m.Instrument(mux, route)
+
+	for _, target := range []string{"/rates/latest", "/rates/latest", "/rates/2024-03-28", "/unknown"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+
+	assert.InDelta(t, 2, testutil.ToFloat64(m.requests.WithLabelValues("/rates/latest", "GET", "200")), 0)
+	assert.InDelta(t, 1,
+		testutil.ToFloat64(m.requests.WithLabelValues("/rates/{calculationDay}", "GET", "404")), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(m.requests.WithLabelValues(unmatchedRoute, "GET", "404")), 0)
+	assert.Equal(t, 3, testutil.CollectAndCount(m.requestDuration))
+}
+
+func TestObserveRun(t *testing.T) {
+	m := NewMetrics()
+	started := time.Date(2024, time.March, 28, 16, 0, 0, 0, time.UTC)
+
+	m.ObserveRun(models.JobRun{Job: models.JobSync, Started: started, Duration: time.Second, Rows: 30})
+	m.ObserveRun(models.JobRun{Job: models.JobSync, Started: started, Duration: time.Second, Err: errors.New("timeout")})
+	m.ObserveRun(models.JobRun{Job: models.JobCleanup, Started: started, Duration: time.Second, Rows: 5})
+
+	assert.InDelta(t, 1, testutil.ToFloat64(m.jobRuns.WithLabelValues("sync", "success")), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(m.jobRuns.WithLabelValues("sync", "failure")), 0)
+	assert.InDelta(t, 30, testutil.ToFloat64(m.jobRows.WithLabelValues("sync")), 0)
+	assert.InDelta(t, 5, testutil.ToFloat64(m.jobRows.WithLabelValues("cleanup")), 0)
+	assert.InDelta(t, float64(started.Unix()+1), testutil.ToFloat64(m.jobLastSuccess.WithLabelValues("sync")), 0)
+}
+
+func TestHandler(t *testing.T) {
+	// The pool connects lazily, so its statistics are available without a database.
+	pool, err := pgxpool.New(context.Background(), "postgres://postgres@127.0.0.1:1/rates?pool_max_conns=7")
+	require.NoError(t, err)
+	defer pool.Close()
+
+	data := NewDataCollector(fakeVersions{latestDay: "2024-03-26"}, time.Second).(*dataCollector)
+	data.now = func() time.Time { return time.Date(2024, time.March, 28, 9, 0, 0, 0, time.UTC) }
+
+	m := NewMetrics(NewPoolCollector(pool), data)
+	m.ObserveRun(models.JobRun{Job: models.JobSync, Duration: time.Second, Rows: 30})
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	body := rec.Body.String()
+	for _, line := range []string{
+		"rates_db_pool_max_connections 7",
+		"rates_db_pool_acquired_connections 0",
+		"rates_newest_rate_age_days 2",
+		"rates_newest_rate_scrape_error 0",
+		`rates_job_runs_total{job="sync",result="success"} 1`,
+		`rates_job_rows_total{job="sync"} 30`,
+	} {
+		assert.Contains(t, body, line+"\n")
+	}
+	assert.Contains(t, body, "go_goroutines")
+}
+
+func TestDataCollectorWithoutRates(t *testing.T) {
+	// Only the scrape error is reported, 0 without rates and 1 if the database is unavailable.
+	for _, versions := range []fakeVersions{{}, {err: errors.New("connection refused")}} {
+		collector := NewDataCollector(versions, time.Second)
+		assert.Equal(t, 1, testutil.CollectAndCount(collector))
+		assert.Equal(t, 1, testutil.CollectAndCount(collector, "rates_newest_rate_scrape_error"))
+	}
+
+	err := testutil.CollectAndCompare(NewDataCollector(fakeVersions{err: errors.New("connection refused")}, time.Second),
+		strings.NewReader(`
+# HELP rates_newest_rate_scrape_error 1 if the newest stored rate could not be read during the scrape, 0 otherwise.
+# TYPE rates_newest_rate_scrape_error gauge
+rates_newest_rate_scrape_error 1
+`))
+	require.NoError(t, err)
+}
+
+// blockingVersions is a VersionSource that waits for its context, like an unavailable database.
+type blockingVersions struct{}
+
+func (blockingVersions) FetchDataVersion(ctx context.Context) (models.DataVersion, error) {
+	<-ctx.Done()
+	return models.DataVersion{}, ctx.Err()
+}
+
+func TestDataCollectorTimeout(t *testing.T) {
+	started := time.Now()
+	assert.Equal(t, 1, testutil.CollectAndCount(NewDataCollector(blockingVersions{}, 50*time.Millisecond)))
+	assert.Less(t, time.Since(started), 5*time.Second)
+}
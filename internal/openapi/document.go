@@ -0,0 +1,45 @@
+// Package openapi validates HTTP requests and responses against an OpenAPI 3 document.
+// Loading and validation are done by kin-openapi. Documents using keywords it does not
+// implement are rejected by Load, so that no part of a document is silently left unchecked.
+package openapi
+
+import (
+	"context"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/pkg/errors"
+)
+
+// Document is a loaded and validated OpenAPI document.
+type Document struct {
+	spec   *openapi3.T
+	router routers.Router
+}
+
+// Load parses an OpenAPI document in YAML or JSON and resolves its references.
+// The function returns an error if the document is invalid, holds unresolvable references,
+// or uses schema keywords the validator does not implement.
+func Load(data []byte) (*Document, error) {
+	loader := openapi3.NewLoader()
+	spec, err := loader.LoadFromData(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load OpenAPI document")
+	}
+
+	// Unknown keywords are kept as extensions, which Validate rejects unless they start with x-.
+	if err = spec.Validate(context.Background(), openapi3.DisableExamplesValidation()); err != nil {
+		return nil, errors.Wrap(err, "invalid OpenAPI document")
+	}
+
+	// Operations are matched on their path only; the servers describe where the API is deployed,
+	// which the service itself does not know.
+	spec.Servers = nil
+	router, err := legacy.NewRouter(spec)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build OpenAPI router")
+	}
+
+	return &Document{spec: spec, router: router}, nil
+}
@@ -0,0 +1,155 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/pkg/errors"
+)
+
+// ValidationError describes a request or response that does not match the document.
+type ValidationError struct {
+	// In is the location of the offending value: path, query, header, body or response.
+	In string
+	// Param names the offending parameter, if any.
+	Param string
+	// Message describes the mismatch.
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Param != "" {
+		return fmt.Sprintf("%s parameter %s: %s", e.In, e.Param, e.Message)
+	}
+	return fmt.Sprintf("%s: %s", e.In, e.Message)
+}
+
+// options are the validation options of requests and responses. Authentication is done by the
+// handlers, and requests are validated as they were sent, without setting defaults.
+var options = &openapi3filter.Options{
+	AuthenticationFunc:    openapi3filter.NoopAuthenticationFunc,
+	IncludeResponseStatus: true,
+	SkipSettingDefaults:   true,
+}
+
+// ValidateRequest validates the parameters and the body of a request against the matching operation.
+// Requests the document does not describe are not validated. The body is restored after reading,
+// so that the request can still be served.
+func (d *Document) ValidateRequest(r *http.Request) error {
+	input, ok := d.requestInput(r)
+	if !ok {
+		return nil
+	}
+
+	if err := openapi3filter.ValidateRequest(r.Context(), input); err != nil {
+		return validationError(err)
+	}
+
+	return nil
+}
+
+// ValidateResponse validates the status and the body of a response against the matching operation.
+// Responses to requests the document does not describe are not validated. Bodies are validated for
+// JSON media types only; other media types are checked against the declared content types.
+func (d *Document) ValidateResponse(r *http.Request, status int, header http.Header, body []byte) error {
+	input, ok := d.requestInput(r)
+	if !ok {
+		return nil
+	}
+
+	mediaType := mediaTypeOf(header.Get("Content-Type"))
+	responseOptions := *options
+	responseOptions.ExcludeResponseBody = len(body) == 0 || !IsJSON(mediaType)
+
+	err := openapi3filter.ValidateResponse(context.Background(), &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: input,
+		Status:                 status,
+		Header:                 header,
+		Body:                   io.NopCloser(bytes.NewReader(body)),
+		Options:                &responseOptions,
+	})
+	if err != nil {
+		return &ValidationError{In: "response", Message: fmt.Sprintf("status %d: %s", status, err.Error())}
+	}
+
+	if len(body) == 0 || IsJSON(mediaType) || r.Method == http.MethodHead {
+		return nil
+	}
+
+	response := input.Route.Operation.Responses.Status(status)
+	if response == nil {
+		response = input.Route.Operation.Responses.Default()
+	}
+	if response != nil && len(response.Value.Content) > 0 && response.Value.Content.Get(mediaType) == nil {
+		return &ValidationError{
+			In:      "response",
+			Message: fmt.Sprintf("undocumented media type %q for status %d", mediaType, status),
+		}
+	}
+
+	return nil
+}
+
+// requestInput finds the operation matching a request. HEAD requests match the GET operation of their path.
+// The function returns false if the document does not describe the request.
+func (d *Document) requestInput(r *http.Request) (*openapi3filter.RequestValidationInput, bool) {
+	match := r
+	if r.Method == http.MethodHead {
+		match = r.Clone(r.Context())
+		match.Method = http.MethodGet
+	}
+
+	route, pathParams, err := d.router.FindRoute(match)
+	if err != nil {
+		return nil, false
+	}
+
+	return &openapi3filter.RequestValidationInput{
+		Request:    r,
+		PathParams: pathParams,
+		Route:      route,
+		Options:    options,
+	}, true
+}
+
+// validationError converts a request validation error of kin-openapi into a ValidationError.
+func validationError(err error) error {
+	var requestErr *openapi3filter.RequestError
+	if errors.As(err, &requestErr) {
+		message := requestErr.Reason
+		if requestErr.Err != nil {
+			if message == "" {
+				message = requestErr.Err.Error()
+			} else {
+				message += ": " + requestErr.Err.Error()
+			}
+		}
+		if requestErr.Parameter != nil {
+			return &ValidationError{In: requestErr.Parameter.In, Param: requestErr.Parameter.Name, Message: message}
+		}
+		return &ValidationError{In: "body", Message: message}
+	}
+
+	return &ValidationError{In: "request", Message: err.Error()}
+}
+
+// IsJSON reports whether a media type is JSON, including JSON based types such as application/problem+json.
+func IsJSON(mediaType string) bool {
+	mediaType = mediaTypeOf(mediaType)
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// mediaTypeOf returns the media type of a Content-Type header without its parameters.
+func mediaTypeOf(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(contentType))
+	}
+	return mediaType
+}
@@ -0,0 +1,204 @@
+package openapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDocument = `
+openapi: "3.0.3"
+info:
+  title: Items
+  version: "1.0.0"
+paths:
+  /items/latest:
+    get:
+      parameters:
+        - $ref: "#/components/parameters/Limit"
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Item"
+        default:
+          description: Problem
+          content:
+            application/problem+json:
+              schema:
+                type: object
+                required: [code]
+  /items/{day}:
+    get:
+      parameters:
+        - name: day
+          in: path
+          required: true
+          schema:
+            type: string
+            format: date
+        - name: kind
+          in: query
+          required: true
+          schema:
+            type: string
+            enum: ["a", "b"]
+      responses:
+        "200":
+          description: OK
+    post:
+      parameters:
+        - name: day
+          in: path
+          required: true
+          schema:
+            type: string
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: "#/components/schemas/Item"
+      responses:
+        "201":
+          description: Created
+components:
+  parameters:
+    Limit:
+      name: limit
+      in: query
+      schema:
+        type: integer
+        minimum: 0
+  schemas:
+    Item:
+      type: object
+      required: [code, rates]
+      additionalProperties: false
+      properties:
+        code:
+          type: string
+          pattern: "^[A-Z]{3}$"
+        rates:
+          type: object
+          additionalProperties:
+            type: number
+        change:
+          type: number
+          nullable: true
+        kind:
+          oneOf:
+            - type: string
+              enum: ["a", "b"]
+            - type: integer
+`
+
+func TestLoad(t *testing.T) {
+	_, err := Load([]byte(testDocument))
+	require.NoError(t, err)
+
+	_, err = Load([]byte(strings.Replace(testDocument, "#/components/schemas/Item", "#/components/schemas/Missing", 1)))
+	require.Error(t, err)
+
+	// Keywords the validator does not implement are rejected rather than ignored.
+	_, err = Load([]byte(strings.Replace(testDocument, "pattern: \"^[A-Z]{3}$\"", "const: USD", 1)))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "const")
+}
+
+func TestValidateRequest(t *testing.T) {
+	doc, err := Load([]byte(testDocument))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		method        string
+		target        string
+		body          string
+		expectedParam string
+		expectErr     bool
+	}{
+		{name: "Valid", method: http.MethodGet, target: "/items/latest?limit=5"},
+		{name: "Invalid integer", method: http.MethodGet, target: "/items/latest?limit=ten", expectedParam: "limit", expectErr: true},
+		{name: "Below minimum", method: http.MethodGet, target: "/items/latest?limit=-1", expectedParam: "limit", expectErr: true},
+		{name: "Invalid path date", method: http.MethodGet, target: "/items/2024-13-01?kind=a", expectedParam: "day", expectErr: true},
+		{name: "Missing required query", method: http.MethodGet, target: "/items/2024-03-01", expectedParam: "kind", expectErr: true},
+		{name: "Invalid enum", method: http.MethodGet, target: "/items/2024-03-01?kind=c", expectedParam: "kind", expectErr: true},
+		{name: "Undocumented path", method: http.MethodGet, target: "/unknown"},
+		{name: "Valid body", method: http.MethodPost, target: "/items/x", body: `{"code":"USD","rates":{"EUR":1}}`},
+		{name: "Missing body", method: http.MethodPost, target: "/items/x", expectErr: true},
+		{name: "Invalid body", method: http.MethodPost, target: "/items/x", body: `{"code":"usd","rates":{}}`, expectErr: true},
+		{name: "Valid oneOf", method: http.MethodPost, target: "/items/x", body: `{"code":"USD","rates":{},"kind":2}`},
+		{name: "Invalid oneOf", method: http.MethodPost, target: "/items/x", body: `{"code":"USD","rates":{},"kind":"c"}`, expectErr: true},
+		{name: "HEAD request", method: http.MethodHead, target: "/items/latest?limit=ten", expectedParam: "limit", expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			err := doc.ValidateRequest(req)
+			if !tc.expectErr {
+				require.NoError(t, err)
+				return
+			}
+
+			var validationErr *ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tc.expectedParam, validationErr.Param)
+		})
+	}
+}
+
+func TestValidateResponse(t *testing.T) {
+	doc, err := Load([]byte(testDocument))
+	require.NoError(t, err)
+
+	jsonHeader := http.Header{"Content-Type": []string{"application/json"}}
+	problemHeader := http.Header{"Content-Type": []string{"application/problem+json"}}
+
+	testCases := []struct {
+		name      string
+		status    int
+		header    http.Header
+		body      string
+		expectErr string
+	}{
+		{name: "Valid", status: http.StatusOK, header: jsonHeader, body: `{"code":"USD","rates":{"EUR":0.92}}`},
+		{name: "Missing property", status: http.StatusOK, header: jsonHeader, body: `{"code":"USD"}`, expectErr: `"rates"`},
+		{name: "Wrong type", status: http.StatusOK, header: jsonHeader, body: `{"code":"USD","rates":[]}`, expectErr: `"/rates"`},
+		{name: "Wrong value type", status: http.StatusOK, header: jsonHeader, body: `{"code":"USD","rates":{"EUR":"1"}}`, expectErr: `"/rates/EUR"`},
+		{name: "Null value", status: http.StatusOK, header: jsonHeader, body: `{"code":"USD","rates":{},"change":null}`},
+		{name: "Nullable value", status: http.StatusOK, header: jsonHeader, body: `{"code":"USD","rates":{},"change":0.5}`},
+		{name: "Null value not allowed", status: http.StatusOK, header: jsonHeader, body: `{"code":"USD","rates":null}`, expectErr: `"/rates"`},
+		{name: "Unexpected property", status: http.StatusOK, header: jsonHeader, body: `{"code":"USD","rates":{},"x":1}`, expectErr: `"x"`},
+		{name: "Empty body", status: http.StatusOK, header: jsonHeader},
+		{name: "Default response", status: http.StatusBadRequest, header: problemHeader, body: `{"code":"invalid_parameter"}`},
+		{name: "Undocumented media type", status: http.StatusOK, header: http.Header{"Content-Type": []string{"text/csv"}}, body: "a,b", expectErr: "media type"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/items/latest", nil)
+
+			err := doc.ValidateResponse(req, tc.status, tc.header, []byte(tc.body))
+			if tc.expectErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectErr)
+		})
+	}
+
+	err = doc.ValidateResponse(httptest.NewRequest(http.MethodPost, "/items/x", nil), http.StatusOK, jsonHeader, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 200")
+}
@@ -0,0 +1,81 @@
+// Package params validates the request parameters shared by the HTTP and gRPC APIs.
+// The parsers return plain errors; each transport maps them to its own error responses.
+package params
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// BaseCurrency is the base currency of requests that do not ask for another one.
+	BaseCurrency = "EUR"
+	// DateLayout is the format of the dates of requests.
+	DateLayout = "2006-01-02"
+	// DefaultRange is the number of days analyzed if no period is requested.
+	DefaultRange       = 10
+	currencyCodeLength = 3
+)
+
+// IsCurrencyCode reports whether code looks like an ISO 4217 alphabetic code.
+func IsCurrencyCode(code string) bool {
+	if len(code) != currencyCodeLength {
+		return false
+	}
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseBase validates and upper-cases a base currency.
+// An empty base currency means BaseCurrency.
+func ParseBase(base string) (string, error) {
+	base = strings.ToUpper(strings.TrimSpace(base))
+	if base == "" {
+		return BaseCurrency, nil
+	}
+
+	if !IsCurrencyCode(base) {
+		return "", errors.Errorf("invalid base currency %q", base)
+	}
+
+	return base, nil
+}
+
+// ParseSymbols validates and upper-cases currency codes, removing duplicates.
+// A nil slice is returned if no codes are given.
+func ParseSymbols(codes []string) ([]string, error) {
+	if len(codes) == 0 {
+		return nil, nil
+	}
+
+	seen := make(map[string]struct{}, len(codes))
+	symbols := make([]string, 0, len(codes))
+	for _, symbol := range codes {
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		if !IsCurrencyCode(symbol) {
+			return nil, errors.Errorf("invalid symbol %q", symbol)
+		}
+		if _, ok := seen[symbol]; ok {
+			continue
+		}
+		seen[symbol] = struct{}{}
+		symbols = append(symbols, symbol)
+	}
+
+	return symbols, nil
+}
+
+// ParseDate parses a date in YYYY-MM-DD format. The name of the date prefixes the error message.
+func ParseDate(name, date string) (time.Time, error) {
+	day, err := time.Parse(DateLayout, date)
+	if err != nil {
+		return time.Time{}, errors.Errorf("invalid %s %q, expected YYYY-MM-DD", name, date)
+	}
+	return day, nil
+}
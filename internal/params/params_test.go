@@ -0,0 +1,50 @@
+package params
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsCurrencyCode(t *testing.T) {
+	assert.True(t, IsCurrencyCode("USD"))
+	assert.False(t, IsCurrencyCode("usd"))
+	assert.False(t, IsCurrencyCode("US"))
+	assert.False(t, IsCurrencyCode("U5D"))
+}
+
+func TestParseBase(t *testing.T) {
+	base, err := ParseBase("")
+	require.NoError(t, err)
+	assert.Equal(t, BaseCurrency, base)
+
+	base, err = ParseBase(" gbp ")
+	require.NoError(t, err)
+	assert.Equal(t, "GBP", base)
+
+	_, err = ParseBase("USDT")
+	require.EqualError(t, err, `invalid base currency "USDT"`)
+}
+
+func TestParseSymbols(t *testing.T) {
+	symbols, err := ParseSymbols([]string{"usd", "JPY", " usd"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"USD", "JPY"}, symbols)
+
+	symbols, err = ParseSymbols(nil)
+	require.NoError(t, err)
+	assert.Nil(t, symbols)
+
+	_, err = ParseSymbols([]string{"USD", ""})
+	require.EqualError(t, err, `invalid symbol ""`)
+}
+
+func TestParseDate(t *testing.T) {
+	day, err := ParseDate("date", "2024-02-29")
+	require.NoError(t, err)
+	assert.Equal(t, 29, day.Day())
+
+	_, err = ParseDate("start date", "2024-13-01")
+	require.EqualError(t, err, `invalid start date "2024-13-01", expected YYYY-MM-DD`)
+}
@@ -0,0 +1,65 @@
+package ratelimit
+
+import (
+	"math"
+	"time"
+)
+
+// Policy is the token bucket policy of a route group: the bucket of each client holds up to Burst
+// tokens and is refilled with Requests tokens per Period. Every request takes a token.
+type Policy struct {
+	Group    string
+	Requests int
+	Period   time.Duration
+	Burst    int
+}
+
+// rate returns the number of tokens refilled per second.
+func (p Policy) rate() float64 {
+	return float64(p.Requests) / p.Period.Seconds()
+}
+
+// refill returns the tokens of a bucket holding the given tokens after elapsed time.
+func (p Policy) refill(tokens float64, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return tokens
+	}
+	return math.Min(float64(p.Burst), tokens+elapsed.Seconds()*p.rate())
+}
+
+// fillTime returns the time an empty bucket takes to be full again.
+func (p Policy) fillTime() time.Duration {
+	return p.duration(float64(p.Burst))
+}
+
+// duration returns the time the given number of tokens takes to be refilled.
+func (p Policy) duration(tokens float64) time.Duration {
+	if tokens <= 0 {
+		return 0
+	}
+	return time.Duration(tokens / p.rate() * float64(time.Second))
+}
+
+// Result is the outcome of taking a token.
+type Result struct {
+	Policy Policy
+	// Allowed reports whether a token was taken, i.e. whether the request may be served.
+	Allowed bool
+	// Remaining is the number of whole tokens left in the bucket.
+	Remaining int
+	// Reset is the time until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is the time until the next token is available. It is zero if a token is left.
+	RetryAfter time.Duration
+}
+
+// newResult returns the result of taking a token from a bucket now holding the given tokens.
+func newResult(policy Policy, tokens float64, allowed bool) Result {
+	return Result{
+		Policy:     policy,
+		Allowed:    allowed,
+		Remaining:  int(math.Floor(tokens)),
+		Reset:      policy.duration(float64(policy.Burst) - tokens),
+		RetryAfter: policy.duration(1 - tokens),
+	}
+}
@@ -0,0 +1,139 @@
+// Package ratelimit limits the requests of each client with token buckets, configured per route group.
+package ratelimit
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/light-bringer/rates-exchanger-service/internal/logging"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+)
+
+// pruneInterval is how often the buckets that are full again are deleted.
+const pruneInterval = 5 * time.Minute
+
+// prefix is a path prefix of a route group.
+type prefix struct {
+	path   string
+	policy Policy
+}
+
+// Limiter matches requests to the policies of the route groups and takes their tokens from the store.
+type Limiter struct {
+	store Store
+	// prefixes are the path prefixes of the limited groups, longest first.
+	prefixes []prefix
+	// idle is the longest time a bucket of any group takes to be full again.
+	idle time.Duration
+}
+
+// NewLimiter returns a new Limiter with the given store and route groups.
+// It fails if a group is invalid; the burst of a group defaults to its number of requests.
+func NewLimiter(store Store, config models.RateLimitConfig) (*Limiter, error) {
+	limiter := &Limiter{store: store}
+	names := make(map[string]bool, len(config.Groups))
+	paths := make(map[string]string)
+
+	for _, group := range config.Groups {
+		if group.Name == "" || strings.Contains(group.Name, ":") {
+			return nil, errors.Errorf("invalid rate limit group name %q", group.Name)
+		}
+		if names[group.Name] {
+			return nil, errors.Errorf("duplicate rate limit group %q", group.Name)
+		}
+		names[group.Name] = true
+
+		if len(group.Paths) == 0 {
+			return nil, errors.Errorf("rate limit group %q has no paths", group.Name)
+		}
+		if group.Requests < 0 || group.Burst < 0 {
+			return nil, errors.Errorf("rate limit group %q has a negative limit", group.Name)
+		}
+		if group.Requests > 0 && group.Period <= 0 {
+			return nil, errors.Errorf("rate limit group %q has no period", group.Name)
+		}
+
+		policy := Policy{Group: group.Name, Requests: group.Requests, Period: group.Period, Burst: group.Burst}
+		if policy.Burst == 0 {
+			policy.Burst = policy.Requests
+		}
+		if policy.Requests > 0 && policy.fillTime() > limiter.idle {
+			limiter.idle = policy.fillTime()
+		}
+
+		for _, path := range group.Paths {
+			if !strings.HasPrefix(path, "/") {
+				return nil, errors.Errorf("rate limit group %q has an invalid path %q", group.Name, path)
+			}
+			if other, ok := paths[path]; ok {
+				return nil, errors.Errorf("path %q is in rate limit groups %q and %q", path, other, group.Name)
+			}
+			paths[path] = group.Name
+			limiter.prefixes = append(limiter.prefixes, prefix{path: path, policy: policy})
+		}
+	}
+
+	sort.SliceStable(limiter.prefixes, func(i, j int) bool {
+		return len(limiter.prefixes[i].path) > len(limiter.prefixes[j].path)
+	})
+
+	return limiter, nil
+}
+
+// Policy returns the policy of the group with the longest path prefix matching the path.
+// A prefix matches whole path segments, so "/rates" matches "/rates/latest" but not "/ratesx".
+// The path is not limited if it matches no group, or a group without a limit.
+func (l *Limiter) Policy(path string) (Policy, bool) {
+	for _, candidate := range l.prefixes {
+		if MatchesPrefix(path, candidate.path) {
+			return candidate.policy, candidate.policy.Requests > 0
+		}
+	}
+	return Policy{}, false
+}
+
+// MatchesPrefix reports whether the path is the prefix or below it, matching whole path segments.
+// It is shared with the path prefixes of the authentication, so that both match paths alike.
+func MatchesPrefix(path, prefix string) bool {
+	if path == prefix {
+		return true
+	}
+	return strings.HasPrefix(path, strings.TrimSuffix(prefix, "/")+"/")
+}
+
+// Take takes a token from the bucket of the client in the group of the policy.
+// The client identifies the caller, e.g. by API key or IP address.
+func (l *Limiter) Take(ctx context.Context, policy Policy, client string) (Result, error) {
+	tokens, allowed, err := l.store.Take(ctx, policy.Group+":"+client, policy)
+	if err != nil {
+		return Result{}, err
+	}
+	return newResult(policy, tokens, allowed), nil
+}
+
+// Run deletes the buckets that are full again periodically until the context is cancelled,
+// since a full bucket is the same as none.
+func (l *Limiter) Run(ctx context.Context) {
+	logger := logging.FromContext(ctx)
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Debug("Stopping rate limit pruning...")
+			return
+		case <-ticker.C:
+		}
+
+		pruned, err := l.store.Prune(ctx, l.idle)
+		if err != nil {
+			logger.Error("Failed to prune rate limit buckets", "error", err)
+			continue
+		}
+		logger.Debug("Pruned rate limit buckets", "count", pruned)
+	}
+}
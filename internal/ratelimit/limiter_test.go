@@ -0,0 +1,144 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLimiter(t *testing.T) {
+	testCases := []struct {
+		name   string
+		groups []models.RateLimitGroup
+		err    string
+	}{
+		{
+			name: "Valid",
+			groups: []models.RateLimitGroup{
+				{Name: "rates", Paths: []string{"/rates", "/convert"}, Requests: 60, Period: time.Minute},
+				{Name: "health", Paths: []string{"/health"}},
+			},
+		},
+		{
+			name:   "Missing name",
+			groups: []models.RateLimitGroup{{Paths: []string{"/rates"}, Requests: 1, Period: time.Second}},
+			err:    `invalid rate limit group name ""`,
+		},
+		{
+			name: "Duplicate name",
+			groups: []models.RateLimitGroup{
+				{Name: "rates", Paths: []string{"/rates"}},
+				{Name: "rates", Paths: []string{"/convert"}},
+			},
+			err: `duplicate rate limit group "rates"`,
+		},
+		{
+			name:   "No paths",
+			groups: []models.RateLimitGroup{{Name: "rates", Requests: 1, Period: time.Second}},
+			err:    `rate limit group "rates" has no paths`,
+		},
+		{
+			name:   "Relative path",
+			groups: []models.RateLimitGroup{{Name: "rates", Paths: []string{"rates"}}},
+			err:    `rate limit group "rates" has an invalid path "rates"`,
+		},
+		{
+			name:   "Missing period",
+			groups: []models.RateLimitGroup{{Name: "rates", Paths: []string{"/rates"}, Requests: 1}},
+			err:    `rate limit group "rates" has no period`,
+		},
+		{
+			name: "Path in two groups",
+			groups: []models.RateLimitGroup{
+				{Name: "rates", Paths: []string{"/rates"}},
+				{Name: "other", Paths: []string{"/rates"}},
+			},
+			err: `path "/rates" is in rate limit groups "rates" and "other"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewLimiter(NewMemoryStore(), models.RateLimitConfig{Groups: tc.groups})
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestLimiter_Policy(t *testing.T) {
+	limiter, err := NewLimiter(NewMemoryStore(), models.RateLimitConfig{
+		Groups: []models.RateLimitGroup{
+			{Name: "all", Paths: []string{"/"}, Requests: 100, Period: time.Minute},
+			{Name: "rates", Paths: []string{"/rates"}, Requests: 60, Period: time.Minute, Burst: 10},
+			{Name: "batch", Paths: []string{"/rates/batch"}, Requests: 10, Period: time.Minute},
+			{Name: "health", Paths: []string{"/health"}},
+		},
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		path    string
+		group   string
+		limited bool
+	}{
+		{path: "/rates", group: "rates", limited: true},
+		{path: "/rates/latest", group: "rates", limited: true},
+		{path: "/rates/batch", group: "batch", limited: true},
+		{path: "/ratesx", group: "all", limited: true},
+		{path: "/convert", group: "all", limited: true},
+		{path: "/health/ready", limited: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			policy, limited := limiter.Policy(tc.path)
+			assert.Equal(t, tc.limited, limited)
+			if limited {
+				assert.Equal(t, tc.group, policy.Group)
+			}
+		})
+	}
+
+	policy, _ := limiter.Policy("/rates/latest")
+	assert.Equal(t, 10, policy.Burst)
+	policy, _ = limiter.Policy("/rates/batch")
+	assert.Equal(t, 10, policy.Burst, "burst defaults to requests")
+}
+
+func TestLimiter_Take(t *testing.T) {
+	limiter, err := NewLimiter(NewMemoryStore(), models.RateLimitConfig{
+		Groups: []models.RateLimitGroup{
+			{Name: "rates", Paths: []string{"/rates"}, Requests: 2, Period: time.Minute},
+		},
+	})
+	require.NoError(t, err)
+	policy, _ := limiter.Policy("/rates/latest")
+
+	first, err := limiter.Take(context.Background(), policy, "ip:192.0.2.1")
+	require.NoError(t, err)
+	assert.True(t, first.Allowed)
+	assert.Equal(t, 1, first.Remaining)
+	assert.Zero(t, first.RetryAfter)
+
+	_, err = limiter.Take(context.Background(), policy, "ip:192.0.2.1")
+	require.NoError(t, err)
+
+	throttled, err := limiter.Take(context.Background(), policy, "ip:192.0.2.1")
+	require.NoError(t, err)
+	assert.False(t, throttled.Allowed)
+	assert.Equal(t, 0, throttled.Remaining)
+	assert.InDelta(t, 30*time.Second, throttled.RetryAfter, float64(time.Second))
+	assert.InDelta(t, time.Minute, throttled.Reset, float64(time.Second))
+
+	other, err := limiter.Take(context.Background(), policy, "ip:192.0.2.2")
+	require.NoError(t, err)
+	assert.True(t, other.Allowed, "each client has its own bucket")
+}
@@ -0,0 +1,60 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// bucket is the state of a token bucket.
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// MemoryStore keeps the token buckets in memory. It is safe for concurrent use.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]bucket
+	now     func() time.Time
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]bucket), now: time.Now}
+}
+
+func (m *MemoryStore) Take(_ context.Context, key string, policy Policy) (float64, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	tokens := float64(policy.Burst)
+	if state, ok := m.buckets[key]; ok {
+		tokens = policy.refill(state.tokens, now.Sub(state.updated))
+	}
+
+	allowed := tokens >= 1
+	if allowed {
+		tokens--
+	}
+	m.buckets[key] = bucket{tokens: tokens, updated: now}
+
+	return tokens, allowed, nil
+}
+
+func (m *MemoryStore) Prune(_ context.Context, idle time.Duration) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var pruned int64
+	cutoff := m.now().Add(-idle)
+	for key, state := range m.buckets {
+		if state.updated.Before(cutoff) {
+			delete(m.buckets, key)
+			pruned++
+		}
+	}
+
+	return pruned, nil
+}
@@ -0,0 +1,87 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore_Take(t *testing.T) {
+	// 2 requests per second with a burst of 3.
+	policy := Policy{Group: "rates", Requests: 2, Period: time.Second, Burst: 3}
+	start := time.Date(2024, time.March, 28, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name    string
+		elapsed []time.Duration
+		tokens  float64
+		allowed bool
+	}{
+		{
+			name:    "New bucket is full",
+			elapsed: []time.Duration{0},
+			tokens:  2,
+			allowed: true,
+		},
+		{
+			name:    "Burst exhausted",
+			elapsed: []time.Duration{0, 0, 0, 0},
+			tokens:  0,
+			allowed: false,
+		},
+		{
+			name:    "Refilled over time",
+			elapsed: []time.Duration{0, 0, 0, 500 * time.Millisecond},
+			tokens:  0,
+			allowed: true,
+		},
+		{
+			name:    "Refill capped at burst",
+			elapsed: []time.Duration{0, time.Hour},
+			tokens:  2,
+			allowed: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := NewMemoryStore()
+			now := start
+			store.now = func() time.Time { return now }
+
+			var tokens float64
+			var allowed bool
+			for _, elapsed := range tc.elapsed {
+				now = now.Add(elapsed)
+				var err error
+				tokens, allowed, err = store.Take(context.Background(), "rates:ip:192.0.2.1", policy)
+				require.NoError(t, err)
+			}
+
+			assert.InDelta(t, tc.tokens, tokens, 1e-9)
+			assert.Equal(t, tc.allowed, allowed)
+		})
+	}
+}
+
+func TestMemoryStore_Prune(t *testing.T) {
+	policy := Policy{Group: "rates", Requests: 1, Period: time.Second, Burst: 1}
+	now := time.Date(2024, time.March, 28, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+
+	_, _, err := store.Take(context.Background(), "rates:ip:192.0.2.1", policy)
+	require.NoError(t, err)
+	now = now.Add(time.Minute)
+	_, _, err = store.Take(context.Background(), "rates:ip:192.0.2.2", policy)
+	require.NoError(t, err)
+
+	pruned, err := store.Prune(context.Background(), 30*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), pruned)
+	assert.Len(t, store.buckets, 1)
+	assert.Contains(t, store.buckets, "rates:ip:192.0.2.2")
+}
@@ -0,0 +1,66 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/light-bringer/rates-exchanger-service/internal/logging"
+	"github.com/pkg/errors"
+)
+
+// takeQuery refills the bucket of a key by the time elapsed since its last update, and takes a token if
+// one is left, in a single statement, so that concurrent requests of all instances are counted.
+// A new bucket is created full. The database clock is used, so that the clocks of the instances do not matter.
+// The parameters are the key, the capacity of the bucket and the tokens refilled per second.
+const takeQuery = `
+INSERT INTO %[1]s AS b (key, tokens, allowed, updated_at)
+VALUES ($1, $2::double precision - 1, $2::double precision >= 1, now())
+ON CONFLICT (key) DO UPDATE SET
+	tokens = %[2]s - CASE WHEN %[2]s >= 1 THEN 1 ELSE 0 END,
+	allowed = %[2]s >= 1,
+	updated_at = now()
+RETURNING tokens, allowed`
+
+// refilledTokens is the number of tokens of an existing bucket after refilling it.
+const refilledTokens = `LEAST($2::double precision, b.tokens + ` +
+	`GREATEST(EXTRACT(EPOCH FROM now() - b.updated_at)::double precision, 0) * $3::double precision)`
+
+// PostgresStore keeps the token buckets in Postgres, so that all instances share them.
+type PostgresStore struct {
+	db    *pgxpool.Pool
+	table string
+	take  string
+}
+
+// NewPostgresStore returns a new PostgresStore using the table of the given schema.
+func NewPostgresStore(db *pgxpool.Pool, schema string) *PostgresStore {
+	table := schema + ".rate_limit_buckets"
+	return &PostgresStore{
+		db:    db,
+		table: table,
+		take:  fmt.Sprintf(takeQuery, table, refilledTokens),
+	}
+}
+
+func (p *PostgresStore) Take(ctx context.Context, key string, policy Policy) (float64, bool, error) {
+	var tokens float64
+	var allowed bool
+	if err := p.db.QueryRow(ctx, p.take, key, policy.Burst, policy.rate()).Scan(&tokens, &allowed); err != nil {
+		logging.FromContext(ctx).Error("Failed to take rate limit token", "error", err)
+		return 0, false, errors.Wrap(err, "failed to take rate limit token")
+	}
+
+	return tokens, allowed, nil
+}
+
+func (p *PostgresStore) Prune(ctx context.Context, idle time.Duration) (int64, error) {
+	res, err := p.db.Exec(ctx, "DELETE FROM "+p.table+" WHERE updated_at < now() - $1::interval", idle)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to prune rate limit buckets", "error", err)
+		return 0, errors.Wrap(err, "failed to prune rate limit buckets")
+	}
+
+	return res.RowsAffected(), nil
+}
@@ -0,0 +1,129 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/light-bringer/rates-exchanger-service/internal/testdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testKey is the bucket key used by the tests.
+const testKey = "rates:ip:192.0.2.1"
+
+// newTestPostgresStore returns a PostgresStore on a fresh schema, with the pool and schema to
+// inspect the buckets. The test is skipped if no test database is set; see testdb.New.
+func newTestPostgresStore(t *testing.T) (*PostgresStore, *pgxpool.Pool, string) {
+	t.Helper()
+	pool, schema := testdb.New(t)
+	return NewPostgresStore(pool, schema), pool, schema
+}
+
+// ageBucket moves the last update of a bucket back by the given time, since the store uses the
+// database clock.
+func ageBucket(t *testing.T, pool *pgxpool.Pool, schema, key string, age time.Duration) {
+	t.Helper()
+	_, err := pool.Exec(context.Background(),
+		"UPDATE "+schema+".rate_limit_buckets SET updated_at = updated_at - $2::interval WHERE key = $1", key, age)
+	require.NoError(t, err)
+}
+
+func TestPostgresStore_Take(t *testing.T) {
+	store, pool, schema := newTestPostgresStore(t)
+	ctx := context.Background()
+	// One request per hour with a burst of 3, so that the bucket is not refilled while the test runs.
+	policy := Policy{Group: "rates", Requests: 1, Period: time.Hour, Burst: 3}
+
+	tokens, allowed, err := store.Take(ctx, testKey, policy)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	assert.InDelta(t, 2, tokens, 1e-3, "a new bucket is full")
+
+	for _, want := range []float64{1, 0} {
+		tokens, allowed, err = store.Take(ctx, testKey, policy)
+		require.NoError(t, err)
+		assert.True(t, allowed)
+		assert.InDelta(t, want, tokens, 1e-3)
+	}
+
+	tokens, allowed, err = store.Take(ctx, testKey, policy)
+	require.NoError(t, err)
+	assert.False(t, allowed, "the burst is exhausted")
+	assert.InDelta(t, 0, tokens, 1e-3)
+
+	ageBucket(t, pool, schema, testKey, 90*time.Minute)
+	tokens, allowed, err = store.Take(ctx, testKey, policy)
+	require.NoError(t, err)
+	assert.True(t, allowed, "the bucket is refilled over time")
+	assert.InDelta(t, 0.5, tokens, 1e-3)
+
+	ageBucket(t, pool, schema, testKey, 24*time.Hour)
+	tokens, allowed, err = store.Take(ctx, testKey, policy)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	assert.InDelta(t, 2, tokens, 1e-3, "the refill is capped at the burst")
+
+	tokens, allowed, err = store.Take(ctx, "rates:ip:192.0.2.2", policy)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	assert.InDelta(t, 2, tokens, 1e-3, "the buckets of other keys are separate")
+}
+
+func TestPostgresStore_TakeConcurrently(t *testing.T) {
+	store, _, _ := newTestPostgresStore(t)
+	ctx := context.Background()
+	const burst, requests = 10, 25
+	policy := Policy{Group: "rates", Requests: 1, Period: time.Hour, Burst: burst}
+
+	// Requests served at once, possibly by several instances, take the tokens of the same bucket.
+	var mu sync.Mutex
+	allowedCount := 0
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, allowed, err := store.Take(ctx, testKey, policy)
+			if !assert.NoError(t, err) || !allowed {
+				return
+			}
+			mu.Lock()
+			allowedCount++
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, burst, allowedCount, "exactly the burst is allowed")
+}
+
+func TestPostgresStore_Prune(t *testing.T) {
+	store, pool, schema := newTestPostgresStore(t)
+	ctx := context.Background()
+	policy := Policy{Group: "rates", Requests: 1, Period: time.Second, Burst: 1}
+
+	_, _, err := store.Take(ctx, testKey, policy)
+	require.NoError(t, err)
+	_, _, err = store.Take(ctx, "rates:ip:192.0.2.2", policy)
+	require.NoError(t, err)
+	ageBucket(t, pool, schema, testKey, time.Minute)
+
+	pruned, err := store.Prune(ctx, 30*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), pruned)
+
+	var keys []string
+	rows, err := pool.Query(ctx, "SELECT key FROM "+schema+".rate_limit_buckets")
+	require.NoError(t, err)
+	for rows.Next() {
+		var key string
+		require.NoError(t, rows.Scan(&key))
+		keys = append(keys, key)
+	}
+	require.NoError(t, rows.Err())
+	assert.Equal(t, []string{"rates:ip:192.0.2.2"}, keys)
+}
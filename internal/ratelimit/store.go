@@ -0,0 +1,33 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+)
+
+// Store keeps the token buckets of the clients.
+// MemoryStore limits each instance on its own; PostgresStore shares the buckets between instances.
+type Store interface {
+	// Take takes a token from the bucket of the key, which is created full and refilled as set by the policy.
+	// It returns the tokens left and whether a token was taken; no token is taken from a bucket holding
+	// less than one.
+	Take(ctx context.Context, key string, policy Policy) (tokens float64, allowed bool, err error)
+	// Prune deletes the buckets unused for the given time, which are full again and need not be kept.
+	Prune(ctx context.Context, idle time.Duration) (int64, error)
+}
+
+// NewStore returns the store of the given type, keeping the buckets in the given schema for Postgres.
+func NewStore(storeType models.RateLimitStoreType, db *pgxpool.Pool, schema string) (Store, error) {
+	switch storeType {
+	case models.RateLimitStoreMemory:
+		return NewMemoryStore(), nil
+	case models.RateLimitStorePostgres:
+		return NewPostgresStore(db, schema), nil
+	default:
+		return nil, errors.Errorf("unknown rate limit store %q", storeType)
+	}
+}
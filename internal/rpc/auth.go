@@ -0,0 +1,129 @@
+package rpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/light-bringer/rates-exchanger-service/internal/apikey"
+	"github.com/light-bringer/rates-exchanger-service/internal/logging"
+	"github.com/light-bringer/rates-exchanger-service/internal/ratelimit"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	apiKeyMetadata        = "x-api-key"
+	authorizationMetadata = "authorization"
+	bearerPrefix          = "Bearer "
+)
+
+// apiKeyContextKey is the context key of the authenticated API key of a call.
+type apiKeyContextKey struct{}
+
+// authenticateUnary authenticates the unary RPCs; see authenticate.
+func (s *Server) authenticateUnary(
+	ctx context.Context,
+	req any,
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (any, error) {
+	ctx, err := s.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// authenticateStream authenticates the streams; see authenticate.
+func (s *Server) authenticateStream(
+	srv any,
+	stream grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	ctx, err := s.authenticate(stream.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &contextStream{ServerStream: stream, ctx: ctx})
+}
+
+// authenticate requires an API key with the rates:read scope on every call but those of the anonymous
+// methods, matched by their full name like the paths of the HTTP API. The key is sent in the x-api-key
+// metadata or as a Bearer token in the authorization metadata. Calls without a valid key fail with
+// Unauthenticated, and calls whose key lacks the scope with PermissionDenied; both are rate limited like
+// the methods. The returned context carries the key.
+func (s *Server) authenticate(ctx context.Context, method string) (context.Context, error) {
+	if s.keys == nil || s.isAnonymous(method) {
+		return ctx, nil
+	}
+
+	secret := callAPIKey(ctx)
+	if secret == "" {
+		return ctx, s.reject(ctx, method, status.Error(codes.Unauthenticated,
+			"an API key is required, sent as a Bearer token or in the x-api-key metadata"))
+	}
+
+	key, err := s.keys.Authenticate(ctx, secret)
+	if errors.Is(err, apikey.ErrInvalidKey) {
+		return ctx, s.reject(ctx, method, status.Error(codes.Unauthenticated, "the API key is unknown, revoked or expired"))
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to authenticate API key", "error", err)
+		return ctx, status.Error(codes.Internal, "an internal error occurred")
+	}
+
+	ctx = context.WithValue(ctx, apiKeyContextKey{}, key)
+	if !key.HasScope(models.ScopeRatesRead) {
+		return ctx, s.reject(ctx, method, status.Errorf(codes.PermissionDenied,
+			"the API key lacks the %s scope", models.ScopeRatesRead))
+	}
+
+	return ctx, nil
+}
+
+// reject returns the error of a call that failed authentication, unless the call is throttled.
+// Like the answers of the HTTP API, rejected calls are rate limited by the IP address of the client,
+// or by the key if it lacks the scope.
+func (s *Server) reject(ctx context.Context, method string, err error) error {
+	if limitErr := s.takeToken(ctx, method); limitErr != nil {
+		return limitErr
+	}
+	return err
+}
+
+// authenticatedKey returns the API key the call was authenticated with, if any.
+func authenticatedKey(ctx context.Context) (models.APIKey, bool) {
+	key, ok := ctx.Value(apiKeyContextKey{}).(models.APIKey)
+	return key, ok
+}
+
+// isAnonymous reports whether the method is served without an API key.
+func (s *Server) isAnonymous(method string) bool {
+	for _, prefix := range s.anonymous {
+		if ratelimit.MatchesPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// callAPIKey returns the API key of a call, sent in the x-api-key metadata or as a Bearer token,
+// or an empty string if it sent none.
+func callAPIKey(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if keys := md.Get(apiKeyMetadata); len(keys) > 0 && keys[0] != "" {
+		return keys[0]
+	}
+	if values := md.Get(authorizationMetadata); len(values) > 0 && strings.HasPrefix(values[0], bearerPrefix) {
+		return strings.TrimSpace(strings.TrimPrefix(values[0], bearerPrefix))
+	}
+	return ""
+}
@@ -0,0 +1,78 @@
+package rpc
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/light-bringer/rates-exchanger-service/internal/logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// requestIDMetadata carries the request ID of a call, like the X-Request-ID header of the HTTP API.
+const requestIDMetadata = "x-request-id"
+
+// contextStream is a server stream with the given context, e.g. one carrying the request-scoped logger.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// logUnary serves the unary RPCs with a request-scoped logger; see callLogger.
+func logUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	started := time.Now()
+	ctx, id, logger := callLogger(ctx)
+	if err := grpc.SetHeader(ctx, metadata.Pairs(requestIDMetadata, id)); err != nil {
+		logger.Debug("Failed to set request ID header", "error", err)
+	}
+
+	resp, err := handler(ctx, req)
+	logCall(ctx, logger, info.FullMethod, started, err)
+	return resp, err
+}
+
+// logStream serves the streams with a request-scoped logger; see callLogger.
+func logStream(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	started := time.Now()
+	ctx, id, logger := callLogger(stream.Context())
+	if err := stream.SetHeader(metadata.Pairs(requestIDMetadata, id)); err != nil {
+		logger.Debug("Failed to set request ID header", "error", err)
+	}
+
+	err := handler(srv, &contextStream{ServerStream: stream, ctx: ctx})
+	logCall(ctx, logger, info.FullMethod, started, err)
+	return err
+}
+
+// callLogger returns a copy of ctx carrying a logger with the request ID of the call, like the requests
+// of the HTTP API, together with the ID and the logger. A valid client supplied x-request-id metadata
+// is reused; otherwise a new ID is generated. The interceptors return the ID in the x-request-id header.
+func callLogger(ctx context.Context) (context.Context, string, *slog.Logger) {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(requestIDMetadata); len(ids) > 0 {
+			id = ids[0]
+		}
+	}
+	if !logging.IsValidRequestID(id) {
+		id = logging.NewRequestID()
+	}
+
+	logger := slog.Default().With("request_id", id)
+	return logging.NewContext(ctx, logger), id, logger
+}
+
+// logCall writes the access log line of a finished call.
+func logCall(ctx context.Context, logger *slog.Logger, method string, started time.Time, err error) {
+	logger.Info("Call served",
+		"method", method,
+		"code", status.Code(err).String(),
+		"duration", time.Since(started),
+		"client_ip", peerIP(ctx))
+}
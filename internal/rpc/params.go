@@ -0,0 +1,77 @@
+package rpc
+
+import (
+	ratesv1 "github.com/light-bringer/rates-exchanger-service/api/rates/v1"
+	"github.com/light-bringer/rates-exchanger-service/internal/params"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// parseBase validates the base currency of a request.
+// An empty base currency means EUR.
+func parseBase(base string) (string, error) {
+	base, err := params.ParseBase(base)
+	if err != nil {
+		return "", status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return base, nil
+}
+
+// parseSymbols validates the symbols of a request, removing duplicates.
+func parseSymbols(symbols []string) ([]string, error) {
+	symbols, err := params.ParseSymbols(symbols)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return symbols, nil
+}
+
+// parseDate validates a required date field in YYYY-MM-DD format.
+func parseDate(field, date string) (string, error) {
+	if _, err := params.ParseDate(field, date); err != nil {
+		return "", status.Error(codes.InvalidArgument, err.Error())
+	}
+	return date, nil
+}
+
+// parsePeriod validates an optional explicit period. Both dates or neither must be set,
+// and the end date may not be before the start date.
+func parsePeriod(start, end string) (string, string, error) {
+	if start == "" && end == "" {
+		return "", "", nil
+	}
+
+	start, err := parseDate("start_date", start)
+	if err != nil {
+		return "", "", err
+	}
+
+	end, err = parseDate("end_date", end)
+	if err != nil {
+		return "", "", err
+	}
+
+	// Dates in YYYY-MM-DD format order lexically.
+	if end < start {
+		return "", "", status.Errorf(codes.InvalidArgument, "end_date %s is before start_date %s", end, start)
+	}
+
+	return start, end, nil
+}
+
+// parseFallback converts the fallback of a request.
+func parseFallback(fallback ratesv1.Fallback) (models.Fallback, error) {
+	switch fallback {
+	case ratesv1.Fallback_FALLBACK_UNSPECIFIED, ratesv1.Fallback_FALLBACK_PREVIOUS:
+		return models.FallbackPrevious, nil
+	case ratesv1.Fallback_FALLBACK_NEXT:
+		return models.FallbackNext, nil
+	case ratesv1.Fallback_FALLBACK_NONE:
+		return models.FallbackNone, nil
+	default:
+		return "", status.Errorf(codes.InvalidArgument, "invalid fallback %v", fallback)
+	}
+}
@@ -0,0 +1,115 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/light-bringer/rates-exchanger-service/internal/logging"
+	"github.com/light-bringer/rates-exchanger-service/internal/ratelimit"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// rateLimitUnary limits the unary RPCs like the HTTP routes; see takeToken.
+func (s *Server) rateLimitUnary(
+	ctx context.Context,
+	req any,
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (any, error) {
+	if err := s.takeToken(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// rateLimitStream limits the streams like the HTTP routes; opening a stream takes a single token.
+func (s *Server) rateLimitStream(
+	srv any,
+	stream grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	if err := s.takeToken(stream.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, stream)
+}
+
+// takeToken takes a token of the client from the bucket of the route group of the method, matched by
+// its full name, e.g. "/rates.v1.RatesService/GetLatestRates". The response carries the RateLimit
+// headers as metadata, and a ResourceExhausted status with a retry-after header if no token is left.
+// Calls are served unlimited if the store fails, so that an unavailable shared store does not take
+// the API down.
+func (s *Server) takeToken(ctx context.Context, method string) error {
+	if s.limiter == nil {
+		return nil
+	}
+	policy, limited := s.limiter.Policy(method)
+	if !limited {
+		return nil
+	}
+
+	result, err := s.limiter.Take(ctx, policy, rateLimitClient(ctx))
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to take rate limit token, serving unlimited", "error", err)
+		return nil
+	}
+
+	header := rateLimitMetadata(result)
+	if !result.Allowed {
+		retryAfter := max(ceilSeconds(result.RetryAfter), 1)
+		header.Set("retry-after", strconv.Itoa(retryAfter))
+		grpc.SetHeader(ctx, header) //nolint:errcheck // the call is rejected anyway
+		return status.Errorf(codes.ResourceExhausted, "rate limit of %s exceeded, retry in %d seconds",
+			policy.Group, retryAfter)
+	}
+
+	grpc.SetHeader(ctx, header) //nolint:errcheck // headers are informational
+	return nil
+}
+
+// rateLimitClient identifies the client of a call by the ID of its API key, if the key was authenticated,
+// or else by its IP address, like the clients of the HTTP API.
+func rateLimitClient(ctx context.Context) string {
+	if key, ok := authenticatedKey(ctx); ok {
+		return "key:" + strconv.FormatInt(key.ID, 10)
+	}
+	return "ip:" + peerIP(ctx)
+}
+
+// peerIP returns the IP address of the client of a call, or an empty string if it is unknown.
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// rateLimitMetadata returns the RateLimit headers of the HTTP API as gRPC metadata.
+func rateLimitMetadata(result ratelimit.Result) metadata.MD {
+	return metadata.Pairs(
+		"ratelimit-limit", strconv.Itoa(result.Policy.Burst),
+		"ratelimit-remaining", strconv.Itoa(result.Remaining),
+		"ratelimit-reset", strconv.Itoa(ceilSeconds(result.Reset)),
+		"ratelimit-policy", fmt.Sprintf("%d;w=%d;burst=%d",
+			result.Policy.Requests, ceilSeconds(result.Policy.Period), result.Policy.Burst),
+	)
+}
+
+// ceilSeconds returns the duration in whole seconds, rounded up.
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
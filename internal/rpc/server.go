@@ -0,0 +1,338 @@
+// Package rpc serves the rates over gRPC.
+package rpc
+
+import (
+	"context"
+	"net"
+	"sort"
+	"sync"
+
+	ratesv1 "github.com/light-bringer/rates-exchanger-service/api/rates/v1"
+	"github.com/light-bringer/rates-exchanger-service/internal/apikey"
+	"github.com/light-bringer/rates-exchanger-service/internal/logging"
+	"github.com/light-bringer/rates-exchanger-service/internal/params"
+	"github.com/light-bringer/rates-exchanger-service/internal/ratelimit"
+	"github.com/light-bringer/rates-exchanger-service/internal/service"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// Server implements the gRPC RatesService on top of the RatesService.
+type Server struct {
+	ratesv1.UnimplementedRatesServiceServer
+
+	service *service.RatesService
+	// hub notifies the WatchRates streams of new rates.
+	hub *service.VersionHub
+	// limiter limits the calls of each client; they are not limited if it is nil.
+	limiter *ratelimit.Limiter
+	// keys authenticates the API keys of the calls; no key is required if it is nil.
+	keys *apikey.Service
+	// anonymous are the prefixes of the full method names served without an API key.
+	anonymous  []string
+	grpcServer *grpc.Server
+
+	// done is closed on shutdown to end the WatchRates streams, which would otherwise block it.
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewServer returns a new Server with the given RatesService. The WatchRates streams are notified through
+// the given VersionHub, which the rate streams of the HTTP API share.
+// The server registers the reflection service, so that tools such as grpcurl can discover the API.
+func NewServer(ratesService *service.RatesService, hub *service.VersionHub) *Server {
+	s := &Server{
+		service: ratesService,
+		hub:     hub,
+		done:    make(chan struct{}),
+	}
+	s.grpcServer = grpc.NewServer(
+		// Calls are logged first, so that rejected calls are logged too, and keys are checked before
+		// rate limiting, so that authenticated clients are limited by their key.
+		grpc.ChainUnaryInterceptor(logUnary, s.authenticateUnary, s.rateLimitUnary),
+		grpc.ChainStreamInterceptor(logStream, s.authenticateStream, s.rateLimitStream),
+	)
+
+	ratesv1.RegisterRatesServiceServer(s.grpcServer, s)
+	reflection.Register(s.grpcServer)
+
+	return s
+}
+
+// WithRateLimit limits the calls of each client with the given Limiter, like the HTTP routes.
+// The route groups match the full method names, e.g. "/rates.v1.RatesService".
+// It must be called before Serve.
+func (s *Server) WithRateLimit(limiter *ratelimit.Limiter) *Server {
+	s.limiter = limiter
+	return s
+}
+
+// WithAuthentication requires an API key with the rates:read scope, authenticated by the given Service,
+// on every call but those of the methods matching the anonymous prefixes, like the HTTP routes.
+// It must be called before Serve.
+func (s *Server) WithAuthentication(keys *apikey.Service, anonymous []string) *Server {
+	s.keys = keys
+	s.anonymous = anonymous
+	return s
+}
+
+// Serve accepts gRPC connections on the listener until the server is shut down.
+func (s *Server) Serve(listener net.Listener) error {
+	if err := s.grpcServer.Serve(listener); err != nil {
+		return errors.Wrap(err, "failed to serve gRPC")
+	}
+	return nil
+}
+
+// Shutdown ends the open WatchRates streams and stops the server gracefully, waiting for the
+// pending RPCs to complete. If the context is done first, the remaining connections are closed.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.stopOnce.Do(func() { close(s.done) })
+
+	stopped := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.grpcServer.Stop()
+		return errors.Wrap(ctx.Err(), "gRPC server forced to shutdown")
+	}
+}
+
+// GetLatestRates returns the rates of the latest publication day.
+func (s *Server) GetLatestRates(
+	ctx context.Context,
+	req *ratesv1.GetLatestRatesRequest,
+) (*ratesv1.GetLatestRatesResponse, error) {
+	base, err := parseBase(req.GetBase())
+	if err != nil {
+		return nil, err
+	}
+
+	rates, date, err := s.service.FetchLatestExchangeRates(ctx, base)
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	return &ratesv1.GetLatestRatesResponse{Base: base, Date: date, Rates: toRates(limitRates(rates, req.GetLimit()))}, nil
+}
+
+// GetRatesForDate returns the rates of a date, falling back to a nearby publication day.
+func (s *Server) GetRatesForDate(
+	ctx context.Context,
+	req *ratesv1.GetRatesForDateRequest,
+) (*ratesv1.GetRatesForDateResponse, error) {
+	date, err := parseDate("date", req.GetDate())
+	if err != nil {
+		return nil, err
+	}
+
+	base, err := parseBase(req.GetBase())
+	if err != nil {
+		return nil, err
+	}
+
+	fallback, err := parseFallback(req.GetFallback())
+	if err != nil {
+		return nil, err
+	}
+
+	rates, effectiveDate, err := s.service.FetchRatesForDate(ctx, date, base, fallback)
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	return &ratesv1.GetRatesForDateResponse{
+		Base:          base,
+		Date:          effectiveDate,
+		RequestedDate: date,
+		Rates:         toRates(limitRates(rates, req.GetLimit())),
+	}, nil
+}
+
+// GetRateStatistics returns the statistics of the rates over a period.
+func (s *Server) GetRateStatistics(
+	ctx context.Context,
+	req *ratesv1.GetRateStatisticsRequest,
+) (*ratesv1.GetRateStatisticsResponse, error) {
+	base, err := parseBase(req.GetBase())
+	if err != nil {
+		return nil, err
+	}
+
+	symbols, err := parseSymbols(req.GetSymbols())
+	if err != nil {
+		return nil, err
+	}
+
+	start, end, err := parsePeriod(req.GetStartDate(), req.GetEndDate())
+	if err != nil {
+		return nil, err
+	}
+
+	days := req.GetDays()
+	if days == 0 {
+		days = params.DefaultRange
+	}
+
+	stats, err := s.service.GetRateStatistics(ctx, base, symbols, start, end, days)
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	currencies := make([]string, 0, len(stats))
+	for currency := range stats {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+
+	response := &ratesv1.GetRateStatisticsResponse{
+		Base:       base,
+		Statistics: make([]*ratesv1.RateStatistics, 0, len(currencies)),
+	}
+	for _, currency := range currencies {
+		response.Statistics = append(response.Statistics, toStatistics(currency, stats[currency]))
+	}
+
+	return response, nil
+}
+
+// WatchRates streams the latest rates, first as currently stored and then whenever a sync changes them.
+// The stream ends when the client cancels it or the server shuts down.
+func (s *Server) WatchRates(req *ratesv1.WatchRatesRequest, stream ratesv1.RatesService_WatchRatesServer) error {
+	base, err := parseBase(req.GetBase())
+	if err != nil {
+		return err
+	}
+
+	symbols, err := parseSymbols(req.GetSymbols())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	go func() {
+		select {
+		case <-s.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	notify, unsubscribe := s.hub.Subscribe()
+	defer unsubscribe()
+
+	logging.FromContext(ctx).Debug("Watching rates", "base", base, "symbols", symbols)
+
+	// sent is the version of the rates sent last; notifications of the same version are ignored.
+	var sent *models.DataVersion
+	send := func() error {
+		version, fetchErr := s.service.FetchDataVersion(ctx)
+		if fetchErr != nil {
+			return statusError(ctx, fetchErr)
+		}
+		if sent != nil && version.Sequence == sent.Sequence && version.LatestDay == sent.LatestDay {
+			return nil
+		}
+		rates, date, fetchErr := s.service.FetchLatestExchangeRates(ctx, base)
+		if fetchErr != nil {
+			return statusError(ctx, fetchErr)
+		}
+
+		response := &ratesv1.WatchRatesResponse{
+			Base:     base,
+			Date:     date,
+			Sequence: version.Sequence,
+			Rates:    toRates(service.FilterRates(rates, symbols)),
+		}
+		if sendErr := stream.Send(response); sendErr != nil {
+			return errors.Wrap(sendErr, "failed to send rates")
+		}
+		sent = &version
+		return nil
+	}
+
+	if err = send(); err != nil {
+		return err
+	}
+	for ctx.Err() == nil {
+		select {
+		case <-ctx.Done():
+		case <-notify:
+			if err = send(); err != nil {
+				return err
+			}
+		}
+	}
+
+	select {
+	case <-s.done:
+		return status.Error(codes.Unavailable, "server is shutting down")
+	default:
+		return status.FromContextError(stream.Context().Err()).Err()
+	}
+}
+
+// statusError converts an error returned by the RatesService into a gRPC status.
+// Known client errors are reported with their message; any other error is logged
+// with the logger of the call and reported as an opaque internal error.
+func statusError(ctx context.Context, err error) error {
+	switch {
+	case errors.Is(err, service.ErrUnknownCurrency):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, service.ErrRatesNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	default:
+		logging.FromContext(ctx).Error("Internal error", "error", err)
+		return status.Error(codes.Internal, "an internal error occurred")
+	}
+}
+
+// toRates converts rates into their gRPC messages.
+func toRates(rates models.LatestExchangeRates) []*ratesv1.Rate {
+	messages := make([]*ratesv1.Rate, 0, len(rates))
+	for _, rate := range rates {
+		messages = append(messages, &ratesv1.Rate{Currency: rate.Currency, Rate: rate.Rate})
+	}
+	return messages
+}
+
+// limitRates returns the first rates, in the currency order of the service; zero keeps all of them.
+func limitRates(rates models.LatestExchangeRates, limit uint64) models.LatestExchangeRates {
+	if limit > 0 && uint64(len(rates)) > limit {
+		return rates[:limit]
+	}
+	return rates
+}
+
+// toStatistics converts the statistics of a currency into their gRPC message.
+func toStatistics(currency string, stat models.RateStatistic) *ratesv1.RateStatistics {
+	return &ratesv1.RateStatistics{
+		Currency:      currency,
+		Min:           stat.MinRate,
+		Max:           stat.MaxRate,
+		Avg:           stat.AvgRate,
+		Median:        stat.MedianRate,
+		StdDev:        stat.StdDev,
+		Count:         int64(stat.Count),
+		First:         stat.FirstRate,
+		Last:          stat.LastRate,
+		Change:        stat.Change,
+		ChangePercent: stat.ChangePercent,
+		FirstDate:     stat.FirstDate,
+		LastDate:      stat.LastDate,
+		MinDate:       stat.MinDate,
+		MaxDate:       stat.MaxDate,
+	}
+}
@@ -0,0 +1,391 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	ratesv1 "github.com/light-bringer/rates-exchanger-service/api/rates/v1"
+	"github.com/light-bringer/rates-exchanger-service/internal/apikey"
+	"github.com/light-bringer/rates-exchanger-service/internal/logging"
+	"github.com/light-bringer/rates-exchanger-service/internal/ratelimit"
+	"github.com/light-bringer/rates-exchanger-service/internal/service"
+	"github.com/light-bringer/rates-exchanger-service/internal/testdb"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newTestClient serves a Server without a database over an in-memory connection, after applying
+// the given options to it. Only requests rejected before the RatesService is called can be served.
+func newTestClient(t *testing.T, options ...func(*Server)) (ratesv1.RatesServiceClient, *Server) {
+	t.Helper()
+
+	server := NewServer(nil, service.NewVersionHub(context.Background(), nil, time.Second))
+	for _, option := range options {
+		option(server)
+	}
+
+	return serve(t, server), server
+}
+
+// newDatabaseClient serves a Server on a RatesService holding the given EUR based rates, keyed by day
+// and currency, over an in-memory connection. It returns the client and the pool and schema of the
+// rates. The test is skipped if no test database is set; see testdb.New.
+func newDatabaseClient(
+	t *testing.T,
+	rates map[string]map[string]float64,
+) (ratesv1.RatesServiceClient, *pgxpool.Pool, string) {
+	t.Helper()
+	pool, schema := testdb.New(t)
+	testdb.InsertRates(t, pool, schema, rates)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	ratesService := service.NewRatesService(pool, schema)
+
+	return serve(t, NewServer(ratesService, service.NewVersionHub(ctx, ratesService, 10*time.Millisecond))), pool, schema
+}
+
+// serve serves the Server over an in-memory connection until the test ends and returns a client of it.
+func serve(t *testing.T, server *Server) ratesv1.RatesServiceClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1024 * 1024)
+	go server.Serve(listener)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		conn.Close()
+		server.Shutdown(context.Background())
+	})
+
+	return ratesv1.NewRatesServiceClient(conn)
+}
+
+func TestServerRates(t *testing.T) {
+	client, _, _ := newDatabaseClient(t, map[string]map[string]float64{
+		"2024-03-07": {"USD": 1.0, "GBP": 0.8, "JPY": 150},
+		"2024-03-08": {"USD": 1.25, "GBP": 0.85, "JPY": 160},
+	})
+	ctx := context.Background()
+
+	latest, err := client.GetLatestRates(ctx, &ratesv1.GetLatestRatesRequest{Base: "usd", Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, "USD", latest.GetBase())
+	assert.Equal(t, "2024-03-08", latest.GetDate())
+	assertRates(t, map[string]float64{"EUR": 0.8, "GBP": 0.68}, latest.GetRates())
+
+	// A Saturday falls back to the Friday before.
+	forDate, err := client.GetRatesForDate(ctx, &ratesv1.GetRatesForDateRequest{Date: "2024-03-09"})
+	require.NoError(t, err)
+	assert.Equal(t, "2024-03-08", forDate.GetDate())
+	assert.Equal(t, "2024-03-09", forDate.GetRequestedDate())
+	assertRates(t, map[string]float64{"GBP": 0.85, "JPY": 160, "USD": 1.25}, forDate.GetRates())
+
+	_, err = client.GetRatesForDate(ctx, &ratesv1.GetRatesForDateRequest{Date: "2024-01-01"})
+	assert.Equal(t, codes.NotFound, status.Code(err), err)
+
+	_, err = client.GetLatestRates(ctx, &ratesv1.GetLatestRatesRequest{Base: "CHF"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), err)
+}
+
+func TestServerRateStatistics(t *testing.T) {
+	client, _, _ := newDatabaseClient(t, map[string]map[string]float64{
+		"2024-03-06": {"USD": 1.0, "GBP": 0.8},
+		"2024-03-07": {"USD": 1.5, "GBP": 0.9},
+		"2024-03-08": {"USD": 1.25, "GBP": 0.85},
+	})
+
+	response, err := client.GetRateStatistics(context.Background(), &ratesv1.GetRateStatisticsRequest{
+		Symbols:   []string{"USD"},
+		StartDate: "2024-03-06",
+		EndDate:   "2024-03-08",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "EUR", response.GetBase())
+	require.Len(t, response.GetStatistics(), 1)
+	stats := response.GetStatistics()[0]
+	assert.Equal(t, "USD", stats.GetCurrency())
+	assert.Equal(t, int64(3), stats.GetCount())
+	assert.InDelta(t, 1.0, stats.GetMin(), 1e-9)
+	assert.InDelta(t, 1.5, stats.GetMax(), 1e-9)
+	assert.InDelta(t, 1.25, stats.GetAvg(), 1e-9)
+	assert.Equal(t, "2024-03-06", stats.GetFirstDate())
+	assert.Equal(t, "2024-03-08", stats.GetLastDate())
+	assert.Equal(t, "2024-03-07", stats.GetMaxDate())
+}
+
+func TestServerWatchRates(t *testing.T) {
+	client, pool, schema := newDatabaseClient(t, map[string]map[string]float64{
+		"2024-03-07": {"USD": 1.0, "GBP": 0.8},
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream, err := client.WatchRates(ctx, &ratesv1.WatchRatesRequest{Symbols: []string{"USD"}})
+	require.NoError(t, err)
+
+	initial, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "2024-03-07", initial.GetDate())
+	assertRates(t, map[string]float64{"USD": 1.0}, initial.GetRates())
+
+	// A sync stores the rates of a new day and increments the sequence in the same transaction.
+	testdb.InsertRates(t, pool, schema, map[string]map[string]float64{"2024-03-08": {"USD": 1.25, "GBP": 0.85}})
+	_, err = pool.Exec(ctx, "UPDATE "+schema+".sync_state SET sequence = sequence + 1, updated_at = now()")
+	require.NoError(t, err)
+
+	update, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "2024-03-08", update.GetDate())
+	assert.Equal(t, initial.GetSequence()+1, update.GetSequence())
+	assertRates(t, map[string]float64{"USD": 1.25}, update.GetRates())
+}
+
+func TestServerInvalidArguments(t *testing.T) {
+	client, _ := newTestClient(t)
+	ctx := context.Background()
+
+	testCases := []struct {
+		name string
+		call func() error
+	}{
+		{
+			name: "Invalid base",
+			call: func() error {
+				_, err := client.GetLatestRates(ctx, &ratesv1.GetLatestRatesRequest{Base: "EURO"})
+				return err
+			},
+		},
+		{
+			name: "Invalid date",
+			call: func() error {
+				_, err := client.GetRatesForDate(ctx, &ratesv1.GetRatesForDateRequest{Date: "2024-13-01"})
+				return err
+			},
+		},
+		{
+			name: "Invalid fallback",
+			call: func() error {
+				_, err := client.GetRatesForDate(ctx, &ratesv1.GetRatesForDateRequest{Date: "2024-03-01", Fallback: 42})
+				return err
+			},
+		},
+		{
+			name: "Incomplete period",
+			call: func() error {
+				_, err := client.GetRateStatistics(ctx, &ratesv1.GetRateStatisticsRequest{StartDate: "2024-03-01"})
+				return err
+			},
+		},
+		{
+			name: "End before start",
+			call: func() error {
+				_, err := client.GetRateStatistics(ctx, &ratesv1.GetRateStatisticsRequest{
+					StartDate: "2024-03-31",
+					EndDate:   "2024-03-01",
+				})
+				return err
+			},
+		},
+		{
+			name: "Invalid symbol",
+			call: func() error {
+				stream, err := client.WatchRates(ctx, &ratesv1.WatchRatesRequest{Symbols: []string{"usd", "1"}})
+				if err != nil {
+					return err
+				}
+				_, err = stream.Recv()
+				return err
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.call()
+			assert.Equal(t, codes.InvalidArgument, status.Code(err), err)
+		})
+	}
+}
+
+func TestServerRateLimit(t *testing.T) {
+	limiter, err := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), models.RateLimitConfig{
+		Groups: []models.RateLimitGroup{
+			{Name: "rates", Paths: []string{"/rates.v1.RatesService"}, Requests: 2, Period: time.Minute},
+		},
+	})
+	require.NoError(t, err)
+	client, _ := newTestClient(t, func(s *Server) { s.WithRateLimit(limiter) })
+	ctx := context.Background()
+
+	// Invalid requests take a token too, so they are answered without a database.
+	var header metadata.MD
+	_, err = client.GetLatestRates(ctx, &ratesv1.GetLatestRatesRequest{Base: "EURO"}, grpc.Header(&header))
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), err)
+	assert.Equal(t, []string{"2"}, header.Get("ratelimit-limit"))
+	assert.Equal(t, []string{"1"}, header.Get("ratelimit-remaining"))
+	assert.Equal(t, []string{"2;w=60;burst=2"}, header.Get("ratelimit-policy"))
+
+	stream, err := client.WatchRates(ctx, &ratesv1.WatchRatesRequest{Base: "EURO"})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), err)
+
+	_, err = client.GetRatesForDate(ctx, &ratesv1.GetRatesForDateRequest{Date: "2024-13-01"}, grpc.Header(&header))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err), err)
+	assert.Equal(t, []string{"30"}, header.Get("retry-after"))
+	assert.Equal(t, []string{"0"}, header.Get("ratelimit-remaining"))
+}
+
+func TestServerAuthentication(t *testing.T) {
+	ctx := context.Background()
+	store := apikey.NewMemoryStore()
+	keys := apikey.NewService(store)
+	_, reader, err := keys.CreateKey(ctx, "reader", []models.APIKeyScope{models.ScopeRatesRead}, nil)
+	require.NoError(t, err)
+	_, admin, err := keys.CreateKey(ctx, "admin", []models.APIKeyScope{models.ScopeAdmin}, nil)
+	require.NoError(t, err)
+	// Keys without scopes cannot be created by the Service, but may be stored by an older version.
+	_, err = store.CreateKey(ctx, models.APIKey{Name: "none", Hash: apikey.HashSecret("scopeless")})
+	require.NoError(t, err)
+	client, _ := newTestClient(t, func(s *Server) { s.WithAuthentication(keys, []string{"/health"}) })
+
+	testCases := []struct {
+		name     string
+		metadata []string
+		code     codes.Code
+	}{
+		{name: "No key", code: codes.Unauthenticated},
+		{name: "Unknown key", metadata: []string{"x-api-key", "unknown"}, code: codes.Unauthenticated},
+		{name: "Missing scope", metadata: []string{"x-api-key", "scopeless"}, code: codes.PermissionDenied},
+		{name: "Key", metadata: []string{"x-api-key", reader}, code: codes.InvalidArgument},
+		{name: "Admin key", metadata: []string{"x-api-key", admin}, code: codes.InvalidArgument},
+		{name: "Bearer token", metadata: []string{"authorization", "Bearer " + reader}, code: codes.InvalidArgument},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := metadata.AppendToOutgoingContext(ctx, tc.metadata...)
+			_, err := client.GetLatestRates(ctx, &ratesv1.GetLatestRatesRequest{Base: "EURO"})
+			assert.Equal(t, tc.code, status.Code(err), err)
+
+			stream, err := client.WatchRates(ctx, &ratesv1.WatchRatesRequest{Base: "EURO"})
+			require.NoError(t, err)
+			_, err = stream.Recv()
+			assert.Equal(t, tc.code, status.Code(err), err)
+		})
+	}
+}
+
+func TestServerRateLimitByKey(t *testing.T) {
+	ctx := context.Background()
+	keys := apikey.NewService(apikey.NewMemoryStore())
+	_, first, err := keys.CreateKey(ctx, "first", []models.APIKeyScope{models.ScopeRatesRead}, nil)
+	require.NoError(t, err)
+	_, second, err := keys.CreateKey(ctx, "second", []models.APIKeyScope{models.ScopeRatesRead}, nil)
+	require.NoError(t, err)
+	limiter, err := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), models.RateLimitConfig{
+		Groups: []models.RateLimitGroup{
+			{Name: "rates", Paths: []string{"/rates.v1.RatesService"}, Requests: 1, Period: time.Minute},
+		},
+	})
+	require.NoError(t, err)
+	client, _ := newTestClient(t, func(s *Server) {
+		s.WithAuthentication(keys, nil).WithRateLimit(limiter)
+	})
+
+	// The clients share the IP address, but each key has its own tokens.
+	for _, secret := range []string{first, second} {
+		ctx := metadata.AppendToOutgoingContext(ctx, "x-api-key", secret)
+		_, err = client.GetLatestRates(ctx, &ratesv1.GetLatestRatesRequest{Base: "EURO"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), err)
+	}
+
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", first)
+	_, err = client.GetLatestRates(ctx, &ratesv1.GetLatestRatesRequest{Base: "EURO"})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err), err)
+}
+
+func TestServerRequestID(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	testCases := []struct {
+		name      string
+		requestID string
+		reused    bool
+	}{
+		{name: "Client ID", requestID: "client-id_1.2:3", reused: true},
+		{name: "No ID"},
+		{name: "Invalid ID", requestID: strings.Repeat("a", logging.MaxRequestIDLength+1)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tc.requestID != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, "x-request-id", tc.requestID)
+			}
+
+			var header metadata.MD
+			_, err := client.GetLatestRates(ctx, &ratesv1.GetLatestRatesRequest{Base: "EURO"}, grpc.Header(&header))
+			assert.Equal(t, codes.InvalidArgument, status.Code(err), err)
+
+			ids := header.Get("x-request-id")
+			require.Len(t, ids, 1)
+			if tc.reused {
+				assert.Equal(t, tc.requestID, ids[0])
+			} else {
+				assert.Len(t, ids[0], len(logging.NewRequestID()))
+			}
+		})
+	}
+}
+
+func TestStatusError(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := logging.NewContext(context.Background(), slog.New(slog.NewTextHandler(&buf, nil)).With("request_id", "abc"))
+	assert.Equal(t, codes.InvalidArgument, status.Code(statusError(ctx, errors.Wrap(service.ErrUnknownCurrency, "currency XXX"))))
+	assert.Equal(t, codes.NotFound, status.Code(statusError(ctx, errors.Wrap(service.ErrRatesNotFound, "date 2024-03-30"))))
+	assert.Empty(t, buf.String(), "client errors are not logged")
+
+	err := statusError(ctx, errors.New("connection refused"))
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.NotContains(t, err.Error(), "connection refused")
+	assert.Contains(t, buf.String(), "connection refused")
+	assert.Contains(t, buf.String(), "request_id=abc", "internal errors are logged with the logger of the call")
+}
+
+// assertRates checks that rates holds exactly the expected rates, in ascending currency order.
+func assertRates(t *testing.T, expected map[string]float64, rates []*ratesv1.Rate) {
+	t.Helper()
+	require.Len(t, rates, len(expected))
+	for i, rate := range rates {
+		if i > 0 {
+			assert.Less(t, rates[i-1].GetCurrency(), rate.GetCurrency())
+		}
+		require.Contains(t, expected, rate.GetCurrency())
+		assert.InDelta(t, expected[rate.GetCurrency()], rate.GetRate(), 1e-9, rate.GetCurrency())
+	}
+}
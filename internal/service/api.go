@@ -3,63 +3,69 @@ package service
 import (
 	"context"
 	"fmt"
-	"log/slog"
 	"time"
 
 	"github.com/Masterminds/squirrel"
+	"github.com/light-bringer/rates-exchanger-service/internal/logging"
 	"github.com/light-bringer/rates-exchanger-service/models"
 	"github.com/pkg/errors"
 )
 
 // FetchLatestExchangeRates fetches the latest exchange rates.
-// The function returns the exchange rates for the latest day.
-// The rates are sorted in ascending order.
-// The function returns an error if the query fails.
-func (s *RatesService) FetchLatestExchangeRates(limit uint64) (models.LatestExchangeRates, error) {
+// The function returns the exchange rates for the latest day, quoted against the given base currency,
+// together with that day. The day is empty if no rates are stored.
+// The rates are sorted by currency in ascending order, like those of FetchRatesForDate.
+// The function returns an error if the query fails or the base currency is unknown.
+func (s *RatesService) FetchLatestExchangeRates(
+	ctx context.Context,
+	base string,
+) (models.LatestExchangeRates, string, error) {
+	logger := logging.FromContext(ctx)
+	if err := s.checkCurrencyExists(ctx, base); err != nil {
+		return nil, "", err
+	}
+
 	queryBuilder := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
 	subQuery := queryBuilder.Select("MAX(day) AS latest_day").From(s.tableName)
 
 	subQueryStr, _, _ := subQuery.ToSql()
 
-	// Main query: Joins the exchange_rates table with the subquery to fetch rates for the latest day.
-	query := queryBuilder.Select("er.currency", "er.rate").
-		From(s.tableName + " AS er").
+	// Main query: Joins the (rebased) exchange rates with the subquery to fetch rates for the latest day.
+	query := queryBuilder.Select("er.day", "er.currency", "er.rate").
+		FromSelect(s.rebasedRates(base), "er").
 		JoinClause(fmt.Sprintf("INNER JOIN (%s) AS ld ON er.day = ld.latest_day", subQueryStr)).
-		OrderBy("er.rate ASC")
-
-	if limit > 0 {
-		query = query.Limit(limit)
-	}
+		OrderBy("er.currency ASC")
 
 	sqlStr, args, err := query.ToSql()
 	if err != nil {
-		slog.Error("Failed to build SQL query", "error", err)
-		return nil, errors.Wrap(err, "failed to build SQL query")
+		logger.Error("Failed to build SQL query", "error", err)
+		return nil, "", errors.Wrap(err, "failed to build SQL query")
 	}
 
-	conn, err := s.db.Acquire(context.Background())
+	conn, err := s.db.Acquire(ctx)
 	if err != nil {
-		slog.Error("Failed to acquire connection", "error", err)
-		return nil, errors.Wrap(err, "failed to acquire connection")
+		logger.Error("Failed to acquire connection", "error", err)
+		return nil, "", errors.Wrap(err, "failed to acquire connection")
 	}
 
 	defer conn.Release()
 
-	rows, err := conn.Query(context.Background(), sqlStr, args...)
+	rows, err := conn.Query(ctx, sqlStr, args...)
 	if err != nil {
-		slog.Error("Failed to execute query", "error", err)
-		return nil, errors.Wrap(err, "failed to execute query")
+		logger.Error("Failed to execute query", "error", err)
+		return nil, "", errors.Wrap(err, "failed to execute query")
 	}
 	defer rows.Close()
 
 	rates := make(models.LatestExchangeRates, 0)
+	var day time.Time
 
 	// Iterate through the result set.
 	for rows.Next() {
 		var currency string
 		var rate float64
-		if err := rows.Scan(&currency, &rate); err != nil {
-			slog.Error("Failed to scan row", "error", err)
+		if err := rows.Scan(&day, &currency, &rate); err != nil {
+			logger.Error("Failed to scan row", "error", err)
 			continue
 		}
 
@@ -69,59 +75,73 @@ func (s *RatesService) FetchLatestExchangeRates(limit uint64) (models.LatestExch
 		})
 	}
 
-	return rates, nil
+	if len(rates) == 0 {
+		return rates, "", nil
+	}
+
+	return rates, day.Format(dateLayout), nil
 }
 
 // FetchRatesForDate fetches the exchange rates for a given date.
-// The function returns the exchange rates for the given date.
-// The rates are sorted in ascending order.
-// The function returns an error if the query fails.
+// The function returns the exchange rates for the given date, quoted against the given base currency,
+// together with the publication day they were taken from.
+// If no rates were published on the date, the publication day is resolved using the given fallback.
+// The rates are sorted by currency in ascending order.
+// The function returns ErrRatesNotFound if no usable publication day exists,
+// or an error if the query fails or the base currency is unknown.
 func (s *RatesService) FetchRatesForDate(
+	ctx context.Context,
 	date string,
-	limit uint64,
-) (models.LatestExchangeRates, error) {
+	base string,
+	fallback models.Fallback,
+) (models.LatestExchangeRates, string, error) {
+	logger := logging.FromContext(ctx)
 	psql := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
 
-	_, err := time.Parse("2006-01-02", date)
+	requestedDay, err := time.Parse(dateLayout, date)
 	if err != nil {
-		slog.Error("Failed to parse date", "error", err)
-		return nil, errors.Wrap(err, "failed to parse date")
+		logger.Error("Failed to parse date", "error", err)
+		return nil, "", errors.Wrap(err, "failed to parse date")
 	}
 
-	selectBuilder := psql.Select("currency", "rate").From(s.tableName).Where(squirrel.Eq{"day": date}).OrderBy("currency ASC")
-
-	if limit > 0 {
-		selectBuilder = selectBuilder.Limit(limit)
+	if err = s.checkCurrencyExists(ctx, base); err != nil {
+		return nil, "", err
 	}
 
+	selectBuilder := psql.Select("day", "currency", "rate").
+		FromSelect(s.rebasedRates(base), "er").
+		Where(squirrel.Expr("day = (?)", s.resolveDay(requestedDay, base, fallback))).
+		OrderBy("currency ASC")
+
 	sqlStr, args, err := selectBuilder.ToSql()
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to build SQL query")
+		return nil, "", errors.Wrap(err, "failed to build SQL query")
 	}
 
-	conn, err := s.db.Acquire(context.Background())
+	conn, err := s.db.Acquire(ctx)
 	if err != nil {
-		slog.Error("Failed to acquire connection", "error", err)
-		return nil, errors.Wrap(err, "failed to acquire connection")
+		logger.Error("Failed to acquire connection", "error", err)
+		return nil, "", errors.Wrap(err, "failed to acquire connection")
 	}
 
 	defer conn.Release()
 
-	rows, err := conn.Query(context.Background(), sqlStr, args...)
+	rows, err := conn.Query(ctx, sqlStr, args...)
 	if err != nil {
-		slog.Error("Failed to execute query", "error", err)
-		return nil, errors.Wrap(err, "failed to execute query")
+		logger.Error("Failed to execute query", "error", err)
+		return nil, "", errors.Wrap(err, "failed to execute query")
 	}
 	defer rows.Close()
 
 	rates := make(models.LatestExchangeRates, 0)
+	var day time.Time
 
 	// Iterate through the result set.
 	for rows.Next() {
 		var currency string
 		var rate float64
-		if err := rows.Scan(&currency, &rate); err != nil {
-			slog.Error("Failed to scan row", "error", err)
+		if err := rows.Scan(&day, &currency, &rate); err != nil {
+			logger.Error("Failed to scan row", "error", err)
 			continue
 		}
 
@@ -131,74 +151,90 @@ func (s *RatesService) FetchRatesForDate(
 		})
 	}
 
-	return rates, nil
+	if len(rates) == 0 {
+		return nil, "", errors.Wrapf(ErrRatesNotFound, "date %s", date)
+	}
+
+	return rates, day.Format(dateLayout), nil
 }
 
-// GetRateStatistics fetches the rate statistics for the latest day.
-// The statistics include min, max, and average rates for each currency.
-// The statistics are calculated based on the rates for the latest day.
+// GetRateStatistics fetches the rate statistics for a period.
+// The period is either the inclusive range [start, end], or, if start is empty,
+// the given number of days counted back from the latest day.
+// The statistics include min, max, average, median, standard deviation, the first and last rate
+// and their change, and the dates of the min and max, calculated on rates quoted against the given base currency.
+// The rates can optionally be restricted to the given symbols.
 // The function returns a map of currency to rate statistics.
-func (s *RatesService) GetRateStatistics(days uint64) (models.RateStatisticsMap, error) {
-	/**
-	SELECT
-	    currency,
-	    MIN(rate) AS min_rate,
-	    MAX(rate) AS max_rate,
-	    AVG(rate) AS avg_rate
-	FROM
-	    rate_api.exchange_rates
-	WHERE
-	    day = (SELECT MAX(day) FROM rate_api.exchange_rates)
-	GROUP BY
-	    currency
-	ORDER BY
-	    currency;
-		**/
+func (s *RatesService) GetRateStatistics(
+	ctx context.Context,
+	base string,
+	symbols []string,
+	start string,
+	end string,
+	days uint64,
+) (models.RateStatisticsMap, error) {
+	logger := logging.FromContext(ctx)
+	if err := s.checkCurrencyExists(ctx, base); err != nil {
+		return nil, err
+	}
+
 	psql := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
-	subQuery := psql.Select("MAX(day)").From(s.tableName)
-	subQueryStr, _, _ := subQuery.ToSql()
 
-	whereClause := fmt.Sprintf("day <= (%s) AND day >= (%s) - INTERVAL '%d days'", subQueryStr, subQueryStr, days)
+	query := psql.Select("day", "currency", "rate").
+		FromSelect(s.rebasedRates(base), "er").
+		OrderBy("currency ASC", "day ASC")
 
-	query := psql.Select(
-		"currency",
-		"MIN(rate) AS min_rate",
-		"MAX(rate) AS max_rate",
-		"AVG(rate) AS avg_rate",
-	).From("rate_api.exchange_rates").
-		Where(whereClause).
-		GroupBy("currency").
-		OrderBy("currency")
+	if start != "" {
+		query = query.Where(squirrel.GtOrEq{"day": start}).Where(squirrel.LtOrEq{"day": end})
+	} else {
+		subQuery := psql.Select("MAX(day)").From(s.tableName)
+		subQueryStr, _, _ := subQuery.ToSql()
+		query = query.Where(fmt.Sprintf("day <= (%s) AND day >= (%s) - INTERVAL '%d days'", subQueryStr, subQueryStr, days))
+	}
+
+	if len(symbols) > 0 {
+		query = query.Where(squirrel.Eq{"currency": symbols})
+	}
 
 	sqlStr, args, err := query.ToSql()
-	slog.Info("SQL query", "sql", sqlStr, "args", args)
+	logger.Debug("SQL query", "sql", sqlStr, "args", args)
 	if err != nil {
-		slog.Error("Failed to build SQL query", "error", err)
+		logger.Error("Failed to build SQL query", "error", err)
 		return nil, errors.Wrap(err, "failed to build SQL query")
 	}
 
-	conn, err := s.db.Acquire(context.Background())
+	conn, err := s.db.Acquire(ctx)
 	if err != nil {
-		slog.Error("Failed to acquire connection", "error", err)
+		logger.Error("Failed to acquire connection", "error", err)
 		return nil, errors.Wrap(err, "failed to acquire connection")
 	}
 	defer conn.Release()
 
-	rows, err := conn.Query(context.Background(), sqlStr, args...)
+	rows, err := conn.Query(ctx, sqlStr, args...)
 	if err != nil {
-		slog.Error("Failed to execute query", "error", err)
+		logger.Error("Failed to execute query", "error", err)
 		return nil, errors.Wrap(err, "failed to execute query")
 	}
 	defer rows.Close()
 
-	stats := make(models.RateStatisticsMap)
+	series := make(map[string][]ratePoint)
 	for rows.Next() {
-		var stat models.RateStatistic
-		if err := rows.Scan(&stat.Currency, &stat.MinRate, &stat.MaxRate, &stat.AvgRate); err != nil {
-			slog.Error("Failed to read row", "error", err)
+		var currency string
+		var point ratePoint
+		if err := rows.Scan(&point.Day, &currency, &point.Rate); err != nil {
+			logger.Error("Failed to read row", "error", err)
 			continue
 		}
-		stats[stat.Currency] = stat
+		series[currency] = append(series[currency], point)
+	}
+	if err = rows.Err(); err != nil {
+		logger.Error("Failed to read rows", "error", err)
+		return nil, errors.Wrap(err, "failed to read rows")
+	}
+
+	stats := make(models.RateStatisticsMap, len(series))
+	for currency, points := range series {
+		stats[currency] = computeStatistics(currency, points)
 	}
 
 	return stats, nil
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/light-bringer/rates-exchanger-service/internal/logging"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+)
+
+// dayRates holds the rates of the publication day a requested date was resolved to.
+type dayRates struct {
+	day   string
+	rates models.LatestExchangeRates
+}
+
+// FetchRatesForDates fetches the exchange rates for many dates, quoted against the given base currency.
+// Each date is resolved to a publication day using the given fallback, like in FetchRatesForDate,
+// and all dates are read with a single query.
+// The function returns a result per item, in the order of the items. Items with symbols only include those
+// currencies; items without a usable publication day have no date and no rates.
+// The function returns an error if the query fails or the base currency is unknown.
+func (s *RatesService) FetchRatesForDates(
+	ctx context.Context,
+	items []models.BatchRateItem,
+	base string,
+	fallback models.Fallback,
+) ([]models.BatchRateResult, error) {
+	logger := logging.FromContext(ctx)
+	dates := make([]string, 0, len(items))
+	seen := make(map[string]struct{}, len(items))
+	symbols := make([]string, 0)
+	allSymbols := false
+	for _, item := range items {
+		if _, ok := seen[item.Date]; !ok {
+			seen[item.Date] = struct{}{}
+			dates = append(dates, item.Date)
+		}
+		if len(item.Symbols) == 0 {
+			allSymbols = true
+		}
+		symbols = append(symbols, item.Symbols...)
+	}
+
+	var currencies []string
+	if !allSymbols {
+		currencies = symbols
+	}
+
+	sqlStr, args, err := s.batchRatesQuery(dates, base, fallback, currencies).ToSql()
+	if err != nil {
+		logger.Error("Failed to build SQL query", "error", err)
+		return nil, errors.Wrap(err, "failed to build SQL query")
+	}
+
+	rows, err := s.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		logger.Error("Failed to execute query", "error", err)
+		return nil, errors.Wrap(err, "failed to execute query")
+	}
+	defer rows.Close()
+
+	resolved := make(map[string]dayRates)
+	for rows.Next() {
+		var requestedDay time.Time
+		var day *time.Time
+		var currency *string
+		var rate *float64
+		if err := rows.Scan(&requestedDay, &day, &currency, &rate); err != nil {
+			logger.Error("Failed to scan row", "error", err)
+			continue
+		}
+		// The date has no usable publication day.
+		if day == nil {
+			continue
+		}
+
+		date := requestedDay.Format(dateLayout)
+		entry, ok := resolved[date]
+		if !ok {
+			entry = dayRates{day: day.Format(dateLayout), rates: make(models.LatestExchangeRates, 0)}
+		}
+		// The day has no rates of the symbols.
+		if currency != nil && rate != nil {
+			entry.rates = append(entry.rates, models.LatestExchangeRate{Currency: *currency, Rate: *rate})
+		}
+		resolved[date] = entry
+	}
+	if err = rows.Err(); err != nil {
+		logger.Error("Failed to read rows", "error", err)
+		return nil, errors.Wrap(err, "failed to read rows")
+	}
+
+	// An unknown base resolves no day, so its existence is only checked when no day was resolved.
+	if len(resolved) == 0 {
+		if err = s.checkCurrencyExists(ctx, base); err != nil {
+			return nil, err
+		}
+	}
+
+	return batchResults(items, resolved), nil
+}
+
+// batchRatesQuery builds the query reading the rates of the publication days the dates resolve to,
+// restricted to the given currencies unless they are nil.
+// Each date is resolved once by a lateral subquery, and the resolved days are left joined with the rates,
+// so that the resolution does not run per rate row and a day is known even if it has no rates of the currencies.
+// Dates without a usable publication day yield a row without a resolved day.
+func (s *RatesService) batchRatesQuery(
+	dates []string,
+	base string,
+	fallback models.Fallback,
+	currencies []string,
+) squirrel.SelectBuilder {
+	requestedDays := squirrel.Select().
+		Column(squirrel.Alias(squirrel.Expr("unnest(?::date[])", dates), "requested_day"))
+	resolvedDays := squirrel.Select("d.requested_day", "r.resolved_day").
+		FromSelect(requestedDays, "d").
+		JoinClause(squirrel.Expr("CROSS JOIN LATERAL (?) AS r(resolved_day)",
+			s.resolveDays("d.requested_day", base, fallback)))
+
+	rates := squirrel.Select("day", "currency", "rate").FromSelect(s.rebasedRates(base), "r")
+	if currencies != nil {
+		rates = rates.Where(squirrel.Eq{"currency": currencies})
+	}
+
+	return squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).
+		Select("rd.requested_day", "rd.resolved_day", "er.currency", "er.rate").
+		FromSelect(resolvedDays, "rd").
+		JoinClause(squirrel.Expr("LEFT JOIN (?) AS er ON er.day = rd.resolved_day", rates)).
+		OrderBy("rd.requested_day ASC", "er.currency ASC")
+}
+
+// batchResults builds the result of each item from the rates of the publication days the dates were resolved to.
+func batchResults(items []models.BatchRateItem, resolved map[string]dayRates) []models.BatchRateResult {
+	results := make([]models.BatchRateResult, 0, len(items))
+	for _, item := range items {
+		result := models.BatchRateResult{RequestedDate: item.Date, Rates: make(models.LatestExchangeRates, 0)}
+
+		entry, ok := resolved[item.Date]
+		if ok {
+			result.Date = entry.day
+			result.Rates = FilterRates(entry.rates, item.Symbols)
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// FilterRates returns the rates of the given symbols, or all rates if no symbols are given.
+func FilterRates(rates models.LatestExchangeRates, symbols []string) models.LatestExchangeRates {
+	if len(symbols) == 0 {
+		return rates
+	}
+
+	wanted := make(map[string]struct{}, len(symbols))
+	for _, symbol := range symbols {
+		wanted[symbol] = struct{}{}
+	}
+
+	filtered := make(models.LatestExchangeRates, 0, len(symbols))
+	for _, rate := range rates {
+		if _, ok := wanted[rate.Currency]; ok {
+			filtered = append(filtered, rate)
+		}
+	}
+
+	return filtered
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchResults(t *testing.T) {
+	friday := models.LatestExchangeRates{
+		{Currency: "GBP", Rate: 0.8551},
+		{Currency: "JPY", Rate: 162.15},
+		{Currency: "USD", Rate: 1.0811},
+	}
+	resolved := map[string]dayRates{
+		// A Saturday and the Friday before resolve to the same publication day.
+		"2024-03-02": {day: "2024-03-01", rates: friday},
+		"2024-03-01": {day: "2024-03-01", rates: friday},
+	}
+	items := []models.BatchRateItem{
+		{Date: "2024-03-02", Symbols: []string{"USD"}},
+		{Date: "2024-03-01"},
+		{Date: "2023-01-01", Symbols: []string{"USD"}},
+		{Date: "2024-03-02", Symbols: []string{"USD", "JPY", "CHF"}},
+	}
+
+	results := batchResults(items, resolved)
+
+	require.Len(t, results, 4)
+	assert.Equal(t, models.BatchRateResult{
+		RequestedDate: "2024-03-02",
+		Date:          "2024-03-01",
+		Rates:         models.LatestExchangeRates{{Currency: "USD", Rate: 1.0811}},
+	}, results[0])
+	assert.Equal(t, friday, results[1].Rates)
+	assert.Equal(t, models.BatchRateResult{
+		RequestedDate: "2023-01-01",
+		Rates:         models.LatestExchangeRates{},
+	}, results[2])
+	assert.Equal(t, models.LatestExchangeRates{
+		{Currency: "JPY", Rate: 162.15},
+		{Currency: "USD", Rate: 1.0811},
+	}, results[3].Rates)
+}
+
+func TestFilterRates(t *testing.T) {
+	rates := models.LatestExchangeRates{
+		{Currency: "GBP", Rate: 0.8551},
+		{Currency: "JPY", Rate: 162.15},
+		{Currency: "USD", Rate: 1.0811},
+	}
+
+	assert.Equal(t, rates, FilterRates(rates, nil))
+	assert.Equal(t, models.LatestExchangeRates{{Currency: "GBP", Rate: 0.8551}, {Currency: "USD", Rate: 1.0811}},
+		FilterRates(rates, []string{"USD", "GBP", "CHF"}))
+}
+
+func TestBatchRatesQuery(t *testing.T) {
+	s := NewRatesService(nil, "rate_api")
+	dates := []string{"2024-03-29", "2024-03-30"}
+
+	sql, args, err := s.batchRatesQuery(dates, "USD", models.FallbackPrevious, []string{"GBP"}).ToSql()
+	require.NoError(t, err)
+
+	// Each requested date is resolved once, by a lateral subquery, and the rates are left joined on the resolved day.
+	assert.Contains(t, sql, "unnest($1::date[])")
+	assert.Contains(t, sql, "CROSS JOIN LATERAL (SELECT MAX(day) FROM rate_api.exchange_rates")
+	assert.Contains(t, sql, "LEFT JOIN (SELECT day, currency, rate FROM")
+	assert.Contains(t, sql, "AS er ON er.day = rd.resolved_day")
+	assert.Equal(t, 1, strings.Count(sql, "LATERAL"))
+	assert.NotContains(t, sql, "er.day = (")
+	assert.Contains(t, args, dates)
+	assert.Contains(t, args, "GBP")
+
+	sql, _, err = s.batchRatesQuery(dates, "EUR", models.FallbackNext, nil).ToSql()
+	require.NoError(t, err)
+	assert.NotContains(t, sql, "currency IN")
+	assert.Contains(t, sql, "SELECT MIN(day)")
+}
+
+func TestFetchRatesForDates(t *testing.T) {
+	s := newTestService(t, map[string]map[string]float64{
+		"2024-03-07": {"USD": 1.0, "GBP": 0.8},
+		"2024-03-08": {"USD": 1.25, "GBP": 0.85, "JPY": 160},
+	})
+	items := []models.BatchRateItem{
+		// A Saturday resolves to the Friday before.
+		{Date: "2024-03-09", Symbols: []string{"JPY"}},
+		// The day resolves, but has no rate of the symbol.
+		{Date: "2024-03-07", Symbols: []string{"JPY"}},
+		// No publication day within the fallback window.
+		{Date: "2024-01-01", Symbols: []string{"JPY"}},
+	}
+
+	results, err := s.FetchRatesForDates(context.Background(), items, "USD", models.FallbackPrevious)
+	require.NoError(t, err)
+
+	require.Len(t, results, 3)
+	assert.Equal(t, "2024-03-08", results[0].Date)
+	assertRates(t, map[string]float64{"JPY": 128}, results[0].Rates)
+	assert.Equal(t, models.BatchRateResult{
+		RequestedDate: "2024-03-07",
+		Date:          "2024-03-07",
+		Rates:         models.LatestExchangeRates{},
+	}, results[1])
+	assert.Equal(t, models.BatchRateResult{
+		RequestedDate: "2024-01-01",
+		Rates:         models.LatestExchangeRates{},
+	}, results[2])
+
+	results, err = s.FetchRatesForDates(context.Background(),
+		[]models.BatchRateItem{{Date: "2024-03-07"}}, "USD", models.FallbackPrevious)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "2024-03-07", results[0].Date)
+	assertRates(t, map[string]float64{"EUR": 1, "GBP": 0.8}, results[0].Rates)
+
+	_, err = s.FetchRatesForDates(context.Background(), items, "CHF", models.FallbackPrevious)
+	require.ErrorIs(t, err, ErrUnknownCurrency)
+}
@@ -0,0 +1,25 @@
+package service
+
+import "github.com/pkg/errors"
+
+const (
+	// baseCurrency is the currency the ECB publishes its reference rates against.
+	// All rows in exchange_rates are quoted as 1 EUR = rate units of currency.
+	baseCurrency = "EUR"
+	dateLayout   = "2006-01-02"
+	// defaultMinorUnits is the number of decimals conversions into currencies without ISO 4217 minor units,
+	// such as gold, are rounded to.
+	defaultMinorUnits = 2
+	// maxFallbackDays bounds how far a date is moved to find a publication day.
+	// The longest TARGET closure (Good Friday to Easter Monday) spans four days.
+	maxFallbackDays = 7
+	// businessDaysPerYear annualizes daily volatility; TARGET is open about 255 days a year.
+	businessDaysPerYear = 255
+)
+
+var (
+	// ErrUnknownCurrency is returned when a requested currency has no stored rates.
+	ErrUnknownCurrency = errors.New("unknown currency")
+	// ErrRatesNotFound is returned when no rates are stored for a requested date.
+	ErrRatesNotFound = errors.New("rates not found")
+)
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/light-bringer/rates-exchanger-service/internal/logging"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+)
+
+// ConvertAmount converts an amount from one currency to another.
+// The cross rate is triangulated through EUR using the rates published on the given date,
+// or on the latest day if the date is empty. The publication day is resolved using the given fallback.
+// The result is rounded to the minor units of the target currency in the currencies table using the given
+// rounding mode.
+// The function returns ErrRatesNotFound if no rates are stored for the date,
+// and ErrUnknownCurrency if either currency is not quoted on that date.
+func (s *RatesService) ConvertAmount(
+	ctx context.Context,
+	from string,
+	to string,
+	amount float64,
+	date string,
+	fallback models.Fallback,
+	mode models.RoundingMode,
+) (models.Conversion, error) {
+	logger := logging.FromContext(ctx)
+	psql := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+	// The whole day is fetched, so that a missing day can be told apart from a missing currency.
+	selectBuilder := psql.Select("day", "currency", "rate").From(s.tableName)
+
+	if date == "" {
+		selectBuilder = selectBuilder.Where("day = (SELECT MAX(day) FROM " + s.tableName + ")")
+	} else {
+		requestedDay, err := time.Parse(dateLayout, date)
+		if err != nil {
+			logger.Error("Failed to parse date", "error", err)
+			return models.Conversion{}, errors.Wrap(err, "failed to parse date")
+		}
+		selectBuilder = selectBuilder.Where(squirrel.Expr("day = (?)", s.resolveDay(requestedDay, baseCurrency, fallback)))
+	}
+
+	sqlStr, args, err := selectBuilder.ToSql()
+	if err != nil {
+		logger.Error("Failed to build SQL query", "error", err)
+		return models.Conversion{}, errors.Wrap(err, "failed to build SQL query")
+	}
+
+	rows, err := s.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		logger.Error("Failed to execute query", "error", err)
+		return models.Conversion{}, errors.Wrap(err, "failed to execute query")
+	}
+	defer rows.Close()
+
+	// EUR is the implicit base of every stored row.
+	rates := map[string]float64{baseCurrency: 1}
+	var day time.Time
+	for rows.Next() {
+		var currency string
+		var rate float64
+		if err := rows.Scan(&day, &currency, &rate); err != nil {
+			logger.Error("Failed to scan row", "error", err)
+			continue
+		}
+		rates[currency] = rate
+	}
+	if err = rows.Err(); err != nil {
+		logger.Error("Failed to read rows", "error", err)
+		return models.Conversion{}, errors.Wrap(err, "failed to read rows")
+	}
+
+	if day.IsZero() {
+		return models.Conversion{}, errors.Wrapf(ErrRatesNotFound, "date %s", date)
+	}
+
+	for _, currency := range []string{from, to} {
+		if _, ok := rates[currency]; !ok {
+			return models.Conversion{}, errors.Wrapf(ErrUnknownCurrency, "currency %s", currency)
+		}
+	}
+
+	decimals, err := s.fetchMinorUnits(ctx, to)
+	if err != nil {
+		return models.Conversion{}, err
+	}
+
+	rate := rates[to] / rates[from]
+
+	return models.Conversion{
+		From:   from,
+		To:     to,
+		Amount: amount,
+		Result: roundAmount(amount*rate, decimals, mode),
+		Rate:   rate,
+		Date:   day.Format(dateLayout),
+		Rounding: models.Rounding{
+			Mode:     mode,
+			Decimals: decimals,
+		},
+	}, nil
+}
+
+// fetchMinorUnits returns the ISO 4217 minor units of a currency from the currencies table.
+// Currencies missing from the table or without minor units, such as gold, use defaultMinorUnits.
+func (s *RatesService) fetchMinorUnits(ctx context.Context, currency string) (int, error) {
+	logger := logging.FromContext(ctx)
+	sqlStr, args, err := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).
+		Select("minor_units").
+		From(s.currencyTable).
+		Where(squirrel.Eq{"code": currency}).
+		ToSql()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to build SQL query")
+	}
+
+	var units *int
+	err = s.db.QueryRow(ctx, sqlStr, args...).Scan(&units)
+	if errors.Is(err, pgx.ErrNoRows) {
+		logger.Warn("Currency missing from the currencies table, rounding to the default minor units",
+			"currency", currency)
+		return defaultMinorUnits, nil
+	}
+	if err != nil {
+		logger.Error("Failed to fetch minor units", "error", err)
+		return 0, errors.Wrap(err, "failed to fetch minor units")
+	}
+
+	return minorUnitsOrDefault(units), nil
+}
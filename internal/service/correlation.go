@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"math"
+
+	"github.com/light-bringer/rates-exchanger-service/models"
+)
+
+// FetchCorrelation computes the Pearson correlation of the daily log returns of each pair of the given
+// currencies between start and end (inclusive), quoted against the given base currency.
+// The returns of a pair are computed over the publication days on which both currencies have rates,
+// so days where either currency is missing are skipped. The rate of the publication day before start
+// is included, so that the first return falls on start.
+// The function returns ErrUnknownCurrency if the base currency is unknown.
+func (s *RatesService) FetchCorrelation(
+	ctx context.Context,
+	base string,
+	symbols []string,
+	start string,
+	end string,
+) (models.Correlation, error) {
+	if err := s.checkCurrencyExists(ctx, base); err != nil {
+		return models.Correlation{}, err
+	}
+
+	series, err := s.fetchRatePoints(ctx, base, symbols, start, end, 1)
+	if err != nil {
+		return models.Correlation{}, err
+	}
+
+	matrix, observations := computeCorrelation(symbols, series)
+
+	return models.Correlation{
+		Base:         base,
+		StartDate:    start,
+		EndDate:      end,
+		Symbols:      symbols,
+		Matrix:       matrix,
+		Observations: observations,
+	}, nil
+}
+
+// computeCorrelation computes the correlation matrix of the given currencies from their rates sorted by day,
+// together with the number of common returns of each pair.
+func computeCorrelation(symbols []string, series map[string][]ratePoint) ([][]*float64, [][]int) {
+	matrix := make([][]*float64, len(symbols))
+	observations := make([][]int, len(symbols))
+	for i := range symbols {
+		matrix[i] = make([]*float64, len(symbols))
+		observations[i] = make([]int, len(symbols))
+	}
+
+	for i := range symbols {
+		for j := i; j < len(symbols); j++ {
+			x, y := pairReturns(series[symbols[i]], series[symbols[j]])
+			observations[i][j], observations[j][i] = len(x), len(x)
+			if correlation, ok := pearson(x, y); ok {
+				matrix[i][j], matrix[j][i] = &correlation, &correlation
+			}
+		}
+	}
+
+	return matrix, observations
+}
+
+// pairReturns returns the log returns of two rate series sorted by day, computed between
+// consecutive days on which both series have a rate.
+func pairReturns(a, b []ratePoint) ([]float64, []float64) {
+	x, y := make([]float64, 0, len(a)), make([]float64, 0, len(b))
+
+	var previousA, previousB float64
+	common := 0
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i].Day.Before(b[j].Day):
+			i++
+		case b[j].Day.Before(a[i].Day):
+			j++
+		default:
+			if common > 0 && previousA > 0 && previousB > 0 && a[i].Rate > 0 && b[j].Rate > 0 {
+				x = append(x, math.Log(a[i].Rate/previousA))
+				y = append(y, math.Log(b[j].Rate/previousB))
+			}
+			previousA, previousB = a[i].Rate, b[j].Rate
+			common++
+			i++
+			j++
+		}
+	}
+
+	return x, y
+}
+
+// pearson returns the Pearson correlation coefficient of two samples of the same length.
+// The function returns false if there are fewer than two observations or either sample is constant.
+func pearson(x, y []float64) (float64, bool) {
+	if len(x) < 2 || len(x) != len(y) {
+		return 0, false
+	}
+
+	n := float64(len(x))
+	meanX, meanY := 0.0, 0.0
+	for i := range x {
+		meanX += x[i]
+		meanY += y[i]
+	}
+	meanX /= n
+	meanY /= n
+
+	var covariance, varianceX, varianceY float64
+	for i := range x {
+		dx, dy := x[i]-meanX, y[i]-meanY
+		covariance += dx * dy
+		varianceX += dx * dx
+		varianceY += dy * dy
+	}
+	if varianceX == 0 || varianceY == 0 {
+		return 0, false
+	}
+
+	// Rounding can push the coefficient of perfectly correlated samples just outside [-1, 1].
+	return math.Max(-1, math.Min(1, covariance/math.Sqrt(varianceX*varianceY))), true
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPearson(t *testing.T) {
+	testCases := []struct {
+		name        string
+		x           []float64
+		y           []float64
+		expected    float64
+		expectedDef bool
+	}{
+		{name: "Perfect positive", x: []float64{1, 2, 3, 4}, y: []float64{2, 4, 6, 8}, expected: 1, expectedDef: true},
+		{name: "Perfect negative", x: []float64{1, 2, 3, 4}, y: []float64{-1, -2, -3, -4}, expected: -1, expectedDef: true},
+		{name: "Uncorrelated", x: []float64{1, 2, 3, 4}, y: []float64{1, -1, -1, 1}, expected: 0, expectedDef: true},
+		{name: "Partial", x: []float64{1, 2, 3}, y: []float64{1, 3, 2}, expected: 0.5, expectedDef: true},
+		{name: "Constant sample", x: []float64{1, 2, 3}, y: []float64{5, 5, 5}},
+		{name: "Single observation", x: []float64{1}, y: []float64{2}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			correlation, ok := pearson(tc.x, tc.y)
+
+			require.Equal(t, tc.expectedDef, ok)
+			assert.InDelta(t, tc.expected, correlation, 1e-9)
+		})
+	}
+}
+
+func TestPairReturns(t *testing.T) {
+	a := []ratePoint{
+		{Day: day(t, "2024-03-04"), Rate: 1.0},
+		{Day: day(t, "2024-03-05"), Rate: 1.1},
+		{Day: day(t, "2024-03-06"), Rate: 1.2},
+		{Day: day(t, "2024-03-07"), Rate: 1.5},
+	}
+	// b has no rate on 2024-03-05, so both series skip that day.
+	b := []ratePoint{
+		{Day: day(t, "2024-03-04"), Rate: 2.0},
+		{Day: day(t, "2024-03-06"), Rate: 1.0},
+		{Day: day(t, "2024-03-07"), Rate: 4.0},
+		{Day: day(t, "2024-03-08"), Rate: 8.0},
+	}
+
+	x, y := pairReturns(a, b)
+
+	require.Len(t, x, 2)
+	require.Len(t, y, 2)
+	assert.InDelta(t, math.Log(1.2), x[0], 1e-12)
+	assert.InDelta(t, math.Log(1.25), x[1], 1e-12)
+	assert.InDelta(t, math.Log(0.5), y[0], 1e-12)
+	assert.InDelta(t, math.Log(4), y[1], 1e-12)
+}
+
+func TestComputeCorrelation(t *testing.T) {
+	series := map[string][]ratePoint{
+		"USD": {
+			{Day: day(t, "2024-03-04"), Rate: 1.08},
+			{Day: day(t, "2024-03-05"), Rate: 1.09},
+			{Day: day(t, "2024-03-06"), Rate: 1.07},
+			{Day: day(t, "2024-03-07"), Rate: 1.10},
+		},
+		// GBP moves exactly like USD, so their log returns are perfectly correlated.
+		"GBP": {
+			{Day: day(t, "2024-03-04"), Rate: 0.864},
+			{Day: day(t, "2024-03-05"), Rate: 0.872},
+			{Day: day(t, "2024-03-06"), Rate: 0.856},
+			{Day: day(t, "2024-03-07"), Rate: 0.880},
+		},
+	}
+
+	matrix, observations := computeCorrelation([]string{"USD", "GBP", "CHF"}, series)
+
+	require.Len(t, matrix, 3)
+	for i := range 2 {
+		for j := range 2 {
+			require.NotNil(t, matrix[i][j])
+			assert.InDelta(t, 1, *matrix[i][j], 1e-9)
+			assert.Equal(t, 3, observations[i][j])
+		}
+	}
+	for i := range 3 {
+		assert.Nil(t, matrix[i][2])
+		assert.Nil(t, matrix[2][i])
+		assert.Zero(t, observations[2][i])
+	}
+}
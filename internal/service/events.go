@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/light-bringer/rates-exchanger-service/internal/logging"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+)
+
+// FetchSyncEvents fetches the sync events with a sequence greater than the given one,
+// in ascending order of their sequence, up to the given limit.
+func (s *RatesService) FetchSyncEvents(ctx context.Context, after int64, limit uint64) ([]models.SyncEvent, error) {
+	logger := logging.FromContext(ctx)
+	sqlStr, args, err := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).
+		Select("sequence", "latest_day", "currencies", "days").
+		From(s.eventsTable).
+		Where(squirrel.Gt{"sequence": after}).
+		OrderBy("sequence ASC").
+		Limit(limit).
+		ToSql()
+	if err != nil {
+		logger.Error("Failed to build SQL query", "error", err)
+		return nil, errors.Wrap(err, "failed to build SQL query")
+	}
+
+	rows, err := s.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		logger.Error("Failed to execute query", "error", err)
+		return nil, errors.Wrap(err, "failed to execute query")
+	}
+	defer rows.Close()
+
+	events := make([]models.SyncEvent, 0)
+	for rows.Next() {
+		var event models.SyncEvent
+		var latestDay time.Time
+		var days []time.Time
+		if err = rows.Scan(&event.Sequence, &latestDay, &event.Currencies, &days); err != nil {
+			logger.Error("Failed to scan row", "error", err)
+			return nil, errors.Wrap(err, "failed to scan row")
+		}
+
+		event.Date = latestDay.Format(dateLayout)
+		event.Days = make([]string, 0, len(days))
+		for _, day := range days {
+			event.Days = append(event.Days, day.Format(dateLayout))
+		}
+		events = append(events, event)
+	}
+	if err = rows.Err(); err != nil {
+		logger.Error("Failed to read rows", "error", err)
+		return nil, errors.Wrap(err, "failed to read rows")
+	}
+
+	return events, nil
+}
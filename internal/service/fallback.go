@@ -0,0 +1,67 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/light-bringer/rates-exchanger-service/models"
+)
+
+// resolveDay returns a subquery yielding the publication day used for the given date.
+// With FallbackPrevious (the default) the closest publication day on or before the date is used,
+// with FallbackNext the closest one on or after it, and with FallbackNone only the date itself.
+// Days on which the base currency was not published are skipped.
+// The subquery yields NULL if no publication day lies within maxFallbackDays of the date.
+// It uses question placeholders so that it can be nested into other queries.
+func (s *RatesService) resolveDay(date time.Time, base string, fallback models.Fallback) squirrel.SelectBuilder {
+	selectBuilder := squirrel.Select().From(s.tableName)
+
+	switch fallback {
+	case models.FallbackNone:
+		selectBuilder = selectBuilder.Column("MAX(day)").
+			Where(squirrel.Eq{"day": date.Format(dateLayout)})
+	case models.FallbackNext:
+		selectBuilder = selectBuilder.Column("MIN(day)").
+			Where(squirrel.GtOrEq{"day": date.Format(dateLayout)}).
+			Where(squirrel.LtOrEq{"day": date.AddDate(0, 0, maxFallbackDays).Format(dateLayout)})
+	case models.FallbackPrevious:
+		fallthrough
+	default:
+		selectBuilder = selectBuilder.Column("MAX(day)").
+			Where(squirrel.LtOrEq{"day": date.Format(dateLayout)}).
+			Where(squirrel.GtOrEq{"day": date.AddDate(0, 0, -maxFallbackDays).Format(dateLayout)})
+	}
+
+	if base != "" && base != baseCurrency {
+		selectBuilder = selectBuilder.Where(squirrel.Eq{"currency": base})
+	}
+
+	return selectBuilder
+}
+
+// resolveDays is like resolveDay, but resolves the date held by the given column of an outer query,
+// so that many dates can be resolved in a single query.
+func (s *RatesService) resolveDays(column string, base string, fallback models.Fallback) squirrel.SelectBuilder {
+	selectBuilder := squirrel.Select().From(s.tableName)
+
+	switch fallback {
+	case models.FallbackNone:
+		selectBuilder = selectBuilder.Column("MAX(day)").
+			Where("day = " + column)
+	case models.FallbackNext:
+		selectBuilder = selectBuilder.Column("MIN(day)").
+			Where(fmt.Sprintf("day BETWEEN %[1]s AND %[1]s + %[2]d", column, maxFallbackDays))
+	case models.FallbackPrevious:
+		fallthrough
+	default:
+		selectBuilder = selectBuilder.Column("MAX(day)").
+			Where(fmt.Sprintf("day BETWEEN %[1]s - %[2]d AND %[1]s", column, maxFallbackDays))
+	}
+
+	if base != "" && base != baseCurrency {
+		selectBuilder = selectBuilder.Where(squirrel.Eq{"currency": base})
+	}
+
+	return selectBuilder
+}
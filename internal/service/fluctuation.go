@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/light-bringer/rates-exchanger-service/internal/logging"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+)
+
+// FetchFluctuation compares the exchange rates of two dates.
+// Both dates are resolved to the closest publication day on or before them, the same way
+// FetchRatesForDate does by default, and the rates of both days are read with a single query.
+// The rates are quoted against the given base currency and optionally restricted to the given symbols.
+// Currencies that are not quoted on both days are omitted, so the rates are empty if no symbol is.
+// The function returns ErrRatesNotFound if either date cannot be resolved to a publication day.
+func (s *RatesService) FetchFluctuation(
+	ctx context.Context,
+	start string,
+	end string,
+	base string,
+	symbols []string,
+) (models.Fluctuations, error) {
+	logger := logging.FromContext(ctx)
+	startDay, err := time.Parse(dateLayout, start)
+	if err != nil {
+		logger.Error("Failed to parse date", "error", err)
+		return models.Fluctuations{}, errors.Wrap(err, "failed to parse start date")
+	}
+
+	endDay, err := time.Parse(dateLayout, end)
+	if err != nil {
+		logger.Error("Failed to parse date", "error", err)
+		return models.Fluctuations{}, errors.Wrap(err, "failed to parse end date")
+	}
+
+	if err = s.checkCurrencyExists(ctx, base); err != nil {
+		return models.Fluctuations{}, err
+	}
+
+	psql := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+	resolvedDays := squirrel.Select().
+		Column(squirrel.Alias(s.resolveDay(startDay, base, models.FallbackPrevious), "start_day")).
+		Column(squirrel.Alias(s.resolveDay(endDay, base, models.FallbackPrevious), "end_day"))
+
+	rates := squirrel.Select("day", "currency", "rate").FromSelect(s.rebasedRates(base), "r")
+	if len(symbols) > 0 {
+		rates = rates.Where(squirrel.Eq{"currency": symbols})
+	}
+
+	// The resolved days are selected once and left joined with the rates, so that they are known
+	// even if no rates match the symbols, and two dates resolving to the same publication day
+	// can be told apart from a date that does not resolve.
+	selectBuilder := psql.Select("d.start_day", "d.end_day", "er.day", "er.currency", "er.rate").
+		FromSelect(resolvedDays, "d").
+		JoinClause(squirrel.Expr("LEFT JOIN (?) AS er ON er.day IN (d.start_day, d.end_day)", rates))
+
+	sqlStr, args, err := selectBuilder.ToSql()
+	if err != nil {
+		logger.Error("Failed to build SQL query", "error", err)
+		return models.Fluctuations{}, errors.Wrap(err, "failed to build SQL query")
+	}
+
+	rows, err := s.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		logger.Error("Failed to execute query", "error", err)
+		return models.Fluctuations{}, errors.Wrap(err, "failed to execute query")
+	}
+	defer rows.Close()
+
+	ratesByDay := make(map[time.Time]map[string]float64)
+	var resolvedStartDay, resolvedEndDay *time.Time
+	for rows.Next() {
+		var day *time.Time
+		var currency *string
+		var rate *float64
+		if err := rows.Scan(&resolvedStartDay, &resolvedEndDay, &day, &currency, &rate); err != nil {
+			logger.Error("Failed to scan row", "error", err)
+			continue
+		}
+		// The single row of days without matching rates has no rate.
+		if day == nil || currency == nil || rate == nil {
+			continue
+		}
+		if _, ok := ratesByDay[*day]; !ok {
+			ratesByDay[*day] = make(map[string]float64)
+		}
+		ratesByDay[*day][*currency] = *rate
+	}
+	if err = rows.Err(); err != nil {
+		logger.Error("Failed to read rows", "error", err)
+		return models.Fluctuations{}, errors.Wrap(err, "failed to read rows")
+	}
+
+	if resolvedStartDay == nil || resolvedEndDay == nil {
+		return models.Fluctuations{}, errors.Wrap(ErrRatesNotFound, "no publication day for start or end date")
+	}
+
+	return buildFluctuations(*resolvedStartDay, *resolvedEndDay, ratesByDay), nil
+}
+
+// buildFluctuations compares the rates of the start day against the rates of the end day.
+// Currencies that are not quoted on both days are omitted.
+func buildFluctuations(
+	startDay time.Time,
+	endDay time.Time,
+	ratesByDay map[time.Time]map[string]float64,
+) models.Fluctuations {
+	result := models.Fluctuations{
+		StartDate: startDay.Format(dateLayout),
+		EndDate:   endDay.Format(dateLayout),
+		Rates:     make(map[string]models.Fluctuation),
+	}
+
+	for currency, startRate := range ratesByDay[startDay] {
+		endRate, ok := ratesByDay[endDay][currency]
+		if !ok {
+			continue
+		}
+
+		fluctuation := models.Fluctuation{
+			StartRate: startRate,
+			EndRate:   endRate,
+			Change:    endRate - startRate,
+		}
+		if startRate != 0 {
+			fluctuation.ChangePercent = fluctuation.Change / startRate * models.Percent
+		}
+		result.Rates[currency] = fluctuation
+	}
+
+	return result
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildFluctuations(t *testing.T) {
+	start, end := day(t, "2024-03-01"), day(t, "2024-03-28")
+	ratesByDay := map[time.Time]map[string]float64{
+		start: {"USD": 1.0833, "GBP": 0.8551, "JPY": 162.15},
+		end:   {"USD": 1.0811, "GBP": 0.8551},
+	}
+
+	result := buildFluctuations(start, end, ratesByDay)
+
+	assert.Equal(t, "2024-03-01", result.StartDate)
+	assert.Equal(t, "2024-03-28", result.EndDate)
+	assert.Len(t, result.Rates, 2)
+	assert.InDelta(t, -0.0022, result.Rates["USD"].Change, 1e-9)
+	assert.InDelta(t, -0.203083172, result.Rates["USD"].ChangePercent, 1e-9)
+	assert.InDelta(t, 0, result.Rates["GBP"].Change, 1e-9)
+	assert.NotContains(t, result.Rates, "JPY")
+}
+
+func TestBuildFluctuationsSameDay(t *testing.T) {
+	friday := day(t, "2024-03-29")
+	result := buildFluctuations(friday, friday, map[time.Time]map[string]float64{friday: {"USD": 1.079}})
+
+	assert.InDelta(t, 1.079, result.Rates["USD"].StartRate, 1e-9)
+	assert.InDelta(t, 1.079, result.Rates["USD"].EndRate, 1e-9)
+}
+
+func TestFetchFluctuationWithoutMatchingSymbols(t *testing.T) {
+	s := newTestService(t, map[string]map[string]float64{
+		"2024-03-01": {"USD": 1.0833, "GBP": 0.8551},
+		"2024-03-28": {"USD": 1.0811, "GBP": 0.8551},
+	})
+
+	// 2024-03-30 is a Saturday and resolves to the Thursday before Good Friday.
+	result, err := s.FetchFluctuation(context.Background(), "2024-03-01", "2024-03-30", "EUR", []string{"CHF"})
+	require.NoError(t, err)
+	assert.Equal(t, "2024-03-01", result.StartDate)
+	assert.Equal(t, "2024-03-28", result.EndDate)
+	assert.Empty(t, result.Rates)
+
+	result, err = s.FetchFluctuation(context.Background(), "2024-03-01", "2024-03-28", "USD", []string{"GBP"})
+	require.NoError(t, err)
+	assert.InDelta(t, 0.8551/1.0833, result.Rates["GBP"].StartRate, 1e-9)
+
+	_, err = s.FetchFluctuation(context.Background(), "2024-02-01", "2024-03-28", "EUR", nil)
+	require.ErrorIs(t, err, ErrRatesNotFound)
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// VersionHub shares a single watch of the data version among all open rate streams, of both the HTTP
+// and the gRPC API, so that the stored rates are polled once per interval whatever the number of streams.
+// The watch is started with the first subscriber and runs until the context of the hub is done.
+type VersionHub struct {
+	service  *RatesService
+	ctx      context.Context
+	interval time.Duration
+
+	mu          sync.Mutex
+	started     bool
+	subscribers map[chan struct{}]struct{}
+}
+
+// NewVersionHub returns a new VersionHub polling the data version of the service at the given interval
+// until the context is done.
+func NewVersionHub(ctx context.Context, service *RatesService, interval time.Duration) *VersionHub {
+	return &VersionHub{
+		service:     service,
+		ctx:         ctx,
+		interval:    interval,
+		subscribers: make(map[chan struct{}]struct{}),
+	}
+}
+
+// Subscribe registers a stream for change notifications. Notifications are coalesced,
+// so a slow stream receives at most one pending notification. A notification means the version may have
+// changed since the stream subscribed; the stream checks what changed since it last sent rates.
+// The returned function unregisters the stream.
+func (h *VersionHub) Subscribe() (<-chan struct{}, func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.started {
+		h.started = true
+		go h.watch()
+	}
+
+	notify := make(chan struct{}, 1)
+	h.subscribers[notify] = struct{}{}
+
+	return notify, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(h.subscribers, notify)
+	}
+}
+
+// watch notifies the subscribers of every version the watch receives, including the first one.
+// A stream may already have sent that version, or a later one, when it is notified, so the streams compare
+// the current version with the one they sent rather than relying on the notifications alone.
+func (h *VersionHub) watch() {
+	for range h.service.WatchDataVersion(h.ctx, h.interval) {
+		h.notify()
+	}
+}
+
+// notify notifies all subscribers without blocking.
+func (h *VersionHub) notify() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for notify := range h.subscribers {
+		select {
+		case notify <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/light-bringer/rates-exchanger-service/internal/testdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionHubNotify(t *testing.T) {
+	hub := NewVersionHub(context.Background(), nil, 0)
+	// Mark the watch as started, so that subscribing does not poll the missing database.
+	hub.started = true
+
+	first, unsubscribeFirst := hub.Subscribe()
+	second, unsubscribeSecond := hub.Subscribe()
+	unsubscribeSecond()
+
+	hub.notify()
+	hub.notify()
+
+	assert.Len(t, first, 1, "notifications are coalesced")
+	assert.Empty(t, second)
+
+	unsubscribeFirst()
+	assert.Empty(t, hub.subscribers)
+}
+
+func TestVersionHubWatch(t *testing.T) {
+	pool, schema := testdb.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	hub := NewVersionHub(ctx, NewRatesService(pool, schema), 10*time.Millisecond)
+
+	notify, unsubscribe := hub.Subscribe()
+	defer unsubscribe()
+
+	// The first version is notified too, since the stream cannot know whether it sent it.
+	receive := func() {
+		t.Helper()
+		select {
+		case <-notify:
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "no notification")
+		}
+	}
+	receive()
+
+	_, err := pool.Exec(ctx, "UPDATE "+schema+".sync_state SET sequence = sequence + 1, updated_at = now()")
+	require.NoError(t, err)
+	receive()
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+)
+
+// FetchIndicator computes a technical indicator of a currency quoted against the given base currency,
+// for every publication day between start and end (inclusive).
+// The rates of the publication days before start are included in the windows, so that the series
+// starts on start if enough rates are stored; days without a full window are omitted.
+// The function returns ErrUnknownCurrency if no rates are stored for the currency or the base currency.
+func (s *RatesService) FetchIndicator(
+	ctx context.Context,
+	currency string,
+	base string,
+	start string,
+	end string,
+	params models.IndicatorParams,
+) (models.Indicator, error) {
+	for _, code := range []string{base, currency} {
+		if err := s.checkCurrencyExists(ctx, code); err != nil {
+			return models.Indicator{}, err
+		}
+	}
+
+	startDay, err := time.Parse(dateLayout, start)
+	if err != nil {
+		return models.Indicator{}, errors.Wrap(err, "failed to parse start date")
+	}
+
+	series, err := s.fetchRatePoints(ctx, base, []string{currency}, start, end, params.Window-1)
+	if err != nil {
+		return models.Indicator{}, err
+	}
+
+	indicator := models.Indicator{
+		Currency: currency,
+		Base:     base,
+		Type:     params.Type,
+		Window:   params.Window,
+		Series:   computeIndicator(series[currency], startDay, params),
+	}
+	if params.Type == models.IndicatorBollinger {
+		indicator.K = params.K
+	}
+
+	return indicator, nil
+}
+
+// computeIndicator computes an indicator over rates sorted by day.
+// A point is returned for every day on or after start that ends a full window.
+// The exponential moving average is seeded with the simple moving average of the first window.
+func computeIndicator(points []ratePoint, start time.Time, params models.IndicatorParams) []models.IndicatorPoint {
+	result := make([]models.IndicatorPoint, 0, len(points))
+	if params.Window < 1 || len(points) < params.Window {
+		return result
+	}
+
+	alpha := 2 / float64(params.Window+1)
+	var ema float64
+	for i := params.Window - 1; i < len(points); i++ {
+		window := points[i-params.Window+1 : i+1]
+		point := models.IndicatorPoint{Date: points[i].Day.Format(dateLayout), Rate: points[i].Rate}
+
+		switch params.Type {
+		case models.IndicatorSMA:
+			point.Value = meanRate(window)
+		case models.IndicatorEMA:
+			if i == params.Window-1 {
+				ema = meanRate(window)
+			} else {
+				ema = alpha*points[i].Rate + (1-alpha)*ema
+			}
+			point.Value = ema
+		case models.IndicatorMin:
+			point.Value = window[0].Rate
+			for _, p := range window {
+				point.Value = math.Min(point.Value, p.Rate)
+			}
+		case models.IndicatorMax:
+			point.Value = window[0].Rate
+			for _, p := range window {
+				point.Value = math.Max(point.Value, p.Rate)
+			}
+		case models.IndicatorBollinger:
+			mean := meanRate(window)
+			// Bollinger bands use the population standard deviation of the window.
+			variance := 0.0
+			for _, p := range window {
+				variance += (p.Rate - mean) * (p.Rate - mean)
+			}
+			width := params.K * math.Sqrt(variance/float64(len(window)))
+			upper, lower := mean+width, mean-width
+			point.Value, point.Upper, point.Lower = mean, &upper, &lower
+		}
+
+		if !points[i].Day.Before(start) {
+			result = append(result, point)
+		}
+	}
+
+	return result
+}
+
+// meanRate returns the average rate of the given points.
+func meanRate(points []ratePoint) float64 {
+	sum := 0.0
+	for _, point := range points {
+		sum += point.Rate
+	}
+	return sum / float64(len(points))
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeIndicator(t *testing.T) {
+	points := []ratePoint{
+		{Day: day(t, "2024-03-04"), Rate: 1},
+		{Day: day(t, "2024-03-05"), Rate: 2},
+		{Day: day(t, "2024-03-06"), Rate: 3},
+		{Day: day(t, "2024-03-07"), Rate: 5},
+		{Day: day(t, "2024-03-08"), Rate: 4},
+	}
+
+	testCases := []struct {
+		name           string
+		indicator      models.IndicatorType
+		start          string
+		expectedDates  []string
+		expectedValues []float64
+	}{
+		{
+			name:           "Simple moving average",
+			indicator:      models.IndicatorSMA,
+			start:          "2024-03-04",
+			expectedDates:  []string{"2024-03-06", "2024-03-07", "2024-03-08"},
+			expectedValues: []float64{2, 10.0 / 3, 4},
+		},
+		{
+			name:           "Exponential moving average",
+			indicator:      models.IndicatorEMA,
+			start:          "2024-03-04",
+			expectedDates:  []string{"2024-03-06", "2024-03-07", "2024-03-08"},
+			expectedValues: []float64{2, 3.5, 3.75},
+		},
+		{
+			name:           "Rolling minimum",
+			indicator:      models.IndicatorMin,
+			start:          "2024-03-04",
+			expectedDates:  []string{"2024-03-06", "2024-03-07", "2024-03-08"},
+			expectedValues: []float64{1, 2, 3},
+		},
+		{
+			name:           "Rolling maximum",
+			indicator:      models.IndicatorMax,
+			start:          "2024-03-04",
+			expectedDates:  []string{"2024-03-06", "2024-03-07", "2024-03-08"},
+			expectedValues: []float64{3, 5, 5},
+		},
+		{
+			name:           "Lookback before start",
+			indicator:      models.IndicatorEMA,
+			start:          "2024-03-07",
+			expectedDates:  []string{"2024-03-07", "2024-03-08"},
+			expectedValues: []float64{3.5, 3.75},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			params := models.IndicatorParams{Type: tc.indicator, Window: 3}
+			result := computeIndicator(points, day(t, tc.start), params)
+
+			require.Len(t, result, len(tc.expectedDates))
+			for i, point := range result {
+				assert.Equal(t, tc.expectedDates[i], point.Date)
+				assert.InDelta(t, tc.expectedValues[i], point.Value, 1e-9)
+				assert.Nil(t, point.Upper)
+				assert.Nil(t, point.Lower)
+			}
+		})
+	}
+}
+
+func TestComputeIndicatorBollinger(t *testing.T) {
+	points := []ratePoint{
+		{Day: day(t, "2024-03-04"), Rate: 1},
+		{Day: day(t, "2024-03-05"), Rate: 2},
+		{Day: day(t, "2024-03-06"), Rate: 3},
+	}
+
+	params := models.IndicatorParams{Type: models.IndicatorBollinger, Window: 3, K: 2}
+	result := computeIndicator(points, day(t, "2024-03-04"), params)
+
+	require.Len(t, result, 1)
+	assert.InDelta(t, 3, result[0].Rate, 0)
+	assert.InDelta(t, 2, result[0].Value, 1e-9)
+	require.NotNil(t, result[0].Upper)
+	require.NotNil(t, result[0].Lower)
+	assert.InDelta(t, 3.632993162, *result[0].Upper, 1e-9)
+	assert.InDelta(t, 0.367006838, *result[0].Lower, 1e-9)
+}
+
+func TestComputeIndicatorShortSeries(t *testing.T) {
+	points := []ratePoint{{Day: day(t, "2024-03-04"), Rate: 1}}
+
+	result := computeIndicator(points, day(t, "2024-03-04"), models.IndicatorParams{Type: models.IndicatorSMA, Window: 20})
+
+	assert.NotNil(t, result)
+	assert.Empty(t, result)
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/light-bringer/rates-exchanger-service/internal/logging"
+	"github.com/pkg/errors"
+)
+
+// rebasedRates returns a query yielding (day, currency, rate) rows quoted against the given base currency.
+// The stored rows are EUR based, so for any other base the cross rate is computed as rate / base_rate
+// for the same day. EUR itself is added to the output as 1 / base_rate, and the base currency is excluded.
+// Days on which the base currency was not published are dropped.
+func (s *RatesService) rebasedRates(base string) squirrel.SelectBuilder {
+	psql := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+	if base == "" || base == baseCurrency {
+		return psql.Select("day", "currency", "rate").From(s.tableName)
+	}
+
+	// withBase adds an implicit EUR row with rate 1 for every published day.
+	withBase := fmt.Sprintf(
+		"(SELECT day, currency, rate FROM %[1]s UNION ALL SELECT DISTINCT day, '%[2]s', 1 FROM %[1]s)",
+		s.tableName,
+		baseCurrency,
+	)
+
+	return psql.Select("r.day", "r.currency", "r.rate / b.rate AS rate").
+		From(withBase+" AS r").
+		Join(withBase+" AS b ON b.day = r.day AND b.currency = ?", base).
+		Where("r.currency <> b.currency")
+}
+
+// checkCurrencyExists checks that rates are stored for the given currency.
+// The function returns ErrUnknownCurrency if no rates are stored for it.
+func (s *RatesService) checkCurrencyExists(ctx context.Context, currency string) error {
+	logger := logging.FromContext(ctx)
+	if currency == "" || currency == baseCurrency {
+		return nil
+	}
+
+	sqlStr, args, err := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).
+		Select("1").
+		From(s.tableName).
+		Where(squirrel.Eq{"currency": currency}).
+		Limit(1).
+		Prefix("SELECT EXISTS (").
+		Suffix(")").
+		ToSql()
+	if err != nil {
+		logger.Error("Failed to build SQL query", "error", err)
+		return errors.Wrap(err, "failed to build SQL query")
+	}
+
+	var exists bool
+	if err = s.db.QueryRow(ctx, sqlStr, args...).Scan(&exists); err != nil {
+		logger.Error("Failed to execute query", "error", err)
+		return errors.Wrap(err, "failed to execute query")
+	}
+
+	if !exists {
+		return errors.Wrapf(ErrUnknownCurrency, "currency %s", currency)
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/light-bringer/rates-exchanger-service/internal/testdb"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestService returns a RatesService on a fresh schema holding the given EUR based rates,
+// keyed by day and currency. The test is skipped if no test database is set; see testdb.New.
+func newTestService(t *testing.T, rates map[string]map[string]float64) *RatesService {
+	t.Helper()
+	pool, schema := testdb.New(t)
+	testdb.InsertRates(t, pool, schema, rates)
+	return NewRatesService(pool, schema)
+}
+
+func TestRebasedRatesWithoutBase(t *testing.T) {
+	s := NewRatesService(nil, "rate_api")
+
+	for _, base := range []string{"", baseCurrency} {
+		sqlStr, args, err := s.rebasedRates(base).ToSql()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT day, currency, rate FROM rate_api.exchange_rates", sqlStr)
+		assert.Empty(t, args)
+	}
+
+	sqlStr, args, err := s.rebasedRates("USD").ToSql()
+	require.NoError(t, err)
+	assert.True(t, strings.Contains(sqlStr, "UNION ALL"), sqlStr)
+	assert.Equal(t, []interface{}{"USD"}, args)
+}
+
+func TestFetchRebasedRates(t *testing.T) {
+	s := newTestService(t, map[string]map[string]float64{
+		"2024-03-07": {"USD": 1.0, "GBP": 0.8, "JPY": 150},
+		"2024-03-08": {"USD": 1.25, "GBP": 0.85, "JPY": 160},
+	})
+	ctx := context.Background()
+
+	rates, day, err := s.FetchLatestExchangeRates(ctx, "USD")
+	require.NoError(t, err)
+	assert.Equal(t, "2024-03-08", day)
+	// The cross rates are rate / 1.25; EUR is added as 1 / 1.25 and USD itself is left out.
+	assertRates(t, map[string]float64{"EUR": 0.8, "GBP": 0.68, "JPY": 128}, rates)
+
+	rates, day, err = s.FetchRatesForDate(ctx, "2024-03-07", "GBP", models.FallbackPrevious)
+	require.NoError(t, err)
+	assert.Equal(t, "2024-03-07", day)
+	assertRates(t, map[string]float64{"EUR": 1.25, "USD": 1.25, "JPY": 187.5}, rates)
+}
+
+func TestFetchRatesWithEURBase(t *testing.T) {
+	s := newTestService(t, map[string]map[string]float64{
+		"2024-03-08": {"USD": 1.25, "GBP": 0.85},
+	})
+
+	for _, base := range []string{"", baseCurrency} {
+		rates, _, err := s.FetchLatestExchangeRates(context.Background(), base)
+		require.NoError(t, err)
+		// The stored rates are returned as they are, without an EUR row.
+		assertRates(t, map[string]float64{"GBP": 0.85, "USD": 1.25}, rates)
+	}
+}
+
+func TestFetchRatesWithUnknownBase(t *testing.T) {
+	s := newTestService(t, map[string]map[string]float64{
+		"2024-03-08": {"USD": 1.25},
+	})
+
+	_, _, err := s.FetchLatestExchangeRates(context.Background(), "CHF")
+	require.ErrorIs(t, err, ErrUnknownCurrency)
+
+	_, _, err = s.FetchRatesForDate(context.Background(), "2024-03-08", "CHF", models.FallbackPrevious)
+	require.ErrorIs(t, err, ErrUnknownCurrency)
+}
+
+// assertRates checks that rates holds exactly the expected rates, in ascending currency order.
+func assertRates(t *testing.T, expected map[string]float64, rates models.LatestExchangeRates) {
+	t.Helper()
+	require.Len(t, rates, len(expected))
+	for i, rate := range rates {
+		if i > 0 {
+			assert.Less(t, rates[i-1].Currency, rate.Currency)
+		}
+		require.Contains(t, expected, rate.Currency)
+		assert.InDelta(t, expected[rate.Currency], rate.Rate, 1e-9, rate.Currency)
+	}
+}
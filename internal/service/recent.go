@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/light-bringer/rates-exchanger-service/internal/logging"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+)
+
+// FetchRecentRates fetches the EUR based exchange rates of the given number of latest publication days.
+// The function returns an empty time series if no rates are stored.
+func (s *RatesService) FetchRecentRates(ctx context.Context, days uint64) (models.TimeSeries, error) {
+	logger := logging.FromContext(ctx)
+	sqlStr, args, err := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).
+		Select("day", "currency", "rate").
+		From(s.tableName).
+		Where(fmt.Sprintf("day IN (SELECT DISTINCT day FROM %s ORDER BY day DESC LIMIT %d)", s.tableName, days)).
+		OrderBy("day ASC", "currency ASC").
+		ToSql()
+	if err != nil {
+		logger.Error("Failed to build SQL query", "error", err)
+		return nil, errors.Wrap(err, "failed to build SQL query")
+	}
+
+	rows, err := s.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		logger.Error("Failed to execute query", "error", err)
+		return nil, errors.Wrap(err, "failed to execute query")
+	}
+	defer rows.Close()
+
+	series := make(models.TimeSeries)
+	for rows.Next() {
+		var day time.Time
+		var currency string
+		var rate float64
+		if err = rows.Scan(&day, &currency, &rate); err != nil {
+			logger.Error("Failed to scan row", "error", err)
+			return nil, errors.Wrap(err, "failed to scan row")
+		}
+
+		date := day.Format(dateLayout)
+		if _, ok := series[date]; !ok {
+			series[date] = make(map[string]float64)
+		}
+		series[date][currency] = rate
+	}
+	if err = rows.Err(); err != nil {
+		logger.Error("Failed to read rows", "error", err)
+		return nil, errors.Wrap(err, "failed to read rows")
+	}
+
+	return series, nil
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+
+	"github.com/light-bringer/rates-exchanger-service/models"
+)
+
+const decimalBase = 10
+
+// minorUnitsOrDefault returns the given ISO 4217 minor units, or defaultMinorUnits if the currency has none.
+func minorUnitsOrDefault(units *int) int {
+	if units == nil {
+		return defaultMinorUnits
+	}
+	return *units
+}
+
+// roundAmount rounds value to the given number of decimals using the given rounding mode.
+// The value is rounded on its shortest decimal representation, so that e.g. 2.675 is treated
+// as exactly 2.675 rather than the nearest binary float 2.67499999...
+func roundAmount(value float64, decimals int, mode models.RoundingMode) float64 {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return value
+	}
+
+	exact, ok := new(big.Rat).SetString(strconv.FormatFloat(value, 'g', -1, 64))
+	if !ok {
+		return value
+	}
+
+	scale := new(big.Int).Exp(big.NewInt(decimalBase), big.NewInt(int64(decimals)), nil)
+	scaled := new(big.Rat).Mul(exact, new(big.Rat).SetInt(scale))
+
+	// Split into the integer part (truncated towards zero) and the absolute fractional remainder.
+	quotient, remainder := new(big.Int).QuoRem(scaled.Num(), scaled.Denom(), new(big.Int))
+	remainder.Abs(remainder)
+
+	// Compare twice the remainder against the denominator to decide the direction.
+	cmp := new(big.Int).Lsh(remainder, 1).Cmp(scaled.Denom())
+	roundAway := cmp > 0 || (cmp == 0 && (mode == models.RoundHalfUp || quotient.Bit(0) == 1))
+	if roundAway {
+		if scaled.Sign() < 0 {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+
+	result, _ := new(big.Rat).SetFrac(quotient, scale).Float64()
+	return result
+}
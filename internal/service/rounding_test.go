@@ -0,0 +1,41 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoundAmount(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    float64
+		decimals int
+		mode     models.RoundingMode
+		expected float64
+	}{
+		{name: "half even rounds tie down to even", value: 2.125, decimals: 2, mode: models.RoundHalfEven, expected: 2.12},
+		{name: "half even rounds tie up to even", value: 2.135, decimals: 2, mode: models.RoundHalfEven, expected: 2.14},
+		{name: "half up rounds tie up", value: 2.125, decimals: 2, mode: models.RoundHalfUp, expected: 2.13},
+		{name: "binary representation is ignored", value: 2.675, decimals: 2, mode: models.RoundHalfUp, expected: 2.68},
+		{name: "non tie rounds to nearest", value: 2.126, decimals: 2, mode: models.RoundHalfEven, expected: 2.13},
+		{name: "zero decimals", value: 150.5, decimals: 0, mode: models.RoundHalfEven, expected: 150},
+		{name: "zero decimals half up", value: 150.5, decimals: 0, mode: models.RoundHalfUp, expected: 151},
+		{name: "negative half up rounds away from zero", value: -2.125, decimals: 2, mode: models.RoundHalfUp, expected: -2.13},
+		{name: "negative half even", value: -2.125, decimals: 2, mode: models.RoundHalfEven, expected: -2.12},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.InDelta(t, tc.expected, roundAmount(tc.value, tc.decimals, tc.mode), 1e-9)
+		})
+	}
+}
+
+func TestMinorUnitsOrDefault(t *testing.T) {
+	zero, three := 0, 3
+	assert.Equal(t, 0, minorUnitsOrDefault(&zero))
+	assert.Equal(t, 3, minorUnitsOrDefault(&three))
+	assert.Equal(t, defaultMinorUnits, minorUnitsOrDefault(nil))
+}
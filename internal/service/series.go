@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/light-bringer/rates-exchanger-service/internal/logging"
+	"github.com/pkg/errors"
+)
+
+// fetchRatePoints fetches the rates of each currency between start and end (inclusive), sorted by day and
+// quoted against the given base currency. The rates can optionally be restricted to the given symbols.
+// With a positive lookback, the rates of that many publication days before start are fetched as well,
+// so that rolling computations have a full window on start.
+// The function returns a map of currency to rate points.
+func (s *RatesService) fetchRatePoints(
+	ctx context.Context,
+	base string,
+	symbols []string,
+	start string,
+	end string,
+	lookback int,
+) (map[string][]ratePoint, error) {
+	logger := logging.FromContext(ctx)
+	query := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).
+		Select("day", "currency", "rate").
+		FromSelect(s.rebasedRates(base), "er").
+		Where(squirrel.LtOrEq{"day": end}).
+		OrderBy("currency ASC", "day ASC")
+
+	if lookback > 0 {
+		// The subqueries use question placeholders so that they can be nested.
+		previousDays := squirrel.Select("DISTINCT day").
+			From(s.tableName).
+			Where(squirrel.Lt{"day": start}).
+			OrderBy("day DESC").
+			Limit(uint64(lookback))
+		firstDay := squirrel.Select("MIN(day)").FromSelect(previousDays, "w")
+		query = query.Where(squirrel.Expr("day >= COALESCE((?), ?::date)", firstDay, start))
+	} else {
+		query = query.Where(squirrel.GtOrEq{"day": start})
+	}
+
+	if len(symbols) > 0 {
+		query = query.Where(squirrel.Eq{"currency": symbols})
+	}
+
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		logger.Error("Failed to build SQL query", "error", err)
+		return nil, errors.Wrap(err, "failed to build SQL query")
+	}
+
+	rows, err := s.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		logger.Error("Failed to execute query", "error", err)
+		return nil, errors.Wrap(err, "failed to execute query")
+	}
+	defer rows.Close()
+
+	series := make(map[string][]ratePoint)
+	for rows.Next() {
+		var currency string
+		var point ratePoint
+		if err := rows.Scan(&point.Day, &currency, &point.Rate); err != nil {
+			logger.Error("Failed to scan row", "error", err)
+			continue
+		}
+		series[currency] = append(series[currency], point)
+	}
+	if err = rows.Err(); err != nil {
+		logger.Error("Failed to read rows", "error", err)
+		return nil, errors.Wrap(err, "failed to read rows")
+	}
+
+	return series, nil
+}
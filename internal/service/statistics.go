@@ -0,0 +1,84 @@
+package service
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/light-bringer/rates-exchanger-service/models"
+)
+
+// ratePoint is a single rate of a currency on a publication day.
+type ratePoint struct {
+	Day  time.Time
+	Rate float64
+}
+
+// computeStatistics calculates the statistics of a currency from its rates sorted by day.
+// The standard deviation is the sample standard deviation; it is zero for fewer than two observations.
+func computeStatistics(currency string, points []ratePoint) models.RateStatistic {
+	stat := models.RateStatistic{
+		Currency: currency,
+		Count:    len(points),
+	}
+	if len(points) == 0 {
+		return stat
+	}
+
+	first, last := points[0], points[len(points)-1]
+	stat.FirstRate, stat.FirstDate = first.Rate, first.Day.Format(dateLayout)
+	stat.LastRate, stat.LastDate = last.Rate, last.Day.Format(dateLayout)
+	stat.MinRate, stat.MinDate = first.Rate, stat.FirstDate
+	stat.MaxRate, stat.MaxDate = first.Rate, stat.FirstDate
+
+	rates := make([]float64, 0, len(points))
+	sum := 0.0
+	for _, point := range points {
+		if point.Rate < stat.MinRate {
+			stat.MinRate, stat.MinDate = point.Rate, point.Day.Format(dateLayout)
+		}
+		if point.Rate > stat.MaxRate {
+			stat.MaxRate, stat.MaxDate = point.Rate, point.Day.Format(dateLayout)
+		}
+		sum += point.Rate
+		rates = append(rates, point.Rate)
+	}
+
+	stat.AvgRate = sum / float64(len(rates))
+	stat.MedianRate = median(rates)
+	stat.StdDev = sampleStdDev(rates, stat.AvgRate)
+
+	stat.Change = stat.LastRate - stat.FirstRate
+	if stat.FirstRate != 0 {
+		stat.ChangePercent = stat.Change / stat.FirstRate * models.Percent
+	}
+
+	return stat
+}
+
+// median returns the median of the values. The values are sorted in place.
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sort.Float64s(values)
+	mid := len(values) / 2 //nolint:gomnd // halving
+	if len(values)%2 == 1 {
+		return values[mid]
+	}
+	return (values[mid-1] + values[mid]) / 2 //nolint:gomnd // mean of the two middle values
+}
+
+// sampleStdDev returns the sample standard deviation of the values around the given mean.
+func sampleStdDev(values []float64, mean float64) float64 {
+	if len(values) < 2 { //nolint:gomnd // a single value has no deviation
+		return 0
+	}
+
+	sumSquares := 0.0
+	for _, value := range values {
+		sumSquares += (value - mean) * (value - mean)
+	}
+	return math.Sqrt(sumSquares / float64(len(values)-1))
+}
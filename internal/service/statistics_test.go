@@ -0,0 +1,62 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func day(t *testing.T, date string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse(dateLayout, date)
+	if err != nil {
+		t.Fatalf("invalid date %q: %v", date, err)
+	}
+	return parsed
+}
+
+func TestComputeStatistics(t *testing.T) {
+	t.Run("multiple observations", func(t *testing.T) {
+		points := []ratePoint{
+			{Day: day(t, "2024-03-01"), Rate: 1.10},
+			{Day: day(t, "2024-03-04"), Rate: 1.08},
+			{Day: day(t, "2024-03-05"), Rate: 1.12},
+			{Day: day(t, "2024-03-06"), Rate: 1.14},
+		}
+
+		stat := computeStatistics("USD", points)
+
+		assert.Equal(t, "USD", stat.Currency)
+		assert.Equal(t, 4, stat.Count)
+		assert.InDelta(t, 1.08, stat.MinRate, 1e-9)
+		assert.Equal(t, "2024-03-04", stat.MinDate)
+		assert.InDelta(t, 1.14, stat.MaxRate, 1e-9)
+		assert.Equal(t, "2024-03-06", stat.MaxDate)
+		assert.InDelta(t, 1.11, stat.AvgRate, 1e-9)
+		assert.InDelta(t, 1.11, stat.MedianRate, 1e-9)
+		assert.InDelta(t, 0.025819889, stat.StdDev, 1e-9)
+		assert.InDelta(t, 1.10, stat.FirstRate, 1e-9)
+		assert.InDelta(t, 1.14, stat.LastRate, 1e-9)
+		assert.Equal(t, "2024-03-01", stat.FirstDate)
+		assert.Equal(t, "2024-03-06", stat.LastDate)
+		assert.InDelta(t, 0.04, stat.Change, 1e-9)
+		assert.InDelta(t, 3.636363636, stat.ChangePercent, 1e-9)
+	})
+
+	t.Run("single observation", func(t *testing.T) {
+		stat := computeStatistics("JPY", []ratePoint{{Day: day(t, "2024-03-01"), Rate: 162.5}})
+
+		assert.Equal(t, 1, stat.Count)
+		assert.InDelta(t, 162.5, stat.MedianRate, 1e-9)
+		assert.InDelta(t, 0, stat.StdDev, 1e-9)
+		assert.InDelta(t, 0, stat.Change, 1e-9)
+	})
+
+	t.Run("no observations", func(t *testing.T) {
+		stat := computeStatistics("GBP", nil)
+
+		assert.Equal(t, 0, stat.Count)
+		assert.Empty(t, stat.FirstDate)
+	})
+}
@@ -8,13 +8,22 @@ type RatesService struct {
 	db        *pgxpool.Pool
 	schema    string
 	tableName string
+	// stateTable holds the sync sequence that versions the exchange rates.
+	stateTable string
+	// eventsTable records the changes of each sync, so that streams can be resumed.
+	eventsTable string
+	// currencyTable holds the ISO 4217 currencies, whose minor units conversions are rounded to.
+	currencyTable string
 }
 
 // NewRatesService returns a new instance of RatesService.
 func NewRatesService(db *pgxpool.Pool, schema string) *RatesService {
 	return &RatesService{
-		db:        db,
-		schema:    schema,
-		tableName: schema + ".exchange_rates",
+		db:            db,
+		schema:        schema,
+		tableName:     schema + ".exchange_rates",
+		stateTable:    schema + ".sync_state",
+		eventsTable:   schema + ".sync_events",
+		currencyTable: schema + ".currencies",
 	}
 }
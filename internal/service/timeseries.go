@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/light-bringer/rates-exchanger-service/internal/logging"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+)
+
+// FetchTimeSeries fetches the exchange rates for every publication day between start and end (inclusive).
+// The rates are quoted against the given base currency and optionally restricted to the given symbols.
+// All days are read with a single range query.
+// The function returns an error if the query fails or the base currency is unknown.
+func (s *RatesService) FetchTimeSeries(
+	ctx context.Context,
+	start string,
+	end string,
+	base string,
+	symbols []string,
+) (models.TimeSeries, error) {
+	logger := logging.FromContext(ctx)
+	if err := s.checkCurrencyExists(ctx, base); err != nil {
+		return nil, err
+	}
+
+	psql := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+	selectBuilder := psql.Select("day", "currency", "rate").
+		FromSelect(s.rebasedRates(base), "er").
+		Where(squirrel.GtOrEq{"day": start}).
+		Where(squirrel.LtOrEq{"day": end}).
+		OrderBy("day ASC", "currency ASC")
+
+	if len(symbols) > 0 {
+		selectBuilder = selectBuilder.Where(squirrel.Eq{"currency": symbols})
+	}
+
+	sqlStr, args, err := selectBuilder.ToSql()
+	if err != nil {
+		logger.Error("Failed to build SQL query", "error", err)
+		return nil, errors.Wrap(err, "failed to build SQL query")
+	}
+
+	rows, err := s.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		logger.Error("Failed to execute query", "error", err)
+		return nil, errors.Wrap(err, "failed to execute query")
+	}
+	defer rows.Close()
+
+	series := make(models.TimeSeries)
+	for rows.Next() {
+		var day time.Time
+		var currency string
+		var rate float64
+		if err := rows.Scan(&day, &currency, &rate); err != nil {
+			logger.Error("Failed to scan row", "error", err)
+			continue
+		}
+
+		date := day.Format(dateLayout)
+		if _, ok := series[date]; !ok {
+			series[date] = make(map[string]float64)
+		}
+		series[date][currency] = rate
+	}
+	if err = rows.Err(); err != nil {
+		logger.Error("Failed to read rows", "error", err)
+		return nil, errors.Wrap(err, "failed to read rows")
+	}
+
+	return series, nil
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/light-bringer/rates-exchanger-service/internal/logging"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+)
+
+// FetchDataVersion fetches the version of the stored exchange rates, made of the latest day and
+// the sync sequence, with a single lightweight query.
+// If the sync state has not been recorded yet, the sequence is zero and the modification time is
+// derived from the latest day.
+func (s *RatesService) FetchDataVersion(ctx context.Context) (models.DataVersion, error) {
+	logger := logging.FromContext(ctx)
+	sqlStr, args, err := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).
+		Select("(SELECT MAX(day) FROM "+s.tableName+")", "st.sequence", "st.updated_at").
+		From("(SELECT 1) AS one").
+		LeftJoin(s.stateTable + " AS st ON st.id").
+		ToSql()
+	if err != nil {
+		logger.Error("Failed to build SQL query", "error", err)
+		return models.DataVersion{}, errors.Wrap(err, "failed to build SQL query")
+	}
+
+	var latestDay, updatedAt *time.Time
+	var sequence *int64
+	if err = s.db.QueryRow(ctx, sqlStr, args...).Scan(&latestDay, &sequence, &updatedAt); err != nil {
+		logger.Error("Failed to execute query", "error", err)
+		return models.DataVersion{}, errors.Wrap(err, "failed to execute query")
+	}
+
+	var version models.DataVersion
+	if latestDay != nil {
+		version.LatestDay = latestDay.Format(dateLayout)
+		version.ModifiedAt = latestDay.UTC()
+	}
+	if sequence != nil {
+		version.Sequence = *sequence
+	}
+	if updatedAt != nil {
+		version.ModifiedAt = updatedAt.UTC()
+	}
+
+	return version, nil
+}
+
+// WatchDataVersion polls the data version at the given interval until the context is done.
+// The returned channel receives the current version first, and then every version that differs
+// from the previous one, so that every instance of the API sees the syncs of all instances.
+// Failed polls are logged and retried at the next interval. The channel is closed when the context is done.
+func (s *RatesService) WatchDataVersion(ctx context.Context, interval time.Duration) <-chan models.DataVersion {
+	versions := make(chan models.DataVersion)
+
+	go func() {
+		defer close(versions)
+
+		var last models.DataVersion
+		first := true
+		poll := func() {
+			version, err := s.FetchDataVersion(ctx)
+			if err != nil {
+				return
+			}
+			if !first && version.Sequence == last.Sequence && version.LatestDay == last.LatestDay {
+				return
+			}
+			select {
+			case versions <- version:
+				first = false
+				last = version
+			case <-ctx.Done():
+			}
+		}
+
+		poll()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				poll()
+			}
+		}
+	}()
+
+	return versions
+}
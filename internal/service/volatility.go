@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+)
+
+// FetchVolatility computes the annualized historical volatility of the daily log returns of each currency
+// between start and end (inclusive), quoted against the given base currency and optionally restricted to
+// the given symbols. The volatility is the sample standard deviation of the returns scaled by the square root
+// of businessDaysPerYear, computed once over the whole period and as a rolling series over windows of the
+// given number of returns. The rates of the publication days before start are included, so that the period
+// and the rolling series start on start.
+// The function returns ErrUnknownCurrency if the base currency is unknown.
+func (s *RatesService) FetchVolatility(
+	ctx context.Context,
+	base string,
+	symbols []string,
+	start string,
+	end string,
+	window int,
+) (models.Volatility, error) {
+	if err := s.checkCurrencyExists(ctx, base); err != nil {
+		return models.Volatility{}, err
+	}
+
+	startDay, err := time.Parse(dateLayout, start)
+	if err != nil {
+		return models.Volatility{}, errors.Wrap(err, "failed to parse start date")
+	}
+
+	series, err := s.fetchRatePoints(ctx, base, symbols, start, end, window)
+	if err != nil {
+		return models.Volatility{}, err
+	}
+
+	volatility := models.Volatility{
+		Base:              base,
+		StartDate:         start,
+		EndDate:           end,
+		Window:            window,
+		AnnualizationDays: businessDaysPerYear,
+		Currencies:        make(map[string]models.CurrencyVolatility, len(series)),
+	}
+	for currency, points := range series {
+		volatility.Currencies[currency] = computeVolatility(points, startDay, window)
+	}
+
+	return volatility, nil
+}
+
+// logReturn is the log return of a currency from the previous publication day to Day.
+type logReturn struct {
+	Day    time.Time
+	Return float64
+}
+
+// computeVolatility computes the volatility of rates sorted by day over the returns on or after start,
+// and the rolling volatility on every day on or after start that ends a full window of returns.
+func computeVolatility(points []ratePoint, start time.Time, window int) models.CurrencyVolatility {
+	returns := make([]logReturn, 0, len(points))
+	for i := 1; i < len(points); i++ {
+		if points[i-1].Rate > 0 && points[i].Rate > 0 {
+			returns = append(returns, logReturn{Day: points[i].Day, Return: math.Log(points[i].Rate / points[i-1].Rate)})
+		}
+	}
+
+	result := models.CurrencyVolatility{Series: make([]models.VolatilityPoint, 0, len(returns))}
+
+	period := make([]float64, 0, len(returns))
+	for i, r := range returns {
+		if r.Day.Before(start) {
+			continue
+		}
+		period = append(period, r.Return)
+
+		if i+1 >= window {
+			values := make([]float64, 0, window)
+			for _, windowReturn := range returns[i+1-window : i+1] {
+				values = append(values, windowReturn.Return)
+			}
+			result.Series = append(result.Series, models.VolatilityPoint{
+				Date:       r.Day.Format(dateLayout),
+				Volatility: annualizedVolatility(values),
+			})
+		}
+	}
+
+	result.Observations = len(period)
+	if len(period) >= 2 {
+		volatility := annualizedVolatility(period)
+		result.Volatility = &volatility
+	}
+
+	return result
+}
+
+// annualizedVolatility returns the sample standard deviation of daily returns, annualized over
+// businessDaysPerYear. It is zero for fewer than two returns.
+func annualizedVolatility(returns []float64) float64 {
+	if len(returns) == 0 {
+		return 0
+	}
+
+	mean := 0.0
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= float64(len(returns))
+
+	return sampleStdDev(returns, mean) * math.Sqrt(businessDaysPerYear)
+}
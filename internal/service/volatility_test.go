@@ -0,0 +1,56 @@
+package service
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeVolatility(t *testing.T) {
+	// The daily log returns are 0.01, 0.02, -0.03 and 0.02.
+	points := []ratePoint{
+		{Day: day(t, "2024-03-04"), Rate: 1},
+		{Day: day(t, "2024-03-05"), Rate: math.Exp(0.01)},
+		{Day: day(t, "2024-03-06"), Rate: math.Exp(0.03)},
+		{Day: day(t, "2024-03-07"), Rate: math.Exp(0)},
+		{Day: day(t, "2024-03-08"), Rate: math.Exp(0.02)},
+	}
+	annualize := math.Sqrt(businessDaysPerYear)
+	// The sample standard deviation of two returns is their distance divided by the square root of two.
+	pair := func(a, b float64) float64 { return math.Abs(a-b) / math.Sqrt2 * annualize }
+
+	t.Run("Whole series", func(t *testing.T) {
+		result := computeVolatility(points, day(t, "2024-03-04"), 2)
+
+		assert.Equal(t, 4, result.Observations)
+		require.NotNil(t, result.Volatility)
+		assert.InDelta(t, math.Sqrt(0.0017/3)*annualize, *result.Volatility, 1e-9)
+
+		require.Len(t, result.Series, 3)
+		assert.Equal(t, "2024-03-06", result.Series[0].Date)
+		assert.InDelta(t, pair(0.01, 0.02), result.Series[0].Volatility, 1e-9)
+		assert.InDelta(t, pair(0.02, -0.03), result.Series[1].Volatility, 1e-9)
+		assert.Equal(t, "2024-03-08", result.Series[2].Date)
+		assert.InDelta(t, pair(-0.03, 0.02), result.Series[2].Volatility, 1e-9)
+	})
+
+	t.Run("Lookback before start", func(t *testing.T) {
+		result := computeVolatility(points, day(t, "2024-03-06"), 2)
+
+		assert.Equal(t, 3, result.Observations)
+		require.Len(t, result.Series, 3)
+		assert.Equal(t, "2024-03-06", result.Series[0].Date)
+		assert.InDelta(t, pair(0.01, 0.02), result.Series[0].Volatility, 1e-9)
+	})
+
+	t.Run("Too few returns", func(t *testing.T) {
+		result := computeVolatility(points[:2], day(t, "2024-03-04"), 2)
+
+		assert.Equal(t, 1, result.Observations)
+		assert.Nil(t, result.Volatility)
+		assert.NotNil(t, result.Series)
+		assert.Empty(t, result.Series)
+	})
+}
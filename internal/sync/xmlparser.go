@@ -3,10 +3,13 @@ package sync
 import (
 	"context"
 	"encoding/xml"
+	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"sort"
 	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/Masterminds/squirrel"
@@ -17,46 +20,82 @@ import (
 )
 
 type ExchangeRateSync struct {
-	httpClient *http.Client
-	url        string
-	schema     string
-	tableName  string
-	db         *pgxpool.Pool
+	httpClient  *http.Client
+	url         string
+	schema      string
+	tableName   string
+	stateTable  string
+	eventsTable string
+	// currenciesTable holds the ISO 4217 currencies; rates of other codes are rejected.
+	currenciesTable string
+	db              *pgxpool.Pool
+	listeners       []SyncListener
+	observers       []RunObserver
+	// status is the outcome of the syncs run so far, read by the readiness checks.
+	status atomic.Pointer[models.SyncStatus]
 }
 
+// SyncListener is notified with the sync event of every sync that inserted or changed rates,
+// once its transaction is committed.
+type SyncListener func(event models.SyncEvent)
+
+// RunObserver is notified of every finished sync and cleanup run, whether it succeeded or not.
+type RunObserver func(run models.JobRun)
+
 func NewExchangeRateSync(schemaName, url string, db *pgxpool.Pool) *ExchangeRateSync {
 	if schemaName == "" {
 		schemaName = "public"
 	}
 	return &ExchangeRateSync{
-		httpClient: http.DefaultClient,
-		url:        url,
-		db:         db,
-		schema:     schemaName,
-		tableName:  schemaName + ".exchange_rates",
+		httpClient:      http.DefaultClient,
+		url:             url,
+		db:              db,
+		schema:          schemaName,
+		tableName:       schemaName + ".exchange_rates",
+		stateTable:      schemaName + ".sync_state",
+		eventsTable:     schemaName + ".sync_events",
+		currenciesTable: schemaName + ".currencies",
 	}
 }
 
+// AddListener registers a listener notified after every sync that inserted or changed rates.
+// Listeners are called in registration order on the goroutine running the sync.
+// AddListener must not be called concurrently with Sync.
+func (e *ExchangeRateSync) AddListener(listener SyncListener) {
+	e.listeners = append(e.listeners, listener)
+}
+
+// AddRunObserver registers an observer notified after every sync and cleanup run.
+// Observers are called on the goroutine running the job.
+// AddRunObserver must not be called concurrently with Sync or Cleanup.
+func (e *ExchangeRateSync) AddRunObserver(observer RunObserver) {
+	e.observers = append(e.observers, observer)
+}
+
 // loadHTTPData loads the exchange rates from the given URL.
 func (e *ExchangeRateSync) loadHTTPData() (models.ExchangeRates, error) {
 	req, reqErr := http.NewRequestWithContext(context.Background(), http.MethodGet, e.url, nil)
 	if reqErr != nil {
-		slog.Error("Error creating request", reqErr)
+		slog.Error("Error creating request", "error", reqErr)
 		return models.ExchangeRates{}, errors.Wrap(reqErr, "error creating request")
 	}
 
 	resp, err := e.httpClient.Do(req)
-	if err != nil || resp.StatusCode != http.StatusOK {
-		slog.Error("Error getting exchange rates", err)
+	if err != nil {
+		slog.Error("Error getting exchange rates", "error", err)
 		return models.ExchangeRates{}, errors.Wrap(err, "error getting exchange rates")
 	}
 
 	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		slog.Error("Error getting exchange rates", "status", resp.StatusCode)
+		return models.ExchangeRates{}, errors.Errorf("error getting exchange rates: unexpected status %d", resp.StatusCode)
+	}
 	var envelope models.Envelope
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		slog.Error("Error reading response body", err)
+		slog.Error("Error reading response body", "error", err)
 		return models.ExchangeRates{}, errors.Wrap(err, "error reading response body")
 	}
 	if xmlErr := xml.Unmarshal(body, &envelope); xmlErr != nil {
@@ -83,13 +122,18 @@ func (e *ExchangeRateSync) loadHTTPData() (models.ExchangeRates, error) {
 }
 
 // insertToDB inserts the exchange rates into the database using a transaction and batch inserts.
-func (e *ExchangeRateSync) insertToDB(exchangeRates models.ExchangeRates) error {
+// If any row was inserted or changed, the sync sequence is incremented and a sync event describing
+// the changed rows is recorded in the same transaction.
+// The function returns the inserted or changed rates and, if there are any, the recorded sync event.
+func (e *ExchangeRateSync) insertToDB(
+	exchangeRates models.ExchangeRates,
+) (changed models.ExchangeRates, event *models.SyncEvent, err error) {
 	ctx := context.Background()
 	sq := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
 	tx, err := e.db.Begin(ctx)
 	if err != nil {
-		slog.Error("Error beginning transaction", err)
-		return errors.Wrap(err, "error beginning transaction")
+		slog.Error("Error beginning transaction", "error", err)
+		return nil, nil, errors.Wrap(err, "error beginning transaction")
 	}
 
 	defer func() {
@@ -103,11 +147,22 @@ func (e *ExchangeRateSync) insertToDB(exchangeRates models.ExchangeRates) error
 		commitErr := tx.Commit(context.Background())
 		if commitErr != nil {
 			slog.Error("Error committing transaction", "error", commitErr)
+			changed, event, err = nil, nil, errors.Wrap(commitErr, "error committing transaction")
 		}
 	}()
 
+	known, err := e.knownCurrencies(tx)
+	if err != nil {
+		return nil, nil, err
+	}
+	exchangeRates, rejected := filterKnownCurrencies(exchangeRates, known)
+	if len(rejected) > 0 {
+		slog.Warn("Rejected exchange rates of unknown currencies", "currencies", rejected)
+	}
+
 	insertQueryBuilder := sq.Insert(e.tableName).Columns("currency", "rate", "day")
 
+	changed = make(models.ExchangeRates, 0)
 	batchSize := 1000
 	for idx := 0; idx < len(exchangeRates); idx += batchSize {
 		batchEnd := idx + batchSize
@@ -116,62 +171,288 @@ func (e *ExchangeRateSync) insertToDB(exchangeRates models.ExchangeRates) error
 		}
 
 		batchRates := exchangeRates[idx:batchEnd]
-		if err := e.insertBatchToDB(tx, insertQueryBuilder, batchRates); err != nil {
-			return err
+		var batchChanged models.ExchangeRates
+		if batchChanged, err = e.insertBatchToDB(tx, insertQueryBuilder, batchRates); err != nil {
+			return nil, nil, err
 		}
+		changed = append(changed, batchChanged...)
 
 		// Reset insertQueryBuilder for the next batch
 		insertQueryBuilder = sq.Insert(e.tableName).Columns("currency", "rate", "day")
 	}
 
-	slog.Info("Exchange rates inserted successfully")
-	return nil
+	if len(changed) > 0 {
+		var sequence int64
+		if sequence, err = e.bumpSequence(tx); err != nil {
+			return nil, nil, err
+		}
+		recorded := newSyncEvent(sequence, changed)
+		if err = e.recordSyncEvent(tx, recorded); err != nil {
+			return nil, nil, err
+		}
+		event = &recorded
+	}
+
+	slog.Info("Exchange rates inserted successfully", "changed", len(changed))
+	return changed, event, nil
 }
 
 // insertBatchToDB inserts a batch of exchange rates into the database.
+// Rows that are already stored with the same rate are left untouched.
+// The function returns the inserted or changed rates.
 func (e *ExchangeRateSync) insertBatchToDB(
 	tx pgx.Tx,
 	insertQueryBuilder squirrel.InsertBuilder,
 	batchRates models.ExchangeRates,
-) error {
+) (models.ExchangeRates, error) {
 	for _, rate := range batchRates {
 		insertQueryBuilder = insertQueryBuilder.Values(rate.Currency, rate.Rate, rate.Time)
 	}
-	insertQueryBuilder = insertQueryBuilder.Suffix(`ON CONFLICT (day, currency) DO UPDATE SET rate = EXCLUDED.rate`)
+	insertQueryBuilder = insertQueryBuilder.Suffix(fmt.Sprintf(
+		"ON CONFLICT (day, currency) DO UPDATE SET rate = EXCLUDED.rate WHERE %s.rate IS DISTINCT FROM EXCLUDED.rate "+
+			"RETURNING currency, rate, day",
+		e.tableName,
+	))
 	query, args, queryErr := insertQueryBuilder.ToSql()
 	if queryErr != nil {
-		slog.Error("Error building insert query", queryErr)
-		return errors.Wrap(queryErr, "error building insert query")
+		slog.Error("Error building insert query", "error", queryErr)
+		return nil, errors.Wrap(queryErr, "error building insert query")
+	}
+
+	rows, queryErr := tx.Query(context.Background(), query, args...)
+	if queryErr != nil {
+		slog.Error("Error inserting exchange rates", "error", queryErr)
+		return nil, errors.Wrap(queryErr, "error inserting exchange rates")
+	}
+	defer rows.Close()
+
+	changed := make(models.ExchangeRates, 0)
+	for rows.Next() {
+		var rate models.ExchangeRate
+		if scanErr := rows.Scan(&rate.Currency, &rate.Rate, &rate.Time); scanErr != nil {
+			slog.Error("Error reading inserted exchange rate", "error", scanErr)
+			return nil, errors.Wrap(scanErr, "error reading inserted exchange rate")
+		}
+		changed = append(changed, rate)
+	}
+	if rowsErr := rows.Err(); rowsErr != nil {
+		slog.Error("Error inserting exchange rates", "error", rowsErr)
+		return nil, errors.Wrap(rowsErr, "error inserting exchange rates")
+	}
+
+	slog.Debug("Inserted exchange rates", "rows", len(changed), "query", query, "args", args)
+	slog.Info("Inserted exchange rates", "rows", len(changed))
+
+	return changed, nil
+}
+
+// knownCurrencies returns the codes of the currencies table.
+// The function returns an error if the table is empty, since all rates would be rejected.
+func (e *ExchangeRateSync) knownCurrencies(tx pgx.Tx) (map[string]struct{}, error) {
+	query, args, err := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).
+		Select("code").
+		From(e.currenciesTable).
+		ToSql()
+	if err != nil {
+		slog.Error("Error building select query", "error", err)
+		return nil, errors.Wrap(err, "error building select query")
+	}
+
+	rows, err := tx.Query(context.Background(), query, args...)
+	if err != nil {
+		slog.Error("Error reading currencies", "error", err)
+		return nil, errors.Wrap(err, "error reading currencies")
+	}
+
+	codes, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		slog.Error("Error reading currencies", "error", err)
+		return nil, errors.Wrap(err, "error reading currencies")
+	}
+	if len(codes) == 0 {
+		return nil, errors.Errorf("no currencies stored in %s", e.currenciesTable)
+	}
+
+	known := make(map[string]struct{}, len(codes))
+	for _, code := range codes {
+		known[code] = struct{}{}
+	}
+
+	return known, nil
+}
+
+// filterKnownCurrencies drops the rates of currencies missing from the known codes.
+// The function returns the remaining rates and the distinct rejected codes in ascending order.
+func filterKnownCurrencies(
+	exchangeRates models.ExchangeRates,
+	known map[string]struct{},
+) (models.ExchangeRates, []string) {
+	kept := make(models.ExchangeRates, 0, len(exchangeRates))
+	rejected := make(map[string]struct{})
+	for _, rate := range exchangeRates {
+		if _, ok := known[rate.Currency]; !ok {
+			rejected[rate.Currency] = struct{}{}
+			continue
+		}
+		kept = append(kept, rate)
+	}
+
+	return kept, sortedSet(rejected)
+}
+
+// bumpSequence increments the sync sequence, which versions the stored exchange rates.
+// The function returns the new sequence.
+func (e *ExchangeRateSync) bumpSequence(tx pgx.Tx) (int64, error) {
+	query := fmt.Sprintf(
+		`INSERT INTO %[1]s (id, sequence, updated_at) VALUES (TRUE, 1, NOW())
+		ON CONFLICT (id) DO UPDATE SET sequence = %[1]s.sequence + 1, updated_at = NOW()
+		RETURNING sequence`,
+		e.stateTable,
+	)
+
+	var sequence int64
+	if err := tx.QueryRow(context.Background(), query).Scan(&sequence); err != nil {
+		slog.Error("Error updating sync sequence", "error", err)
+		return 0, errors.Wrap(err, "error updating sync sequence")
+	}
+
+	return sequence, nil
+}
+
+// recordSyncEvent records the days and currencies changed by a sync.
+func (e *ExchangeRateSync) recordSyncEvent(tx pgx.Tx, event models.SyncEvent) error {
+	days := make([]time.Time, 0, len(event.Days))
+	for _, day := range event.Days {
+		parsed, err := time.Parse("2006-01-02", day)
+		if err != nil {
+			return errors.Wrap(err, "error parsing changed day")
+		}
+		days = append(days, parsed)
 	}
 
-	res, execErr := tx.Exec(context.Background(), query, args...)
-	if execErr != nil {
-		slog.Error("Error inserting exchange rates", execErr)
-		return errors.Wrap(execErr, "error inserting exchange rates")
+	query, args, err := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).
+		Insert(e.eventsTable).
+		Columns("sequence", "latest_day", "currencies", "days").
+		Values(event.Sequence, event.Date, event.Currencies, days).
+		ToSql()
+	if err != nil {
+		slog.Error("Error building insert query", "error", err)
+		return errors.Wrap(err, "error building insert query")
 	}
 
-	slog.Debug("Inserted exchange rates", "rows", res.RowsAffected(), "query", query, "args", args)
-	slog.Info("Inserted exchange rates", "rows", res.RowsAffected())
+	if _, err = tx.Exec(context.Background(), query, args...); err != nil {
+		slog.Error("Error recording sync event", "error", err)
+		return errors.Wrap(err, "error recording sync event")
+	}
 
 	return nil
 }
 
+// newSyncEvent summarizes the changed rates of a sync: the latest changed day,
+// and the distinct changed currencies and days in ascending order.
+func newSyncEvent(sequence int64, changed models.ExchangeRates) models.SyncEvent {
+	currencies := make(map[string]struct{})
+	days := make(map[string]struct{})
+	for _, rate := range changed {
+		currencies[rate.Currency] = struct{}{}
+		days[rate.Time.Format("2006-01-02")] = struct{}{}
+	}
+
+	event := models.SyncEvent{
+		Sequence:   sequence,
+		Currencies: sortedSet(currencies),
+		Days:       sortedSet(days),
+	}
+	if len(event.Days) > 0 {
+		event.Date = event.Days[len(event.Days)-1]
+	}
+
+	return event
+}
+
+// sortedSet returns the members of a set in ascending order.
+func sortedSet(set map[string]struct{}) []string {
+	members := make([]string, 0, len(set))
+	for member := range set {
+		members = append(members, member)
+	}
+	sort.Strings(members)
+	return members
+}
+
 // Sync synchronizes the exchange rates with the external API.
+// If rates were inserted or changed, the listeners are notified after the transaction is committed.
 func (e *ExchangeRateSync) Sync() {
+	started := time.Now()
 	exchangeRates, err := e.loadHTTPData()
 	slog.Debug("Exchange rates loaded", "exchangeRates", exchangeRates, "error", err)
 	if err != nil {
-		slog.Error("Error loading exchange rates", err)
+		slog.Error("Error loading exchange rates", "error", err)
+		e.finishRun(models.JobSync, started, 0, err)
+		return
+	}
+
+	changed, event, err := e.insertToDB(exchangeRates)
+	if err != nil {
+		slog.Error("Error synchronizing exchange rates", "error", err)
+		e.finishRun(models.JobSync, started, 0, err)
 		return
 	}
 
-	if insertErr := e.insertToDB(exchangeRates); insertErr != nil {
-		slog.Info("Exchange rates synchronized successfully")
+	slog.Info("Exchange rates synchronized successfully", "changed", len(changed))
+	e.finishRun(models.JobSync, started, int64(len(changed)), nil)
+
+	if event != nil {
+		e.notifyListeners(*event)
+	}
+}
+
+// Status returns the outcome of the syncs run so far.
+// Its LastRun is zero until the first sync finished.
+func (e *ExchangeRateSync) Status() models.SyncStatus {
+	if status := e.status.Load(); status != nil {
+		return *status
+	}
+	return models.SyncStatus{}
+}
+
+// finishRun records the outcome of a finished sync in the status and notifies the run observers
+// of a finished sync or cleanup.
+func (e *ExchangeRateSync) finishRun(job models.Job, started time.Time, rows int64, err error) {
+	run := models.JobRun{Job: job, Started: started, Duration: time.Since(started), Rows: rows, Err: err}
+	if job == models.JobSync {
+		e.recordStatus(run)
+	}
+	for _, observer := range e.observers {
+		observer(run)
 	}
 }
 
-// deleteOldRates deletes the exchange rates older than the specified number of days.
-func (e *ExchangeRateSync) deleteOldRates(days int) error {
+// recordStatus records the outcome of a finished sync.
+// Syncs run one at a time, so the previous status is not changed concurrently.
+func (e *ExchangeRateSync) recordStatus(run models.JobRun) {
+	status := e.Status()
+	status.LastRun = run.Started.Add(run.Duration)
+	status.LastError = ""
+	if run.Err != nil {
+		status.LastError = run.Err.Error()
+	} else {
+		status.LastSuccess = status.LastRun
+	}
+	e.status.Store(&status)
+}
+
+// notifyListeners calls the listeners with the event of a committed sync.
+func (e *ExchangeRateSync) notifyListeners(event models.SyncEvent) {
+	for _, listener := range e.listeners {
+		listener(event)
+	}
+}
+
+// deleteOldRates deletes the exchange rates and sync events older than the specified number of days.
+// If any rate was deleted, the sync sequence is incremented in the same transaction.
+// The function returns the number of deleted rates.
+func (e *ExchangeRateSync) deleteOldRates(days int) (deleted int64, err error) {
 	ctx := context.Background()
 	sq := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
 
@@ -180,25 +461,65 @@ func (e *ExchangeRateSync) deleteOldRates(days int) error {
 
 	query, args, queryErr := deleteBuilder.ToSql()
 	if queryErr != nil {
-		slog.Error("Error building delete query", queryErr)
-		return errors.Wrap(queryErr, "error building delete query")
+		slog.Error("Error building delete query", "error", queryErr)
+		return 0, errors.Wrap(queryErr, "error building delete query")
 	}
 
-	res, execErr := e.db.Exec(ctx, query, args...)
-	if execErr != nil {
-		slog.Error("Error deleting exchange rates", execErr)
-		return errors.Wrap(execErr, "error deleting exchange rates")
+	tx, err := e.db.Begin(ctx)
+	if err != nil {
+		slog.Error("Error beginning transaction", "error", err)
+		return 0, errors.Wrap(err, "error beginning transaction")
+	}
+
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(context.Background()); rollbackErr != nil {
+				slog.Error("Error rolling back transaction", "error", rollbackErr)
+			}
+			return
+		}
+		if commitErr := tx.Commit(context.Background()); commitErr != nil {
+			slog.Error("Error committing transaction", "error", commitErr)
+			deleted, err = 0, errors.Wrap(commitErr, "error committing transaction")
+		}
+	}()
+
+	res, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		slog.Error("Error deleting exchange rates", "error", err)
+		return 0, errors.Wrap(err, "error deleting exchange rates")
+	}
+
+	if res.RowsAffected() > 0 {
+		if _, err = e.bumpSequence(tx); err != nil {
+			return 0, err
+		}
+	}
+
+	// Sync events are only needed to resume streams, so they are kept no longer than the rates.
+	eventsQuery, eventsArgs, queryErr := sq.Delete(e.eventsTable).Where(squirrel.Lt{"created_at": threshold}).ToSql()
+	if queryErr != nil {
+		slog.Error("Error building delete query", "error", queryErr)
+		return 0, errors.Wrap(queryErr, "error building delete query")
+	}
+
+	if _, err = tx.Exec(ctx, eventsQuery, eventsArgs...); err != nil {
+		slog.Error("Error deleting sync events", "error", err)
+		return 0, errors.Wrap(err, "error deleting sync events")
 	}
 
 	slog.Debug("Deleted old exchange rates", "rows", res.RowsAffected(), "query", query, "args", args)
 	slog.Info("Deleted old exchange rates", "rows", res.RowsAffected())
 
-	return nil
+	return res.RowsAffected(), nil
 }
 
 // Cleanup deletes the exchange rates older than the specified number of days.
 func (e *ExchangeRateSync) Cleanup(days int) {
-	if err := e.deleteOldRates(days); err != nil {
-		slog.Error("Error cleaning up exchange rates", err)
+	started := time.Now()
+	deleted, err := e.deleteOldRates(days)
+	if err != nil {
+		slog.Error("Error cleaning up exchange rates", "error", err)
 	}
+	e.finishRun(models.JobCleanup, started, deleted, err)
 }
@@ -4,7 +4,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/light-bringer/rates-exchanger-service/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -63,3 +65,68 @@ func TestExchangeRateSync_loadHTTPDataWithHTTPTest(t *testing.T) {
 		})
 	}
 }
+
+func TestNewSyncEvent(t *testing.T) {
+	day := func(date string) time.Time {
+		parsed, err := time.Parse("2006-01-02", date)
+		require.NoError(t, err)
+		return parsed
+	}
+
+	event := newSyncEvent(7, models.ExchangeRates{
+		{Currency: "USD", Rate: 1.0811, Time: day("2024-03-28")},
+		{Currency: "JPY", Rate: 163.4, Time: day("2024-03-28")},
+		{Currency: "USD", Rate: 1.0812, Time: day("2024-03-27")},
+	})
+
+	assert.Equal(t, models.SyncEvent{
+		Sequence:   7,
+		Date:       "2024-03-28",
+		Currencies: []string{"JPY", "USD"},
+		Days:       []string{"2024-03-27", "2024-03-28"},
+	}, event)
+}
+
+func TestFilterKnownCurrencies(t *testing.T) {
+	day := time.Date(2024, 3, 28, 0, 0, 0, 0, time.UTC)
+	known := map[string]struct{}{"USD": {}, "JPY": {}}
+
+	kept, rejected := filterKnownCurrencies(models.ExchangeRates{
+		{Currency: "USD", Rate: 1.0811, Time: day},
+		{Currency: "ZZZ", Rate: 2, Time: day},
+		{Currency: "JPY", Rate: 163.4, Time: day},
+		{Currency: "ABC", Rate: 3, Time: day},
+		{Currency: "ZZZ", Rate: 2.1, Time: day.AddDate(0, 0, -1)},
+	}, known)
+
+	assert.Equal(t, models.ExchangeRates{
+		{Currency: "USD", Rate: 1.0811, Time: day},
+		{Currency: "JPY", Rate: 163.4, Time: day},
+	}, kept)
+	assert.Equal(t, []string{"ABC", "ZZZ"}, rejected)
+}
+
+func TestExchangeRateSync_Status(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ers := NewExchangeRateSync("public", server.URL, nil)
+	assert.Zero(t, ers.Status())
+	var runs []models.JobRun
+	ers.AddRunObserver(func(run models.JobRun) { runs = append(runs, run) })
+
+	ers.Sync()
+
+	status := ers.Status()
+	assert.False(t, status.LastRun.IsZero())
+	assert.True(t, status.LastSuccess.IsZero())
+	assert.Contains(t, status.LastError, "unexpected status 503")
+
+	require.Len(t, runs, 1)
+	assert.Equal(t, models.JobSync, runs[0].Job)
+	assert.Zero(t, runs[0].Rows)
+	assert.Error(t, runs[0].Err)
+	assert.Equal(t, status.LastRun, runs[0].Started.Add(runs[0].Duration))
+}
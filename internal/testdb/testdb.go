@@ -0,0 +1,67 @@
+// Package testdb provides the tests of the queries and stores with a Postgres database.
+package testdb
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/light-bringer/rates-exchanger-service/db"
+	"github.com/light-bringer/rates-exchanger-service/db/schema"
+	"github.com/stretchr/testify/require"
+)
+
+// Env names the connection string of the Postgres database the tests run against.
+// The tests are skipped if it is not set.
+const Env = "TEST_DATABASE_URL"
+
+// New connects to the database named by TEST_DATABASE_URL and creates a fresh schema holding the tables
+// of db/schema, which is dropped when the test ends. It returns the pool and the name of the schema.
+// The test is skipped if TEST_DATABASE_URL is not set.
+func New(t *testing.T) (*pgxpool.Pool, string) {
+	t.Helper()
+	url := os.Getenv(Env)
+	if url == "" {
+		t.Skipf("%s is not set", Env)
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, url)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+
+	name := fmt.Sprintf("rates_test_%d", time.Now().UnixNano())
+	t.Cleanup(func() {
+		_, _ = pool.Exec(context.Background(), "DROP SCHEMA IF EXISTS "+name+" CASCADE")
+	})
+
+	scripts, err := fs.Glob(schema.Scripts, "*.sql")
+	require.NoError(t, err)
+	for _, script := range scripts {
+		sql, err := fs.ReadFile(schema.Scripts, script)
+		require.NoError(t, err)
+		// The scripts create the tables of the rate_api schema; without arguments, Exec runs all their statements.
+		_, err = pool.Exec(ctx, strings.ReplaceAll(string(sql), db.SchemaName, name))
+		require.NoError(t, err, script)
+	}
+
+	return pool, name
+}
+
+// InsertRates stores the given EUR based rates, keyed by day and currency, in the exchange_rates table
+// of the schema.
+func InsertRates(t *testing.T, pool *pgxpool.Pool, schema string, rates map[string]map[string]float64) {
+	t.Helper()
+	for day, currencies := range rates {
+		for currency, rate := range currencies {
+			_, err := pool.Exec(context.Background(),
+				"INSERT INTO "+schema+".exchange_rates (day, currency, rate) VALUES ($1, $2, $3)", day, currency, rate)
+			require.NoError(t, err)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package webhook
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+)
+
+// dialTimeout bounds the connection to a webhook; the whole attempt is bounded by the configured timeout.
+const dialTimeout = 5 * time.Second
+
+// ErrForbiddenAddress is returned for webhook URLs pointing to loopback, private, link-local
+// or other non-public addresses, unless AllowPrivateNetworks is set.
+var ErrForbiddenAddress = errors.New("webhook address is not public")
+
+// sharedAddressSpace is the carrier-grade NAT range of RFC 6598, which net.IP does not classify.
+//
+//nolint:gochecknoglobals // read-only prefix
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// CheckURL checks that a webhook URL is an absolute http or https URL. Unless private networks are allowed,
+// URLs whose host is localhost or a non-public IP address are rejected with ErrForbiddenAddress;
+// host names are checked again once they are resolved, at delivery time.
+func (s *Service) CheckURL(raw string) error {
+	target, err := url.Parse(raw)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Hostname() == "" {
+		return errors.Errorf("invalid url %q, expected an absolute http or https URL", raw)
+	}
+
+	if s.config.AllowPrivateNetworks {
+		return nil
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(target.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return errors.Wrapf(ErrForbiddenAddress, "host %s", host)
+	}
+	if addr, parseErr := netip.ParseAddr(host); parseErr == nil && !isPublic(addr) {
+		return errors.Wrapf(ErrForbiddenAddress, "address %s", addr)
+	}
+
+	return nil
+}
+
+// isPublic reports whether an address may be the target of a webhook.
+func isPublic(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate() && !sharedAddressSpace.Contains(addr)
+}
+
+// newClient returns the HTTP client of the deliveries. Redirects are not followed, so that a webhook
+// cannot send deliveries on to another address, and unless private networks are allowed, connections
+// to non-public addresses are refused after the host name is resolved.
+func newClient(config models.WebhookConfig) *http.Client {
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	if !config.AllowPrivateNetworks {
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return errors.Wrapf(ErrForbiddenAddress, "address %s", address)
+			}
+			if !isPublic(addrPort.Addr()) {
+				return errors.Wrapf(ErrForbiddenAddress, "address %s", addrPort.Addr())
+			}
+			return nil
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert // standard library type
+	transport.DialContext = dialer.DialContext
+	if !config.AllowPrivateNetworks {
+		// A proxy would be dialled instead of the webhook, which bypasses the address check.
+		transport.Proxy = nil
+	}
+
+	return &http.Client{
+		Timeout:   config.Timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
@@ -0,0 +1,239 @@
+package webhook
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/light-bringer/rates-exchanger-service/models"
+)
+
+// MemoryStore keeps webhooks and deliveries in memory. It is safe for concurrent use.
+type MemoryStore struct {
+	mu             sync.Mutex
+	lastWebhookID  int64
+	lastDeliveryID int64
+	webhooks       map[int64]models.Webhook
+	deliveries     map[int64]models.WebhookDelivery
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		webhooks:   make(map[int64]models.Webhook),
+		deliveries: make(map[int64]models.WebhookDelivery),
+	}
+}
+
+func (m *MemoryStore) CreateWebhook(_ context.Context, webhook models.Webhook) (models.Webhook, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lastWebhookID++
+	now := time.Now()
+	webhook.ID = m.lastWebhookID
+	webhook.CreatedAt = now
+	webhook.UpdatedAt = now
+	m.webhooks[webhook.ID] = webhook
+
+	return webhook, nil
+}
+
+func (m *MemoryStore) ListWebhooks(_ context.Context) ([]models.Webhook, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	webhooks := make([]models.Webhook, 0, len(m.webhooks))
+	for _, webhook := range m.webhooks {
+		webhook.Secret = ""
+		webhooks = append(webhooks, webhook)
+	}
+	sort.Slice(webhooks, func(i, j int) bool { return webhooks[i].ID < webhooks[j].ID })
+
+	return webhooks, nil
+}
+
+func (m *MemoryStore) GetWebhook(_ context.Context, id int64) (models.Webhook, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	webhook, ok := m.webhooks[id]
+	if !ok {
+		return models.Webhook{}, ErrWebhookNotFound
+	}
+	return webhook, nil
+}
+
+func (m *MemoryStore) UpdateWebhook(_ context.Context, webhook models.Webhook) (models.Webhook, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, ok := m.webhooks[webhook.ID]
+	if !ok {
+		return models.Webhook{}, ErrWebhookNotFound
+	}
+
+	stored.URL = webhook.URL
+	stored.Description = webhook.Description
+	stored.Secret = webhook.Secret
+	stored.Active = webhook.Active
+	stored.UpdatedAt = time.Now()
+	m.webhooks[stored.ID] = stored
+
+	return stored, nil
+}
+
+func (m *MemoryStore) DeleteWebhook(_ context.Context, id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.webhooks[id]; !ok {
+		return ErrWebhookNotFound
+	}
+
+	delete(m.webhooks, id)
+	for deliveryID, delivery := range m.deliveries {
+		if delivery.WebhookID == id {
+			delete(m.deliveries, deliveryID)
+		}
+	}
+
+	return nil
+}
+
+func (m *MemoryStore) EnqueueDeliveries(
+	_ context.Context,
+	event string,
+	sequence int64,
+	payload []byte,
+	due time.Time,
+) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ids := make([]int64, 0, len(m.webhooks))
+	for id, webhook := range m.webhooks {
+		if webhook.Active {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	for _, id := range ids {
+		m.lastDeliveryID++
+		next := due
+		m.deliveries[m.lastDeliveryID] = models.WebhookDelivery{
+			ID:            m.lastDeliveryID,
+			WebhookID:     id,
+			Event:         event,
+			Sequence:      sequence,
+			Payload:       append([]byte(nil), payload...),
+			Status:        models.DeliveryPending,
+			NextAttemptAt: &next,
+			CreatedAt:     time.Now(),
+		}
+	}
+
+	return len(ids), nil
+}
+
+func (m *MemoryStore) ClaimDueDeliveries(_ context.Context, now, leaseUntil time.Time, limit int) ([]Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	due := make([]models.WebhookDelivery, 0)
+	for _, delivery := range m.deliveries {
+		if delivery.Status != models.DeliveryPending || delivery.NextAttemptAt == nil ||
+			delivery.NextAttemptAt.After(now) || !m.webhooks[delivery.WebhookID].Active {
+			continue
+		}
+		due = append(due, delivery)
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].NextAttemptAt.Equal(*due[j].NextAttemptAt) {
+			return due[i].NextAttemptAt.Before(*due[j].NextAttemptAt)
+		}
+		return due[i].ID < due[j].ID
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	jobs := make([]Job, 0, len(due))
+	for _, delivery := range due {
+		lease := leaseUntil
+		delivery.NextAttemptAt = &lease
+		m.deliveries[delivery.ID] = delivery
+
+		webhook := m.webhooks[delivery.WebhookID]
+		jobs = append(jobs, Job{Delivery: delivery, URL: webhook.URL, Secret: webhook.Secret})
+	}
+
+	return jobs, nil
+}
+
+func (m *MemoryStore) RecordAttempt(_ context.Context, id int64, attempt Attempt) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delivery, ok := m.deliveries[id]
+	if !ok {
+		return ErrDeliveryNotFound
+	}
+
+	delivery.Attempts++
+	delivery.Status = attempt.Status
+	delivery.LastStatusCode = attempt.StatusCode
+	delivery.LastError = attempt.Error
+	delivery.NextAttemptAt = attempt.NextAttemptAt
+	if attempt.Status == models.DeliveryDelivered {
+		at := attempt.At
+		delivery.DeliveredAt = &at
+	}
+	m.deliveries[id] = delivery
+
+	return nil
+}
+
+func (m *MemoryStore) ListDeliveries(_ context.Context, filter DeliveryFilter) ([]models.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	deliveries := make([]models.WebhookDelivery, 0)
+	for _, delivery := range m.deliveries {
+		if filter.WebhookID != 0 && delivery.WebhookID != filter.WebhookID {
+			continue
+		}
+		if filter.Status != "" && delivery.Status != filter.Status {
+			continue
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].ID > deliveries[j].ID })
+	if filter.Limit > 0 && uint64(len(deliveries)) > filter.Limit {
+		deliveries = deliveries[:filter.Limit]
+	}
+
+	return deliveries, nil
+}
+
+func (m *MemoryStore) RequeueDelivery(_ context.Context, id int64, due time.Time) (models.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delivery, ok := m.deliveries[id]
+	if !ok {
+		return models.WebhookDelivery{}, ErrDeliveryNotFound
+	}
+	if delivery.Status != models.DeliveryDead {
+		return models.WebhookDelivery{}, ErrDeliveryNotDead
+	}
+
+	delivery.Status = models.DeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = &due
+	m.deliveries[id] = delivery
+
+	return delivery, nil
+}
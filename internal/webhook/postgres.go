@@ -0,0 +1,402 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/light-bringer/rates-exchanger-service/internal/logging"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+)
+
+// webhookColumns are the columns of a webhook, in the order scanned by scanWebhook.
+//
+//nolint:gochecknoglobals // read-only column list
+var webhookColumns = []string{"id", "url", "secret", "description", "active", "created_at", "updated_at"}
+
+// deliveryColumns are the columns of a delivery, in the order scanned by scanDelivery.
+//
+//nolint:gochecknoglobals // read-only column list
+var deliveryColumns = []string{
+	"id", "webhook_id", "event", "sequence", "payload", "status", "attempts",
+	"next_attempt_at", "last_status_code", "last_error", "created_at", "delivered_at",
+}
+
+// PostgresStore stores the webhooks and their deliveries in Postgres.
+type PostgresStore struct {
+	db              *pgxpool.Pool
+	webhooksTable   string
+	deliveriesTable string
+}
+
+// NewPostgresStore returns a new PostgresStore using the tables of the given schema.
+func NewPostgresStore(db *pgxpool.Pool, schema string) *PostgresStore {
+	return &PostgresStore{
+		db:              db,
+		webhooksTable:   schema + ".webhooks",
+		deliveriesTable: schema + ".webhook_deliveries",
+	}
+}
+
+func (p *PostgresStore) builder() squirrel.StatementBuilderType {
+	return squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+}
+
+func (p *PostgresStore) CreateWebhook(ctx context.Context, webhook models.Webhook) (models.Webhook, error) {
+	logger := logging.FromContext(ctx)
+	query, args, err := p.builder().
+		Insert(p.webhooksTable).
+		Columns("url", "secret", "description", "active").
+		Values(webhook.URL, webhook.Secret, webhook.Description, webhook.Active).
+		Suffix("RETURNING " + strings.Join(webhookColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return models.Webhook{}, errors.Wrap(err, "failed to build SQL query")
+	}
+
+	created, err := scanWebhook(p.db.QueryRow(ctx, query, args...))
+	if err != nil {
+		logger.Error("Failed to create webhook", "error", err)
+		return models.Webhook{}, errors.Wrap(err, "failed to create webhook")
+	}
+
+	return created, nil
+}
+
+func (p *PostgresStore) ListWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	logger := logging.FromContext(ctx)
+	query, args, err := p.builder().
+		Select(webhookColumns...).
+		From(p.webhooksTable).
+		OrderBy("id ASC").
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build SQL query")
+	}
+
+	rows, err := p.db.Query(ctx, query, args...)
+	if err != nil {
+		logger.Error("Failed to execute query", "error", err)
+		return nil, errors.Wrap(err, "failed to execute query")
+	}
+	defer rows.Close()
+
+	webhooks := make([]models.Webhook, 0)
+	for rows.Next() {
+		webhook, scanErr := scanWebhook(rows)
+		if scanErr != nil {
+			return nil, errors.Wrap(scanErr, "failed to scan row")
+		}
+		webhook.Secret = ""
+		webhooks = append(webhooks, webhook)
+	}
+	if err = rows.Err(); err != nil {
+		logger.Error("Failed to read rows", "error", err)
+		return nil, errors.Wrap(err, "failed to read rows")
+	}
+
+	return webhooks, nil
+}
+
+func (p *PostgresStore) GetWebhook(ctx context.Context, id int64) (models.Webhook, error) {
+	logger := logging.FromContext(ctx)
+	query, args, err := p.builder().
+		Select(webhookColumns...).
+		From(p.webhooksTable).
+		Where(squirrel.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return models.Webhook{}, errors.Wrap(err, "failed to build SQL query")
+	}
+
+	webhook, err := scanWebhook(p.db.QueryRow(ctx, query, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.Webhook{}, ErrWebhookNotFound
+	}
+	if err != nil {
+		logger.Error("Failed to fetch webhook", "error", err)
+		return models.Webhook{}, errors.Wrap(err, "failed to fetch webhook")
+	}
+
+	return webhook, nil
+}
+
+func (p *PostgresStore) UpdateWebhook(ctx context.Context, webhook models.Webhook) (models.Webhook, error) {
+	logger := logging.FromContext(ctx)
+	query, args, err := p.builder().
+		Update(p.webhooksTable).
+		Set("url", webhook.URL).
+		Set("secret", webhook.Secret).
+		Set("description", webhook.Description).
+		Set("active", webhook.Active).
+		Set("updated_at", squirrel.Expr("NOW()")).
+		Where(squirrel.Eq{"id": webhook.ID}).
+		Suffix("RETURNING " + strings.Join(webhookColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return models.Webhook{}, errors.Wrap(err, "failed to build SQL query")
+	}
+
+	updated, err := scanWebhook(p.db.QueryRow(ctx, query, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.Webhook{}, ErrWebhookNotFound
+	}
+	if err != nil {
+		logger.Error("Failed to update webhook", "error", err)
+		return models.Webhook{}, errors.Wrap(err, "failed to update webhook")
+	}
+
+	return updated, nil
+}
+
+func (p *PostgresStore) DeleteWebhook(ctx context.Context, id int64) error {
+	logger := logging.FromContext(ctx)
+	query, args, err := p.builder().Delete(p.webhooksTable).Where(squirrel.Eq{"id": id}).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build SQL query")
+	}
+
+	res, err := p.db.Exec(ctx, query, args...)
+	if err != nil {
+		logger.Error("Failed to delete webhook", "error", err)
+		return errors.Wrap(err, "failed to delete webhook")
+	}
+	if res.RowsAffected() == 0 {
+		return ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+func (p *PostgresStore) EnqueueDeliveries(
+	ctx context.Context,
+	event string,
+	sequence int64,
+	payload []byte,
+	due time.Time,
+) (int, error) {
+	logger := logging.FromContext(ctx)
+	query := fmt.Sprintf(
+		`INSERT INTO %s (webhook_id, event, sequence, payload, status, next_attempt_at)
+		SELECT id, $1, $2, $3, $4, $5 FROM %s WHERE active`,
+		p.deliveriesTable, p.webhooksTable,
+	)
+
+	res, err := p.db.Exec(ctx, query, event, sequence, payload, models.DeliveryPending, due)
+	if err != nil {
+		logger.Error("Failed to enqueue webhook deliveries", "error", err)
+		return 0, errors.Wrap(err, "failed to enqueue webhook deliveries")
+	}
+
+	return int(res.RowsAffected()), nil
+}
+
+// ClaimDueDeliveries locks the due deliveries with SKIP LOCKED, so that several instances
+// of the service can work through the same queue without attempting a delivery twice.
+func (p *PostgresStore) ClaimDueDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]Job, error) {
+	logger := logging.FromContext(ctx)
+	query := fmt.Sprintf(
+		`UPDATE %[1]s d SET next_attempt_at = $1
+		FROM (
+			SELECT pending.id FROM %[1]s pending
+			JOIN %[2]s hook ON hook.id = pending.webhook_id
+			WHERE pending.status = $2 AND pending.next_attempt_at <= $3 AND hook.active
+			ORDER BY pending.next_attempt_at, pending.id
+			LIMIT $4
+			FOR UPDATE OF pending SKIP LOCKED
+		) due, %[2]s w
+		WHERE d.id = due.id AND w.id = d.webhook_id
+		RETURNING %[3]s, w.url, w.secret`,
+		p.deliveriesTable, p.webhooksTable, strings.Join(prefixed("d.", deliveryColumns), ", "),
+	)
+
+	rows, err := p.db.Query(ctx, query, leaseUntil, models.DeliveryPending, now, limit)
+	if err != nil {
+		logger.Error("Failed to claim webhook deliveries", "error", err)
+		return nil, errors.Wrap(err, "failed to claim webhook deliveries")
+	}
+	defer rows.Close()
+
+	jobs := make([]Job, 0)
+	for rows.Next() {
+		var job Job
+		if job.Delivery, err = scanDelivery(rows, &job.URL, &job.Secret); err != nil {
+			return nil, errors.Wrap(err, "failed to scan row")
+		}
+		jobs = append(jobs, job)
+	}
+	if err = rows.Err(); err != nil {
+		logger.Error("Failed to read rows", "error", err)
+		return nil, errors.Wrap(err, "failed to read rows")
+	}
+
+	return jobs, nil
+}
+
+func (p *PostgresStore) RecordAttempt(ctx context.Context, id int64, attempt Attempt) error {
+	logger := logging.FromContext(ctx)
+	var statusCode *int
+	if attempt.StatusCode != 0 {
+		statusCode = &attempt.StatusCode
+	}
+	var deliveredAt *time.Time
+	if attempt.Status == models.DeliveryDelivered {
+		deliveredAt = &attempt.At
+	}
+
+	query, args, err := p.builder().
+		Update(p.deliveriesTable).
+		Set("attempts", squirrel.Expr("attempts + 1")).
+		Set("status", attempt.Status).
+		Set("last_status_code", statusCode).
+		Set("last_error", attempt.Error).
+		Set("next_attempt_at", attempt.NextAttemptAt).
+		Set("delivered_at", deliveredAt).
+		Where(squirrel.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build SQL query")
+	}
+
+	res, err := p.db.Exec(ctx, query, args...)
+	if err != nil {
+		logger.Error("Failed to record webhook delivery attempt", "error", err)
+		return errors.Wrap(err, "failed to record webhook delivery attempt")
+	}
+	if res.RowsAffected() == 0 {
+		return ErrDeliveryNotFound
+	}
+
+	return nil
+}
+
+func (p *PostgresStore) ListDeliveries(ctx context.Context, filter DeliveryFilter) ([]models.WebhookDelivery, error) {
+	logger := logging.FromContext(ctx)
+	builder := p.builder().
+		Select(deliveryColumns...).
+		From(p.deliveriesTable).
+		OrderBy("id DESC")
+	if filter.WebhookID != 0 {
+		builder = builder.Where(squirrel.Eq{"webhook_id": filter.WebhookID})
+	}
+	if filter.Status != "" {
+		builder = builder.Where(squirrel.Eq{"status": filter.Status})
+	}
+	if filter.Limit > 0 {
+		builder = builder.Limit(filter.Limit)
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build SQL query")
+	}
+
+	rows, err := p.db.Query(ctx, query, args...)
+	if err != nil {
+		logger.Error("Failed to execute query", "error", err)
+		return nil, errors.Wrap(err, "failed to execute query")
+	}
+	defer rows.Close()
+
+	deliveries := make([]models.WebhookDelivery, 0)
+	for rows.Next() {
+		delivery, scanErr := scanDelivery(rows)
+		if scanErr != nil {
+			return nil, errors.Wrap(scanErr, "failed to scan row")
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if err = rows.Err(); err != nil {
+		logger.Error("Failed to read rows", "error", err)
+		return nil, errors.Wrap(err, "failed to read rows")
+	}
+
+	return deliveries, nil
+}
+
+func (p *PostgresStore) RequeueDelivery(ctx context.Context, id int64, due time.Time) (models.WebhookDelivery, error) {
+	logger := logging.FromContext(ctx)
+	query, args, err := p.builder().
+		Update(p.deliveriesTable).
+		Set("status", models.DeliveryPending).
+		Set("attempts", 0).
+		Set("next_attempt_at", due).
+		Where(squirrel.Eq{"id": id, "status": models.DeliveryDead}).
+		Suffix("RETURNING " + strings.Join(deliveryColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return models.WebhookDelivery{}, errors.Wrap(err, "failed to build SQL query")
+	}
+
+	delivery, err := scanDelivery(p.db.QueryRow(ctx, query, args...))
+	if err == nil {
+		return delivery, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		logger.Error("Failed to requeue webhook delivery", "error", err)
+		return models.WebhookDelivery{}, errors.Wrap(err, "failed to requeue webhook delivery")
+	}
+
+	// Tell a missing delivery from one that is not a dead letter.
+	existsQuery, existsArgs, err := p.builder().
+		Select("1").
+		From(p.deliveriesTable).
+		Where(squirrel.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return models.WebhookDelivery{}, errors.Wrap(err, "failed to build SQL query")
+	}
+
+	var exists int
+	if err = p.db.QueryRow(ctx, existsQuery, existsArgs...).Scan(&exists); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.WebhookDelivery{}, ErrDeliveryNotFound
+		}
+		return models.WebhookDelivery{}, errors.Wrap(err, "failed to fetch webhook delivery")
+	}
+
+	return models.WebhookDelivery{}, ErrDeliveryNotDead
+}
+
+// scanWebhook scans a row of webhookColumns.
+func scanWebhook(row pgx.Row) (models.Webhook, error) {
+	var webhook models.Webhook
+	err := row.Scan(&webhook.ID, &webhook.URL, &webhook.Secret, &webhook.Description, &webhook.Active,
+		&webhook.CreatedAt, &webhook.UpdatedAt)
+	return webhook, err //nolint:wrapcheck // wrapped by the callers
+}
+
+// scanDelivery scans a row of deliveryColumns followed by the given extra columns.
+func scanDelivery(row pgx.Row, extra ...any) (models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	var payload []byte
+	var statusCode *int
+	dest := []any{
+		&delivery.ID, &delivery.WebhookID, &delivery.Event, &delivery.Sequence, &payload, &delivery.Status,
+		&delivery.Attempts, &delivery.NextAttemptAt, &statusCode, &delivery.LastError, &delivery.CreatedAt,
+		&delivery.DeliveredAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return models.WebhookDelivery{}, err //nolint:wrapcheck // wrapped by the callers
+	}
+
+	delivery.Payload = payload
+	if statusCode != nil {
+		delivery.LastStatusCode = *statusCode
+	}
+	return delivery, nil
+}
+
+// prefixed qualifies columns with a table alias.
+func prefixed(prefix string, columns []string) []string {
+	qualified := make([]string, 0, len(columns))
+	for _, column := range columns {
+		qualified = append(qualified, prefix+column)
+	}
+	return qualified
+}
@@ -0,0 +1,222 @@
+package webhook
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/light-bringer/rates-exchanger-service/internal/testdb"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPayload is the payload of the deliveries enqueued by the tests.
+const testPayload = `{"event": "rates.synced", "sequence": 1}`
+
+// newTestPostgresStore returns a PostgresStore on a fresh schema.
+// The test is skipped if no test database is set; see testdb.New.
+func newTestPostgresStore(t *testing.T) *PostgresStore {
+	t.Helper()
+	pool, schema := testdb.New(t)
+	return NewPostgresStore(pool, schema)
+}
+
+// createTestWebhook stores a webhook with the given URL and state.
+func createTestWebhook(t *testing.T, store *PostgresStore, url string, active bool) models.Webhook {
+	t.Helper()
+	webhook, err := store.CreateWebhook(context.Background(),
+		models.Webhook{URL: url, Secret: "secret", Active: active})
+	require.NoError(t, err)
+	return webhook
+}
+
+func TestPostgresStoreWebhooks(t *testing.T) {
+	store := newTestPostgresStore(t)
+	ctx := context.Background()
+
+	created, err := store.CreateWebhook(ctx, models.Webhook{
+		URL: "https://example.com/hook", Secret: "secret", Description: "rates", Active: true,
+	})
+	require.NoError(t, err)
+	assert.NotZero(t, created.ID)
+	assert.Equal(t, "secret", created.Secret)
+	assert.False(t, created.CreatedAt.IsZero())
+
+	webhooks, err := store.ListWebhooks(ctx)
+	require.NoError(t, err)
+	require.Len(t, webhooks, 1)
+	assert.Equal(t, created.ID, webhooks[0].ID)
+	assert.Empty(t, webhooks[0].Secret, "listed webhooks do not include their secrets")
+
+	created.URL = "https://example.com/other"
+	created.Active = false
+	updated, err := store.UpdateWebhook(ctx, created)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/other", updated.URL)
+	assert.False(t, updated.Active)
+
+	fetched, err := store.GetWebhook(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", fetched.Secret)
+	assert.Equal(t, "https://example.com/other", fetched.URL)
+
+	require.NoError(t, store.DeleteWebhook(ctx, created.ID))
+	_, err = store.GetWebhook(ctx, created.ID)
+	require.ErrorIs(t, err, ErrWebhookNotFound)
+	require.ErrorIs(t, store.DeleteWebhook(ctx, created.ID), ErrWebhookNotFound)
+	_, err = store.UpdateWebhook(ctx, created)
+	require.ErrorIs(t, err, ErrWebhookNotFound)
+}
+
+func TestPostgresStoreDeliveries(t *testing.T) {
+	store := newTestPostgresStore(t)
+	ctx := context.Background()
+	active := createTestWebhook(t, store, "https://example.com/active", true)
+	createTestWebhook(t, store, "https://example.com/inactive", false)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	count, err := store.EnqueueDeliveries(ctx, models.WebhookEventRatesSynced, 1, []byte(testPayload), now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count, "only active webhooks get deliveries")
+
+	jobs, err := store.ClaimDueDeliveries(ctx, now.Add(-time.Second), now.Add(time.Minute), 10)
+	require.NoError(t, err)
+	assert.Empty(t, jobs, "deliveries are not claimed before they are due")
+
+	leaseUntil := now.Add(time.Minute)
+	jobs, err = store.ClaimDueDeliveries(ctx, now, leaseUntil, 10)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	job := jobs[0]
+	assert.Equal(t, active.ID, job.Delivery.WebhookID)
+	assert.Equal(t, "https://example.com/active", job.URL)
+	assert.Equal(t, "secret", job.Secret)
+	assert.Equal(t, models.WebhookEventRatesSynced, job.Delivery.Event)
+	assert.JSONEq(t, testPayload, string(job.Delivery.Payload))
+	require.NotNil(t, job.Delivery.NextAttemptAt)
+	assert.True(t, leaseUntil.Equal(*job.Delivery.NextAttemptAt), "claimed deliveries are leased")
+
+	jobs, err = store.ClaimDueDeliveries(ctx, now, leaseUntil, 10)
+	require.NoError(t, err)
+	assert.Empty(t, jobs, "leased deliveries are not claimed again")
+
+	// A delivery whose attempt is not recorded within the lease is claimed again.
+	jobs, err = store.ClaimDueDeliveries(ctx, leaseUntil, leaseUntil.Add(time.Minute), 10)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+
+	retryAt := now.Add(time.Hour)
+	require.NoError(t, store.RecordAttempt(ctx, job.Delivery.ID, Attempt{
+		Status: models.DeliveryPending, StatusCode: 500, Error: "unexpected status 500", At: now,
+		NextAttemptAt: &retryAt,
+	}))
+	jobs, err = store.ClaimDueDeliveries(ctx, retryAt.Add(-time.Second), retryAt.Add(time.Minute), 10)
+	require.NoError(t, err)
+	assert.Empty(t, jobs, "failed deliveries are retried at their next attempt")
+
+	jobs, err = store.ClaimDueDeliveries(ctx, retryAt, retryAt.Add(time.Minute), 10)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, 1, jobs[0].Delivery.Attempts)
+	assert.Equal(t, 500, jobs[0].Delivery.LastStatusCode)
+
+	require.NoError(t, store.RecordAttempt(ctx, job.Delivery.ID, Attempt{
+		Status: models.DeliveryDelivered, StatusCode: 204, At: retryAt,
+	}))
+	jobs, err = store.ClaimDueDeliveries(ctx, retryAt.Add(time.Hour), retryAt.Add(2*time.Hour), 10)
+	require.NoError(t, err)
+	assert.Empty(t, jobs, "delivered deliveries are not claimed")
+
+	deliveries, err := store.ListDeliveries(ctx, DeliveryFilter{Status: models.DeliveryDelivered})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	delivery := deliveries[0]
+	assert.Equal(t, 2, delivery.Attempts)
+	assert.Equal(t, 204, delivery.LastStatusCode)
+	assert.Empty(t, delivery.LastError)
+	assert.Nil(t, delivery.NextAttemptAt)
+	require.NotNil(t, delivery.DeliveredAt)
+	assert.True(t, retryAt.Equal(*delivery.DeliveredAt))
+
+	require.ErrorIs(t, store.RecordAttempt(ctx, delivery.ID+1, Attempt{Status: models.DeliveryDead, At: now}),
+		ErrDeliveryNotFound)
+}
+
+func TestPostgresStoreRequeueDelivery(t *testing.T) {
+	store := newTestPostgresStore(t)
+	ctx := context.Background()
+	webhook := createTestWebhook(t, store, "https://example.com/hook", true)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	_, err := store.EnqueueDeliveries(ctx, models.WebhookEventRatesSynced, 1, []byte(testPayload), now)
+	require.NoError(t, err)
+	jobs, err := store.ClaimDueDeliveries(ctx, now, now.Add(time.Minute), 10)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	id := jobs[0].Delivery.ID
+
+	_, err = store.RequeueDelivery(ctx, id, now)
+	require.ErrorIs(t, err, ErrDeliveryNotDead)
+	_, err = store.RequeueDelivery(ctx, id+1, now)
+	require.ErrorIs(t, err, ErrDeliveryNotFound)
+
+	require.NoError(t, store.RecordAttempt(ctx, id, Attempt{Status: models.DeliveryDead, Error: "timeout", At: now}))
+	requeued, err := store.RequeueDelivery(ctx, id, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, models.DeliveryPending, requeued.Status)
+	assert.Zero(t, requeued.Attempts)
+
+	jobs, err = store.ClaimDueDeliveries(ctx, now.Add(time.Minute), now.Add(2*time.Minute), 10)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+
+	// Deleting a webhook deletes its deliveries.
+	require.NoError(t, store.DeleteWebhook(ctx, webhook.ID))
+	deliveries, err := store.ListDeliveries(ctx, DeliveryFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, deliveries)
+}
+
+func TestPostgresStoreClaimsConcurrently(t *testing.T) {
+	store := newTestPostgresStore(t)
+	ctx := context.Background()
+	const webhooks, events, workers = 5, 10, 8
+	for i := 0; i < webhooks; i++ {
+		createTestWebhook(t, store, "https://example.com/hook", true)
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	for sequence := int64(1); sequence <= events; sequence++ {
+		_, err := store.EnqueueDeliveries(ctx, models.WebhookEventRatesSynced, sequence, []byte(testPayload), now)
+		require.NoError(t, err)
+	}
+
+	// Workers claim small batches until the queue is empty, like several instances of the service.
+	var mu sync.Mutex
+	claims := make(map[int64]int)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				jobs, err := store.ClaimDueDeliveries(ctx, now, now.Add(time.Hour), 3)
+				if !assert.NoError(t, err) || len(jobs) == 0 {
+					return
+				}
+				mu.Lock()
+				for _, job := range jobs {
+					claims[job.Delivery.ID]++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, claims, webhooks*events)
+	for id, count := range claims {
+		assert.Equal(t, 1, count, "delivery %d was claimed more than once", id)
+	}
+}
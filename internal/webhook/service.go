@@ -0,0 +1,328 @@
+// Package webhook notifies subscribers of new rates with signed HTTP callbacks.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/light-bringer/rates-exchanger-service/internal/logging"
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+)
+
+const (
+	// claimBatchSize bounds the number of deliveries attempted concurrently.
+	claimBatchSize = 20
+	// secretBytes is the size of generated secrets.
+	secretBytes = 32
+	// maxResponseBody bounds the part of a response body that is read to reuse the connection.
+	maxResponseBody = 64 << 10
+	// enqueueTimeout bounds the creation of the deliveries of a sync event.
+	enqueueTimeout = 10 * time.Second
+)
+
+// Service manages the webhooks and delivers the sync events to them.
+// Deliveries are stored before they are attempted; failed attempts are retried with
+// exponential backoff until MaxAttempts is reached, after which the delivery is kept as a dead letter.
+type Service struct {
+	store  Store
+	config models.WebhookConfig
+	client *http.Client
+	now    func() time.Time
+	// wake triggers an immediate attempt of the due deliveries.
+	wake chan struct{}
+}
+
+// NewService returns a new Service with the given store and configuration.
+func NewService(store Store, config models.WebhookConfig) *Service {
+	return &Service{
+		store:  store,
+		config: config,
+		client: newClient(config),
+		now:    time.Now,
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+// CreateWebhook creates a webhook. A random secret is generated if none is given.
+// The returned webhook includes its secret.
+func (s *Service) CreateWebhook(ctx context.Context, webhook models.Webhook) (models.Webhook, error) {
+	logger := logging.FromContext(ctx)
+	if webhook.Secret == "" {
+		secret, err := newSecret()
+		if err != nil {
+			return models.Webhook{}, err
+		}
+		webhook.Secret = secret
+	}
+
+	created, err := s.store.CreateWebhook(ctx, webhook)
+	if err != nil {
+		return models.Webhook{}, err //nolint:wrapcheck // store errors are wrapped
+	}
+
+	logger.Info("Webhook created", "id", created.ID, "url", created.URL)
+	return created, nil
+}
+
+// ListWebhooks returns all webhooks, without their secrets.
+func (s *Service) ListWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	return s.store.ListWebhooks(ctx) //nolint:wrapcheck // store errors are wrapped
+}
+
+// GetWebhook returns a webhook, without its secret.
+func (s *Service) GetWebhook(ctx context.Context, id int64) (models.Webhook, error) {
+	webhook, err := s.store.GetWebhook(ctx, id)
+	if err != nil {
+		return models.Webhook{}, err //nolint:wrapcheck // store errors are wrapped
+	}
+	webhook.Secret = ""
+	return webhook, nil
+}
+
+// UpdateWebhook applies the given fields to a webhook and returns it, without its secret.
+func (s *Service) UpdateWebhook(ctx context.Context, id int64, input models.WebhookInput) (models.Webhook, error) {
+	webhook, err := s.store.GetWebhook(ctx, id)
+	if err != nil {
+		return models.Webhook{}, err //nolint:wrapcheck // store errors are wrapped
+	}
+
+	if input.URL != nil {
+		webhook.URL = *input.URL
+	}
+	if input.Description != nil {
+		webhook.Description = *input.Description
+	}
+	if input.Secret != nil {
+		webhook.Secret = *input.Secret
+	}
+	if input.Active != nil {
+		webhook.Active = *input.Active
+	}
+
+	updated, err := s.store.UpdateWebhook(ctx, webhook)
+	if err != nil {
+		return models.Webhook{}, err //nolint:wrapcheck // store errors are wrapped
+	}
+
+	updated.Secret = ""
+	return updated, nil
+}
+
+// DeleteWebhook deletes a webhook together with its deliveries.
+func (s *Service) DeleteWebhook(ctx context.Context, id int64) error {
+	return s.store.DeleteWebhook(ctx, id) //nolint:wrapcheck // store errors are wrapped
+}
+
+// ListDeliveries returns the deliveries matching the filter, newest first.
+func (s *Service) ListDeliveries(ctx context.Context, filter DeliveryFilter) ([]models.WebhookDelivery, error) {
+	if filter.WebhookID != 0 {
+		if _, err := s.store.GetWebhook(ctx, filter.WebhookID); err != nil {
+			return nil, err //nolint:wrapcheck // store errors are wrapped
+		}
+	}
+	return s.store.ListDeliveries(ctx, filter) //nolint:wrapcheck // store errors are wrapped
+}
+
+// RetryDelivery makes a dead letter pending again, so that it is attempted with a fresh set of retries.
+func (s *Service) RetryDelivery(ctx context.Context, id int64) (models.WebhookDelivery, error) {
+	delivery, err := s.store.RequeueDelivery(ctx, id, s.now())
+	if err != nil {
+		return models.WebhookDelivery{}, err //nolint:wrapcheck // store errors are wrapped
+	}
+
+	s.trigger()
+	return delivery, nil
+}
+
+// Notify enqueues a delivery of the sync event for every active webhook and triggers their attempt.
+// It is registered as listener of the sync.
+func (s *Service) Notify(event models.SyncEvent) {
+	ctx, cancel := context.WithTimeout(context.Background(), enqueueTimeout)
+	defer cancel()
+
+	if _, err := s.Enqueue(ctx, event); err != nil {
+		slog.Error("Failed to enqueue webhook deliveries", "sequence", event.Sequence, "error", err)
+	}
+}
+
+// Enqueue creates a pending delivery of the sync event for every active webhook and triggers their attempt.
+// The function returns the number of deliveries created.
+func (s *Service) Enqueue(ctx context.Context, event models.SyncEvent) (int, error) {
+	logger := logging.FromContext(ctx)
+	payload, err := json.Marshal(models.WebhookPayload{Event: models.WebhookEventRatesSynced, SyncEvent: event})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to encode webhook payload")
+	}
+
+	count, err := s.store.EnqueueDeliveries(ctx, models.WebhookEventRatesSynced, event.Sequence, payload, s.now())
+	if err != nil {
+		return 0, err //nolint:wrapcheck // store errors are wrapped
+	}
+
+	logger.Info("Webhook deliveries enqueued", "sequence", event.Sequence, "deliveries", count)
+	if count > 0 {
+		s.trigger()
+	}
+	return count, nil
+}
+
+// Run attempts the due deliveries every poll interval, and right away when deliveries are enqueued,
+// until the context is done.
+func (s *Service) Run(ctx context.Context) {
+	logger := logging.FromContext(ctx)
+	ticker := time.NewTicker(s.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			attempted, err := s.ProcessDue(ctx)
+			if err != nil {
+				logger.Error("Failed to process webhook deliveries", "error", err)
+			}
+			if err != nil || attempted < claimBatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.Debug("Stopping webhook deliveries...")
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// ProcessDue attempts a batch of due deliveries concurrently and returns the number of deliveries attempted.
+func (s *Service) ProcessDue(ctx context.Context) (int, error) {
+	logger := logging.FromContext(ctx)
+	now := s.now()
+	// A delivery that is not recorded within the lease, e.g. because the instance stopped, is attempted again.
+	leaseUntil := now.Add(2 * s.config.Timeout)
+
+	jobs, err := s.store.ClaimDueDeliveries(ctx, now, leaseUntil, claimBatchSize)
+	if err != nil {
+		return 0, err //nolint:wrapcheck // store errors are wrapped
+	}
+
+	var wg sync.WaitGroup
+	for _, job := range jobs {
+		wg.Add(1)
+		go func(job Job) {
+			defer wg.Done()
+			attempt := s.attempt(ctx, job)
+			if recordErr := s.store.RecordAttempt(ctx, job.Delivery.ID, attempt); recordErr != nil {
+				logger.Error("Failed to record webhook delivery attempt", "delivery", job.Delivery.ID,
+					"error", recordErr)
+			}
+		}(job)
+	}
+	wg.Wait()
+
+	return len(jobs), nil
+}
+
+// attempt POSTs a delivery to its webhook and returns the outcome.
+func (s *Service) attempt(ctx context.Context, job Job) Attempt {
+	logger := logging.FromContext(ctx)
+	attempts := job.Delivery.Attempts + 1
+	statusCode, err := s.post(ctx, job)
+	attempt := Attempt{StatusCode: statusCode, At: s.now()}
+
+	switch {
+	case err == nil:
+		attempt.Status = models.DeliveryDelivered
+		logger.Info("Webhook delivered", "delivery", job.Delivery.ID, "webhook", job.Delivery.WebhookID,
+			"attempts", attempts)
+	case attempts >= s.config.MaxAttempts:
+		attempt.Status = models.DeliveryDead
+		attempt.Error = err.Error()
+		logger.Warn("Webhook delivery failed permanently", "delivery", job.Delivery.ID,
+			"webhook", job.Delivery.WebhookID, "attempts", attempts, "error", err)
+	default:
+		next := attempt.At.Add(s.backoff(attempts))
+		attempt.Status = models.DeliveryPending
+		attempt.Error = err.Error()
+		attempt.NextAttemptAt = &next
+		logger.Warn("Webhook delivery failed, retrying", "delivery", job.Delivery.ID,
+			"webhook", job.Delivery.WebhookID, "attempts", attempts, "next_attempt_at", next, "error", err)
+	}
+
+	return attempt
+}
+
+// post sends a signed delivery. Any 2xx response acknowledges it.
+// The function returns the status code of the response, if one was received.
+func (s *Service) post(ctx context.Context, job Job) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, job.URL, bytes.NewReader(job.Delivery.Payload))
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to create request")
+	}
+
+	timestamp := s.now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "rates-exchanger-service-webhooks")
+	req.Header.Set(EventHeader, job.Delivery.Event)
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(job.Delivery.ID, 10))
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(job.Secret, timestamp, job.Delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to send request")
+	}
+	defer resp.Body.Close()
+
+	// Drain the body, so that the connection can be reused.
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBody))
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return resp.StatusCode, errors.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// backoff returns the delay after the given number of failed attempts:
+// InitialBackoff doubled for every further attempt, capped at MaxBackoff.
+func (s *Service) backoff(attempts int) time.Duration {
+	delay := s.config.InitialBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= s.config.MaxBackoff {
+			return s.config.MaxBackoff
+		}
+	}
+	if delay > s.config.MaxBackoff {
+		return s.config.MaxBackoff
+	}
+	return delay
+}
+
+// trigger wakes the delivery loop without blocking.
+func (s *Service) trigger() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// newSecret returns a random 256-bit hex encoded secret.
+func newSecret() (string, error) {
+	buf := make([]byte, secretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", errors.Wrap(err, "failed to generate secret")
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,383 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// receiver is a webhook endpoint answering with a scripted sequence of status codes.
+type receiver struct {
+	t        *testing.T
+	secret   string
+	mu       sync.Mutex
+	statuses []int
+	bodies   [][]byte
+	headers  []http.Header
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	require.NoError(rc.t, err)
+	assert.Equal(rc.t, http.MethodPost, r.Method)
+	assert.Equal(rc.t, "application/json", r.Header.Get("Content-Type"))
+	assert.True(rc.t, Verify(rc.secret, r.Header.Get(TimestampHeader), r.Header.Get(SignatureHeader), body),
+		"signature must match")
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.bodies = append(rc.bodies, body)
+	rc.headers = append(rc.headers, r.Header.Clone())
+
+	status := http.StatusNoContent
+	if len(rc.statuses) > 0 {
+		status = rc.statuses[0]
+		rc.statuses = rc.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func (rc *receiver) calls() int {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return len(rc.bodies)
+}
+
+// newTestService returns a service on a MemoryStore with a controllable clock,
+// and a webhook pointing to a receiver answering with the given status codes.
+// Private networks are allowed, as the receiver listens on the loopback interface.
+func newTestService(t *testing.T, config models.WebhookConfig, statuses ...int) (*Service, *receiver, *time.Time) {
+	t.Helper()
+
+	rc := &receiver{t: t, secret: "s3cret", statuses: statuses}
+	server := httptest.NewServer(rc)
+	t.Cleanup(server.Close)
+
+	if config.Timeout == 0 {
+		config.Timeout = 5 * time.Second
+	}
+	config.AllowPrivateNetworks = true
+	service := NewService(NewMemoryStore(), config)
+	now := time.Date(2024, 6, 3, 16, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	_, err := service.CreateWebhook(context.Background(), models.Webhook{URL: server.URL, Secret: rc.secret, Active: true})
+	require.NoError(t, err)
+
+	return service, rc, &now
+}
+
+func testEvent() models.SyncEvent {
+	return models.SyncEvent{
+		Sequence:   42,
+		Date:       "2024-06-03",
+		Currencies: []string{"USD"},
+		Days:       []string{"2024-06-03"},
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	body := []byte(`{"event":"rates.synced"}`)
+	signature := Sign("secret", 1717430400, body)
+
+	assert.Regexp(t, `^sha256=[0-9a-f]{64}$`, signature)
+	assert.True(t, Verify("secret", "1717430400", signature, body))
+	assert.False(t, Verify("other", "1717430400", signature, body), "wrong secret")
+	assert.False(t, Verify("secret", "1717430401", signature, body), "wrong timestamp")
+	assert.False(t, Verify("secret", "1717430400", signature, []byte(`{}`)), "tampered body")
+	assert.False(t, Verify("secret", "now", signature, body), "invalid timestamp")
+}
+
+func TestServiceDelivers(t *testing.T) {
+	ctx := context.Background()
+	service, rc, _ := newTestService(t, models.WebhookConfig{MaxAttempts: 3, InitialBackoff: time.Minute,
+		MaxBackoff: time.Hour})
+
+	count, err := service.Enqueue(ctx, testEvent())
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	attempted, err := service.ProcessDue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, attempted)
+	require.Equal(t, 1, rc.calls())
+
+	var payload map[string]any
+	require.NoError(t, json.Unmarshal(rc.bodies[0], &payload))
+	assert.Equal(t, "rates.synced", payload["event"])
+	assert.InDelta(t, 42, payload["sequence"], 0)
+	assert.Equal(t, "2024-06-03", payload["date"])
+	assert.Equal(t, "rates.synced", rc.headers[0].Get(EventHeader))
+
+	deliveries, err := service.ListDeliveries(ctx, DeliveryFilter{})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, strconv.FormatInt(deliveries[0].ID, 10), rc.headers[0].Get(DeliveryHeader))
+	assert.Equal(t, models.DeliveryDelivered, deliveries[0].Status)
+	assert.Equal(t, 1, deliveries[0].Attempts)
+	assert.Equal(t, http.StatusNoContent, deliveries[0].LastStatusCode)
+	assert.NotNil(t, deliveries[0].DeliveredAt)
+	assert.Nil(t, deliveries[0].NextAttemptAt)
+
+	// Delivered deliveries are not attempted again.
+	attempted, err = service.ProcessDue(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, attempted)
+}
+
+func TestServiceRetriesWithBackoff(t *testing.T) {
+	ctx := context.Background()
+	service, rc, now := newTestService(t, models.WebhookConfig{MaxAttempts: 5, InitialBackoff: time.Minute,
+		MaxBackoff: time.Hour}, http.StatusInternalServerError, http.StatusBadGateway)
+	start := *now
+
+	_, err := service.Enqueue(ctx, testEvent())
+	require.NoError(t, err)
+
+	_, err = service.ProcessDue(ctx)
+	require.NoError(t, err)
+
+	deliveries, err := service.ListDeliveries(ctx, DeliveryFilter{})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, models.DeliveryPending, deliveries[0].Status)
+	assert.Equal(t, http.StatusInternalServerError, deliveries[0].LastStatusCode)
+	assert.Equal(t, "unexpected status 500", deliveries[0].LastError)
+	assert.Equal(t, start.Add(time.Minute), *deliveries[0].NextAttemptAt)
+
+	// Not due yet.
+	attempted, err := service.ProcessDue(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, attempted)
+
+	*now = start.Add(time.Minute)
+	_, err = service.ProcessDue(ctx)
+	require.NoError(t, err)
+
+	deliveries, err = service.ListDeliveries(ctx, DeliveryFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, deliveries[0].Attempts)
+	assert.Equal(t, now.Add(2*time.Minute), *deliveries[0].NextAttemptAt, "backoff doubles")
+
+	*now = now.Add(2 * time.Minute)
+	_, err = service.ProcessDue(ctx)
+	require.NoError(t, err)
+
+	deliveries, err = service.ListDeliveries(ctx, DeliveryFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, models.DeliveryDelivered, deliveries[0].Status)
+	assert.Equal(t, 3, deliveries[0].Attempts)
+	assert.Empty(t, deliveries[0].LastError)
+	assert.Equal(t, 3, rc.calls())
+}
+
+func TestServiceDeadLetters(t *testing.T) {
+	ctx := context.Background()
+	service, rc, now := newTestService(t, models.WebhookConfig{MaxAttempts: 2, InitialBackoff: time.Minute,
+		MaxBackoff: time.Hour}, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+
+	_, err := service.Enqueue(ctx, testEvent())
+	require.NoError(t, err)
+
+	_, err = service.ProcessDue(ctx)
+	require.NoError(t, err)
+	*now = now.Add(time.Minute)
+	_, err = service.ProcessDue(ctx)
+	require.NoError(t, err)
+
+	dead, err := service.ListDeliveries(ctx, DeliveryFilter{Status: models.DeliveryDead})
+	require.NoError(t, err)
+	require.Len(t, dead, 1)
+	assert.Equal(t, 2, dead[0].Attempts)
+	assert.Nil(t, dead[0].NextAttemptAt)
+
+	// Dead letters are not attempted until they are retried.
+	*now = now.Add(24 * time.Hour)
+	attempted, err := service.ProcessDue(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, attempted)
+
+	retried, err := service.RetryDelivery(ctx, dead[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.DeliveryPending, retried.Status)
+	assert.Zero(t, retried.Attempts)
+
+	_, err = service.RetryDelivery(ctx, dead[0].ID)
+	require.ErrorIs(t, err, ErrDeliveryNotDead)
+	_, err = service.RetryDelivery(ctx, 999)
+	require.ErrorIs(t, err, ErrDeliveryNotFound)
+
+	_, err = service.ProcessDue(ctx)
+	require.NoError(t, err)
+
+	deliveries, err := service.ListDeliveries(ctx, DeliveryFilter{WebhookID: dead[0].WebhookID})
+	require.NoError(t, err)
+	assert.Equal(t, models.DeliveryDelivered, deliveries[0].Status)
+	assert.Equal(t, 3, rc.calls())
+}
+
+func TestServiceSkipsInactiveWebhooks(t *testing.T) {
+	ctx := context.Background()
+	service, rc, _ := newTestService(t, models.WebhookConfig{MaxAttempts: 3, InitialBackoff: time.Minute,
+		MaxBackoff: time.Hour})
+
+	webhooks, err := service.ListWebhooks(ctx)
+	require.NoError(t, err)
+	require.Len(t, webhooks, 1)
+	assert.Empty(t, webhooks[0].Secret, "secrets are not listed")
+
+	inactive := false
+	updated, err := service.UpdateWebhook(ctx, webhooks[0].ID, models.WebhookInput{Active: &inactive})
+	require.NoError(t, err)
+	assert.False(t, updated.Active)
+
+	count, err := service.Enqueue(ctx, testEvent())
+	require.NoError(t, err)
+	assert.Zero(t, count)
+	assert.Zero(t, rc.calls())
+
+	_, err = service.UpdateWebhook(ctx, 999, models.WebhookInput{Active: &inactive})
+	require.ErrorIs(t, err, ErrWebhookNotFound)
+	_, err = service.ListDeliveries(ctx, DeliveryFilter{WebhookID: 999})
+	require.ErrorIs(t, err, ErrWebhookNotFound)
+}
+
+func TestServiceGeneratesSecret(t *testing.T) {
+	service := NewService(NewMemoryStore(), models.WebhookConfig{})
+
+	webhook, err := service.CreateWebhook(context.Background(), models.Webhook{URL: "https://example.com/hook"})
+	require.NoError(t, err)
+	assert.Len(t, webhook.Secret, 2*secretBytes)
+
+	fetched, err := service.GetWebhook(context.Background(), webhook.ID)
+	require.NoError(t, err)
+	assert.Empty(t, fetched.Secret)
+}
+
+func TestCheckURL(t *testing.T) {
+	service := NewService(NewMemoryStore(), models.WebhookConfig{})
+
+	tests := []struct {
+		url       string
+		valid     bool
+		forbidden bool
+	}{
+		{url: "https://example.com/hook", valid: true},
+		{url: "http://203.0.113.10:8080/hook", valid: true},
+		{url: "/hook"},
+		{url: "ftp://example.com/hook"},
+		{url: "http://127.0.0.1/hook", forbidden: true},
+		{url: "http://[::1]/hook", forbidden: true},
+		{url: "http://localhost:8080/hook", forbidden: true},
+		{url: "http://api.localhost/hook", forbidden: true},
+		{url: "http://169.254.169.254/latest/meta-data", forbidden: true},
+		{url: "http://10.0.0.1/hook", forbidden: true},
+		{url: "http://192.168.1.1/hook", forbidden: true},
+		{url: "http://100.64.0.1/hook", forbidden: true},
+		{url: "http://0.0.0.0/hook", forbidden: true},
+		{url: "http://[::ffff:127.0.0.1]/hook", forbidden: true},
+		{url: "http://[fe80::1]/hook", forbidden: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			err := service.CheckURL(tt.url)
+			if tt.valid {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Equal(t, tt.forbidden, errors.Is(err, ErrForbiddenAddress), err.Error())
+		})
+	}
+
+	allowing := NewService(NewMemoryStore(), models.WebhookConfig{AllowPrivateNetworks: true})
+	require.NoError(t, allowing.CheckURL("http://127.0.0.1:8080/hook"))
+	require.Error(t, allowing.CheckURL("/hook"))
+}
+
+func TestServiceRefusesPrivateAddresses(t *testing.T) {
+	ctx := context.Background()
+	rc := &receiver{t: t, secret: "s3cret"}
+	server := httptest.NewServer(rc)
+	t.Cleanup(server.Close)
+
+	// The loopback address of the receiver stands for a public host name resolving to a private address.
+	service := NewService(NewMemoryStore(), models.WebhookConfig{Timeout: 5 * time.Second, MaxAttempts: 1})
+	_, err := service.CreateWebhook(ctx, models.Webhook{URL: server.URL, Secret: rc.secret, Active: true})
+	require.NoError(t, err)
+
+	_, err = service.Enqueue(ctx, testEvent())
+	require.NoError(t, err)
+	_, err = service.ProcessDue(ctx)
+	require.NoError(t, err)
+
+	deliveries, err := service.ListDeliveries(ctx, DeliveryFilter{})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, models.DeliveryDead, deliveries[0].Status)
+	assert.Contains(t, deliveries[0].LastError, ErrForbiddenAddress.Error())
+	assert.Zero(t, rc.calls())
+}
+
+func TestServiceDoesNotFollowRedirects(t *testing.T) {
+	ctx := context.Background()
+	rc := &receiver{t: t, secret: "s3cret"}
+	target := httptest.NewServer(rc)
+	t.Cleanup(target.Close)
+	redirect := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusTemporaryRedirect))
+	t.Cleanup(redirect.Close)
+
+	service := NewService(NewMemoryStore(), models.WebhookConfig{Timeout: 5 * time.Second, MaxAttempts: 1,
+		AllowPrivateNetworks: true})
+	_, err := service.CreateWebhook(ctx, models.Webhook{URL: redirect.URL, Secret: rc.secret, Active: true})
+	require.NoError(t, err)
+
+	_, err = service.Enqueue(ctx, testEvent())
+	require.NoError(t, err)
+	_, err = service.ProcessDue(ctx)
+	require.NoError(t, err)
+
+	deliveries, err := service.ListDeliveries(ctx, DeliveryFilter{})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, models.DeliveryDead, deliveries[0].Status)
+	assert.Equal(t, http.StatusTemporaryRedirect, deliveries[0].LastStatusCode)
+	assert.Zero(t, rc.calls())
+}
+
+func TestBackoff(t *testing.T) {
+	service := NewService(NewMemoryStore(), models.WebhookConfig{
+		InitialBackoff: 30 * time.Second,
+		MaxBackoff:     10 * time.Minute,
+	})
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: 30 * time.Second},
+		{attempts: 2, want: time.Minute},
+		{attempts: 3, want: 2 * time.Minute},
+		{attempts: 5, want: 8 * time.Minute},
+		{attempts: 6, want: 10 * time.Minute},
+		{attempts: 60, want: 10 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.attempts), func(t *testing.T) {
+			assert.Equal(t, tt.want, service.backoff(tt.attempts))
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+const (
+	// SignatureHeader carries the HMAC-SHA256 signature of a delivery as "sha256=<hex>".
+	SignatureHeader = "X-Webhook-Signature"
+	// TimestampHeader carries the Unix time at which a delivery was signed.
+	TimestampHeader = "X-Webhook-Timestamp"
+	// EventHeader carries the event of a delivery.
+	EventHeader = "X-Webhook-Event"
+	// DeliveryHeader carries the ID of a delivery, which is the same for all its attempts.
+	DeliveryHeader = "X-Webhook-Delivery"
+
+	signaturePrefix = "sha256="
+)
+
+// Sign returns the signature of a delivery: the hex encoded HMAC-SHA256, keyed with the webhook secret,
+// of the timestamp, a dot and the body. Signing the timestamp lets receivers reject replayed deliveries.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether the signature header of a delivery matches its timestamp header and body.
+func Verify(secret, timestamp, signature string, body []byte) bool {
+	unix, err := strconv.ParseInt(strings.TrimSpace(timestamp), 10, 64)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal([]byte(Sign(secret, unix, body)), []byte(strings.TrimSpace(signature)))
+}
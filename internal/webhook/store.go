@@ -0,0 +1,77 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/light-bringer/rates-exchanger-service/models"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrWebhookNotFound is returned if no webhook has the requested ID.
+	ErrWebhookNotFound = errors.New("webhook not found")
+	// ErrDeliveryNotFound is returned if no delivery has the requested ID.
+	ErrDeliveryNotFound = errors.New("delivery not found")
+	// ErrDeliveryNotDead is returned when retrying a delivery that is not a dead letter.
+	ErrDeliveryNotDead = errors.New("delivery is not a dead letter")
+)
+
+// Store persists the webhooks and their deliveries.
+// PostgresStore is used by the service; MemoryStore serves tests and local experiments.
+type Store interface {
+	// CreateWebhook stores a new webhook and returns it with its ID and timestamps.
+	CreateWebhook(ctx context.Context, webhook models.Webhook) (models.Webhook, error)
+	// ListWebhooks returns all webhooks ordered by ID, without their secrets.
+	ListWebhooks(ctx context.Context) ([]models.Webhook, error)
+	// GetWebhook returns a webhook, including its secret, or ErrWebhookNotFound.
+	GetWebhook(ctx context.Context, id int64) (models.Webhook, error)
+	// UpdateWebhook replaces the URL, description, secret and state of a webhook.
+	UpdateWebhook(ctx context.Context, webhook models.Webhook) (models.Webhook, error)
+	// DeleteWebhook deletes a webhook together with its deliveries.
+	DeleteWebhook(ctx context.Context, id int64) error
+
+	// EnqueueDeliveries creates a pending delivery of the payload for every active webhook,
+	// due at the given time, and returns the number of deliveries created.
+	EnqueueDeliveries(ctx context.Context, event string, sequence int64, payload []byte, due time.Time) (int, error)
+	// ClaimDueDeliveries returns up to limit pending deliveries of active webhooks that are due at now,
+	// postponing them to leaseUntil so that no other worker attempts them meanwhile.
+	ClaimDueDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]Job, error)
+	// RecordAttempt stores the outcome of a delivery attempt and increments the attempts of the delivery.
+	RecordAttempt(ctx context.Context, id int64, attempt Attempt) error
+	// ListDeliveries returns the deliveries matching the filter, newest first.
+	ListDeliveries(ctx context.Context, filter DeliveryFilter) ([]models.WebhookDelivery, error)
+	// RequeueDelivery makes a dead letter pending again with no attempts, due at the given time.
+	RequeueDelivery(ctx context.Context, id int64, due time.Time) (models.WebhookDelivery, error)
+}
+
+// Job is a claimed delivery together with the target and secret of its webhook.
+type Job struct {
+	Delivery models.WebhookDelivery
+	URL      string
+	Secret   string
+}
+
+// Attempt is the outcome of a delivery attempt.
+type Attempt struct {
+	// Status is the status of the delivery after the attempt.
+	Status models.DeliveryStatus
+	// StatusCode is the HTTP status returned by the webhook, or 0 if no response was received.
+	StatusCode int
+	// Error describes a failed attempt.
+	Error string
+	// At is the time of the attempt.
+	At time.Time
+	// NextAttemptAt is set for pending deliveries.
+	NextAttemptAt *time.Time
+}
+
+// DeliveryFilter selects deliveries.
+type DeliveryFilter struct {
+	// WebhookID restricts the deliveries to a webhook, if not 0.
+	WebhookID int64
+	// Status restricts the deliveries to a status, if not empty.
+	Status models.DeliveryStatus
+	// Limit bounds the number of deliveries, if not 0.
+	Limit uint64
+}
@@ -0,0 +1,133 @@
+package models
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// AlertCondition is the condition of an alert rule, evaluated on the latest two publication days.
+type AlertCondition string
+
+const (
+	// AlertAbove triggers while the rate is above the threshold.
+	AlertAbove AlertCondition = "above"
+	// AlertBelow triggers while the rate is below the threshold.
+	AlertBelow AlertCondition = "below"
+	// AlertCrossesAbove triggers when the rate rises above the threshold from the previous day.
+	AlertCrossesAbove AlertCondition = "crosses_above"
+	// AlertCrossesBelow triggers when the rate falls below the threshold from the previous day.
+	AlertCrossesBelow AlertCondition = "crosses_below"
+	// AlertChangePercent triggers when the rate moves by more than the threshold in percent, in either direction,
+	// from the previous day.
+	AlertChangePercent AlertCondition = "change_percent"
+)
+
+// AlertRule notifies when the rate of a currency, quoted against a base currency, meets a condition.
+type AlertRule struct {
+	ID        int64          `json:"id"`
+	Currency  string         `json:"currency"`
+	Base      string         `json:"base"`
+	Condition AlertCondition `json:"condition"`
+	Threshold float64        `json:"threshold"`
+	// CooldownSeconds is the minimum time between two notifications of the rule.
+	CooldownSeconds int64      `json:"cooldown_seconds"`
+	Active          bool       `json:"active"`
+	LastTriggeredAt *time.Time `json:"last_triggered_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// AlertRuleInput is the body of the requests creating or updating an alert rule.
+// Fields left out are not changed by updates; on creation, currency, condition and threshold are required.
+type AlertRuleInput struct {
+	Currency        *string         `json:"currency"`
+	Base            *string         `json:"base"`
+	Condition       *AlertCondition `json:"condition"`
+	Threshold       *float64        `json:"threshold"`
+	CooldownSeconds *int64          `json:"cooldown_seconds"`
+	Active          *bool           `json:"active"`
+}
+
+// Alert is a triggered alert rule, as sent to the notifiers.
+type Alert struct {
+	Rule          AlertRule `json:"rule"`
+	Date          string    `json:"date"`
+	Rate          float64   `json:"rate"`
+	PreviousDate  string    `json:"previous_date,omitempty"`
+	PreviousRate  float64   `json:"previous_rate,omitempty"`
+	ChangePercent float64   `json:"change_percent,omitempty"`
+	TriggeredAt   time.Time `json:"triggered_at"`
+}
+
+// Subject summarizes the alert in a single line.
+func (a Alert) Subject() string {
+	pair := a.Rule.Base + "/" + a.Rule.Currency
+	switch a.Rule.Condition {
+	case AlertAbove:
+		return fmt.Sprintf("%s is above %g at %g", pair, a.Rule.Threshold, a.Rate)
+	case AlertBelow:
+		return fmt.Sprintf("%s is below %g at %g", pair, a.Rule.Threshold, a.Rate)
+	case AlertCrossesAbove:
+		return fmt.Sprintf("%s crossed above %g to %g", pair, a.Rule.Threshold, a.Rate)
+	case AlertCrossesBelow:
+		return fmt.Sprintf("%s crossed below %g to %g", pair, a.Rule.Threshold, a.Rate)
+	case AlertChangePercent:
+		return fmt.Sprintf("%s moved %+.2f%% to %g", pair, a.ChangePercent, a.Rate)
+	default:
+		return fmt.Sprintf("%s alert at %g", pair, a.Rate)
+	}
+}
+
+// Body describes the alert with the rates it was evaluated on.
+func (a Alert) Body() string {
+	body := fmt.Sprintf("%s\n\nRule: #%d, %s %g\nRate on %s: %g\n",
+		a.Subject(), a.Rule.ID, a.Rule.Condition, a.Rule.Threshold, a.Date, a.Rate)
+	if a.PreviousDate != "" {
+		body += fmt.Sprintf("Rate on %s: %g\nChange: %+.4f%%\n", a.PreviousDate, a.PreviousRate, a.ChangePercent)
+	}
+	return body
+}
+
+// NotifierType selects an alert notifier.
+type NotifierType string
+
+const (
+	// NotifierLog writes alerts to the service log.
+	NotifierLog NotifierType = "log"
+	// NotifierSMTP sends alerts by email.
+	NotifierSMTP NotifierType = "smtp"
+)
+
+// AlertConfig contains the settings of the alert rules.
+type AlertConfig struct {
+	// Enabled evaluates the alert rules after each sync that changes the rates of the latest publication day,
+	// and serves the alert rule endpoints.
+	Enabled bool `yaml:"enabled"`
+	// Notifiers are the notifiers every alert is sent through.
+	Notifiers []NotifierType `yaml:"notifiers"`
+	SMTP      SMTPConfig     `yaml:"smtp"`
+}
+
+// SMTPConfig contains the settings of the SMTP notifier.
+type SMTPConfig struct {
+	Host string `yaml:"host"`
+	Port int    `yaml:"port"`
+	// Username and Password enable PLAIN authentication, which net/smtp only uses over TLS or to localhost.
+	Username string   `yaml:"username"`
+	Password string   `yaml:"password"`
+	From     string   `yaml:"from"`
+	To       []string `yaml:"to"`
+}
+
+// LogValue implements slog.LogValuer and returns the settings with the password redacted.
+func (c SMTPConfig) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("host", c.Host),
+		slog.Int("port", c.Port),
+		slog.String("username", c.Username),
+		slog.String("password", redact(c.Password)),
+		slog.String("from", c.From),
+		slog.Any("to", c.To),
+	)
+}
@@ -0,0 +1,82 @@
+package models
+
+// IndicatorType selects the technical indicator computed over a rate series.
+type IndicatorType string
+
+const (
+	// IndicatorSMA is the simple moving average of the window.
+	IndicatorSMA IndicatorType = "sma"
+	// IndicatorEMA is the exponential moving average with a smoothing factor of 2 / (window + 1).
+	IndicatorEMA IndicatorType = "ema"
+	// IndicatorMin is the lowest rate of the window.
+	IndicatorMin IndicatorType = "min"
+	// IndicatorMax is the highest rate of the window.
+	IndicatorMax IndicatorType = "max"
+	// IndicatorBollinger is the simple moving average with bands at K standard deviations above and below it.
+	IndicatorBollinger IndicatorType = "bollinger"
+)
+
+// IndicatorParams configures the computation of a technical indicator.
+type IndicatorParams struct {
+	Type IndicatorType
+	// Window is the number of publication days the indicator is computed over.
+	Window int
+	// K is the width of the Bollinger bands in standard deviations.
+	K float64
+}
+
+// IndicatorPoint is the value of an indicator on a publication day.
+// Upper and Lower are only set for Bollinger bands, whose Value is the middle band.
+type IndicatorPoint struct {
+	Date  string   `json:"date"`
+	Rate  float64  `json:"rate"`
+	Value float64  `json:"value"`
+	Upper *float64 `json:"upper,omitempty"`
+	Lower *float64 `json:"lower,omitempty"`
+}
+
+// Indicator holds a technical indicator of a currency, aligned to the publication days of its rates.
+type Indicator struct {
+	Currency string           `json:"currency"`
+	Base     string           `json:"base"`
+	Type     IndicatorType    `json:"type"`
+	Window   int              `json:"window"`
+	K        float64          `json:"k,omitempty"`
+	Series   []IndicatorPoint `json:"series"`
+}
+
+// Correlation holds the Pearson correlation of the daily log returns of each pair of currencies.
+// Matrix[i][j] and Observations[i][j] belong to the pair Symbols[i] and Symbols[j]. A correlation is nil
+// if the pair has fewer than two common returns or either series is constant.
+type Correlation struct {
+	Base         string       `json:"base"`
+	StartDate    string       `json:"start_date"`
+	EndDate      string       `json:"end_date"`
+	Symbols      []string     `json:"symbols"`
+	Matrix       [][]*float64 `json:"matrix"`
+	Observations [][]int      `json:"observations"`
+}
+
+// VolatilityPoint is the rolling volatility of a currency on a publication day.
+type VolatilityPoint struct {
+	Date       string  `json:"date"`
+	Volatility float64 `json:"volatility"`
+}
+
+// CurrencyVolatility holds the volatility of a currency over a whole period and its rolling series.
+// Volatility is nil if the period has fewer than two returns.
+type CurrencyVolatility struct {
+	Volatility   *float64          `json:"volatility"`
+	Observations int               `json:"observations"`
+	Series       []VolatilityPoint `json:"series"`
+}
+
+// Volatility holds the annualized historical volatility of the daily log returns of some currencies.
+type Volatility struct {
+	Base              string                        `json:"base"`
+	StartDate         string                        `json:"start_date"`
+	EndDate           string                        `json:"end_date"`
+	Window            int                           `json:"window"`
+	AnnualizationDays int                           `json:"annualization_days"`
+	Currencies        map[string]CurrencyVolatility `json:"currencies"`
+}
@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// APIKeyScope grants an API key access to a set of routes.
+type APIKeyScope string
+
+const (
+	// ScopeRatesRead grants access to the rate, conversion and currency routes.
+	ScopeRatesRead APIKeyScope = "rates:read"
+	// ScopeAdmin grants access to all routes, including the webhook, alert rule and metrics routes.
+	ScopeAdmin APIKeyScope = "admin"
+)
+
+// APIKey is a key clients authenticate with. Only the SHA-256 hash of the secret is stored;
+// the secret is shown once, when the key is created.
+type APIKey struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	// Prefix is the start of the secret, which identifies the key without revealing it.
+	Prefix string        `json:"prefix"`
+	Hash   string        `json:"-"`
+	Scopes []APIKeyScope `json:"scopes"`
+	// ExpiresAt is when the key stops being accepted; keys without it do not expire.
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// HasScope reports whether the key grants the scope. The admin scope grants every scope.
+func (k APIKey) HasScope(scope APIKeyScope) bool {
+	for _, granted := range k.Scopes {
+		if granted == scope || granted == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// IsActive reports whether the key is accepted at the given time, i.e. neither revoked nor expired.
+func (k APIKey) IsActive(at time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || at.Before(*k.ExpiresAt))
+}
+
+// AuthConfig contains the settings of the API key authentication.
+type AuthConfig struct {
+	// Enabled requires an API key on all routes but the anonymous ones.
+	Enabled bool `yaml:"enabled"`
+	// Anonymous are the path prefixes of the routes served without an API key, e.g. "/health".
+	// They also match the full names of the gRPC methods, e.g. "/rates.v1.RatesService/WatchRates".
+	Anonymous []string `yaml:"anonymous"`
+}
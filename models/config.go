@@ -1,6 +1,12 @@
 package models
 
-import "time"
+import (
+	"log/slog"
+	"time"
+)
+
+// redacted replaces secrets in logged configurations.
+const redacted = "[REDACTED]"
 
 type StartupConfig struct {
 	Database struct {
@@ -31,8 +37,92 @@ type StartupConfig struct {
 	HTTP struct {
 		Port int `yaml:"port"`
 	} `yaml:"http"`
+
+	API APIConfig `yaml:"api"`
+
+	GRPC GRPCConfig `yaml:"grpc"`
+
+	Webhooks WebhookConfig `yaml:"webhooks"`
+
+	Alerts AlertConfig `yaml:"alerts"`
+
+	Health HealthConfig `yaml:"health"`
+
+	RateLimit RateLimitConfig `yaml:"rate_limit"`
+
+	Auth AuthConfig `yaml:"auth"`
+}
+
+// LogValue implements slog.LogValuer and returns the configuration with the database password
+// and the SMTP password redacted, so that the configuration can be logged.
+func (c StartupConfig) LogValue() slog.Value {
+	// startupConfig has no LogValue method, which would be called again when the value is logged.
+	type startupConfig StartupConfig
+
+	c.Database.Pass = redact(c.Database.Pass)
+	c.Alerts.SMTP.Password = redact(c.Alerts.SMTP.Password)
+	return slog.AnyValue(startupConfig(c))
+}
+
+// redact hides a non-empty secret; an empty one is kept, to show that it is not set.
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return redacted
 }
 
+// GRPCConfig contains the settings of the gRPC API.
+type GRPCConfig struct {
+	// Enabled starts the gRPC server next to the HTTP server.
+	Enabled bool `yaml:"enabled"`
+	// Port is the port the gRPC server listens on.
+	Port int `yaml:"port"`
+}
+
+// APIConfig contains the settings that control the behaviour of the HTTP API.
+type APIConfig struct {
+	// MaxTimeSeriesDays is the maximum number of days a time series request may span.
+	MaxTimeSeriesDays int `yaml:"max_timeseries_days"`
+	// MaxBatchItems is the maximum number of items of a batch rate request.
+	MaxBatchItems int `yaml:"max_batch_items"`
+	// CSVDecimalSeparator is the default decimal separator of CSV output, either "." or ",".
+	// With "," the fields are separated by ";" instead of ",".
+	CSVDecimalSeparator string `yaml:"csv_decimal_separator"`
+	// CacheMaxAge is the Cache-Control max-age of rate responses that may change with the next sync.
+	CacheMaxAge time.Duration `yaml:"cache_max_age"`
+	// HistoricalCacheMaxAge is the Cache-Control max-age of rate responses that only cover days
+	// before the latest publication day, which no longer change.
+	HistoricalCacheMaxAge time.Duration `yaml:"historical_cache_max_age"`
+	// SpecValidation selects how requests and responses are validated against the OpenAPI specification.
+	SpecValidation ValidationMode `yaml:"spec_validation"`
+	// SpecValidationSampleRate is the fraction of JSON responses whose bodies are validated in log mode,
+	// between 0 and 1. The status of every response is validated. Strict mode validates all bodies.
+	SpecValidationSampleRate float64 `yaml:"spec_validation_sample_rate"`
+	// SpecValidationMaxBody is the size in bytes up to which a sampled JSON response body is recorded
+	// for validation in log mode; larger bodies are not validated.
+	SpecValidationMaxBody int `yaml:"spec_validation_max_body"`
+	// StreamHeartbeat is the interval of the heartbeat comments sent on idle rate streams.
+	StreamHeartbeat time.Duration `yaml:"stream_heartbeat"`
+	// StreamPollInterval is how often the rate streams, including the WatchRates streams of the gRPC API,
+	// check the stored rates for changes.
+	StreamPollInterval time.Duration `yaml:"stream_poll_interval"`
+	// SwaggerUI enables a Swagger UI page for the OpenAPI specification at /docs.
+	SwaggerUI bool `yaml:"swagger_ui"`
+}
+
+// ValidationMode selects how requests and responses are validated against the OpenAPI specification.
+type ValidationMode string
+
+const (
+	// ValidationOff disables the validation.
+	ValidationOff ValidationMode = "off"
+	// ValidationLog logs requests and responses that do not match the specification and serves them unchanged.
+	ValidationLog ValidationMode = "log"
+	// ValidationStrict rejects invalid requests with a 400 and replaces invalid responses with a 500.
+	ValidationStrict ValidationMode = "strict"
+)
+
 type SSLMode string
 
 const (
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStartupConfigLogValue(t *testing.T) {
+	var config StartupConfig
+	config.Database.User = "rates"
+	config.Database.Pass = "db-secret"
+	config.Alerts.SMTP = SMTPConfig{Host: "smtp.example.com", Username: "alerts", Password: "smtp-secret"}
+
+	handlers := map[string]func(*bytes.Buffer) slog.Handler{
+		"text": func(buf *bytes.Buffer) slog.Handler { return slog.NewTextHandler(buf, nil) },
+		"json": func(buf *bytes.Buffer) slog.Handler { return slog.NewJSONHandler(buf, nil) },
+	}
+	for name, newHandler := range handlers {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(newHandler(&buf))
+
+			logger.Info("Config", "config", config, "smtp", config.Alerts.SMTP)
+
+			assert.NotContains(t, buf.String(), "db-secret")
+			assert.NotContains(t, buf.String(), "smtp-secret")
+			assert.Contains(t, buf.String(), redacted)
+			assert.Contains(t, buf.String(), "smtp.example.com")
+			assert.Contains(t, buf.String(), "rates")
+		})
+	}
+
+	// The logged copy is redacted, not the configuration itself.
+	assert.Equal(t, "db-secret", config.Database.Pass)
+	assert.Equal(t, "smtp-secret", config.Alerts.SMTP.Password)
+}
@@ -0,0 +1,17 @@
+package models
+
+// Currency is an ISO 4217 currency together with the range of dates rates are stored for.
+type Currency struct {
+	Code        string `json:"code"`
+	Name        string `json:"name"`
+	NumericCode string `json:"numeric_code"`
+	// MinorUnits is the number of decimals of the currency. It is not set for units such as gold or the SDR.
+	MinorUnits *int     `json:"minor_units,omitempty"`
+	Countries  []string `json:"countries"`
+	// Withdrawn currencies are no longer in use, but may still have historical rates.
+	Withdrawn bool `json:"withdrawn"`
+	// FirstDate and LastDate are the first and last publication days with rates of the currency.
+	// Both are empty if no rates are stored.
+	FirstDate string `json:"first_date,omitempty"`
+	LastDate  string `json:"last_date,omitempty"`
+}
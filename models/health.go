@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// HealthStatus is the outcome of a health check, or of all checks together.
+type HealthStatus string
+
+const (
+	// HealthOK reports a passing check, or a service whose checks all pass.
+	HealthOK HealthStatus = "ok"
+	// HealthFail reports a failing check.
+	HealthFail HealthStatus = "fail"
+	// HealthDegraded reports a service with at least one failing check.
+	HealthDegraded HealthStatus = "degraded"
+)
+
+// HealthCheck is the outcome of a single readiness check.
+type HealthCheck struct {
+	Status HealthStatus `json:"status"`
+	// Detail explains the outcome, e.g. the error of a failing check.
+	Detail string `json:"detail,omitempty"`
+}
+
+// Health is the outcome of the readiness checks, keyed by check name.
+type Health struct {
+	Status HealthStatus           `json:"status"`
+	Checks map[string]HealthCheck `json:"checks"`
+}
+
+// SyncStatus describes the outcome of the syncs run so far.
+type SyncStatus struct {
+	// LastRun is when the last sync finished, successfully or not. It is zero before the first sync finished.
+	LastRun time.Time
+	// LastSuccess is when the last successful sync finished.
+	LastSuccess time.Time
+	// LastError is the error of the last sync, if it failed.
+	LastError string
+}
+
+// HealthConfig contains the settings of the readiness checks.
+type HealthConfig struct {
+	// PublicationCutoff is the time of day, in UTC, after which the rates of a TARGET business day
+	// are expected to be stored.
+	PublicationCutoff time.Duration `yaml:"publication_cutoff"`
+	// MaxLagDays is the number of business days the latest stored rates may be behind the expected
+	// publication day before the service is reported as degraded.
+	MaxLagDays int `yaml:"max_lag_days"`
+	// Timeout bounds the database checks.
+	Timeout time.Duration `yaml:"timeout"`
+}
@@ -1,3 +1,76 @@
 package models
 
 type RateStatisticsMap map[string]RateStatistic
+
+// RoundingMode selects how converted amounts are rounded to the currency's minor units.
+type RoundingMode string
+
+const (
+	RoundHalfEven RoundingMode = "half-even"
+	RoundHalfUp   RoundingMode = "half-up"
+)
+
+// Rounding describes the rounding applied to a converted amount.
+type Rounding struct {
+	Mode     RoundingMode `json:"mode"`
+	Decimals int          `json:"decimals"`
+}
+
+// Conversion is the result of converting an amount between two currencies.
+type Conversion struct {
+	From     string   `json:"from"`
+	To       string   `json:"to"`
+	Amount   float64  `json:"amount"`
+	Result   float64  `json:"result"`
+	Rate     float64  `json:"rate"`
+	Date     string   `json:"date"`
+	Rounding Rounding `json:"rounding"`
+}
+
+// TimeSeries maps a publication date (YYYY-MM-DD) to the rates published on that day, keyed by currency.
+type TimeSeries map[string]map[string]float64
+
+// Fallback selects which publication day is used when no rates were published on a requested date.
+type Fallback string
+
+const (
+	// FallbackPrevious uses the closest earlier publication day.
+	FallbackPrevious Fallback = "previous"
+	// FallbackNext uses the closest later publication day.
+	FallbackNext Fallback = "next"
+	// FallbackNone only uses the requested date itself.
+	FallbackNone Fallback = "none"
+)
+
+// ErrorCode is a stable, machine readable identifier of an API error.
+type ErrorCode string
+
+const (
+	ErrorCodeInvalidParameter ErrorCode = "invalid_parameter"
+	ErrorCodeUnknownCurrency  ErrorCode = "unknown_currency"
+	ErrorCodeRangeTooLarge    ErrorCode = "range_too_large"
+	ErrorCodeTooManyItems     ErrorCode = "too_many_items"
+	ErrorCodeRatesNotFound    ErrorCode = "rates_not_found"
+	ErrorCodeNotFound         ErrorCode = "not_found"
+	ErrorCodeMethodNotAllowed ErrorCode = "method_not_allowed"
+	ErrorCodeNotAcceptable    ErrorCode = "not_acceptable"
+	ErrorCodeInvalidBody      ErrorCode = "invalid_body"
+	ErrorCodeConflict         ErrorCode = "conflict"
+	ErrorCodeRateLimited      ErrorCode = "rate_limited"
+	ErrorCodeUnauthorized     ErrorCode = "unauthorized"
+	ErrorCodeForbidden        ErrorCode = "forbidden"
+	ErrorCodeInternal         ErrorCode = "internal_error"
+)
+
+// Problem is an RFC 7807 problem details response, extended with a stable error code,
+// the offending parameter and the request ID.
+type Problem struct {
+	Type      string    `json:"type"`
+	Title     string    `json:"title"`
+	Status    int       `json:"status"`
+	Detail    string    `json:"detail,omitempty"`
+	Instance  string    `json:"instance,omitempty"`
+	Code      ErrorCode `json:"code"`
+	Param     string    `json:"param,omitempty"`
+	RequestID string    `json:"request_id,omitempty"`
+}
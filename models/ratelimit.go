@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// RateLimitStoreType selects where the token buckets of the rate limiter are kept.
+type RateLimitStoreType string
+
+const (
+	// RateLimitStoreMemory keeps the buckets in the memory of each instance.
+	RateLimitStoreMemory RateLimitStoreType = "memory"
+	// RateLimitStorePostgres keeps the buckets in Postgres, shared by all instances.
+	RateLimitStorePostgres RateLimitStoreType = "postgres"
+)
+
+// RateLimitConfig contains the settings of the rate limiter.
+type RateLimitConfig struct {
+	// Enabled limits the requests of each client to the policies of the groups.
+	Enabled bool `yaml:"enabled"`
+	// Store selects where the token buckets are kept.
+	Store RateLimitStoreType `yaml:"store"`
+	// Groups are the route groups and their policies. A request belongs to the group with the
+	// longest path prefix matching its path; requests matching no group are not limited.
+	Groups []RateLimitGroup `yaml:"groups"`
+}
+
+// RateLimitGroup is a group of routes sharing a rate limit policy.
+// Each client has its own token bucket per group.
+type RateLimitGroup struct {
+	// Name identifies the group in the buckets and the RateLimit-Policy header.
+	Name string `yaml:"name"`
+	// Paths are the path prefixes of the routes of the group, e.g. "/rates".
+	Paths []string `yaml:"paths"`
+	// Requests is the number of requests allowed per period. Zero disables the limit of the group.
+	Requests int `yaml:"requests"`
+	// Period is the period the requests are refilled over.
+	Period time.Duration `yaml:"period"`
+	// Burst is the number of requests that can be made at once, the capacity of the bucket.
+	// It defaults to Requests.
+	Burst int `yaml:"burst"`
+}
@@ -1,18 +1,171 @@
 package models
 
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Percent converts ratios, such as the change of a rate relative to an earlier rate, into percentages.
+const Percent = 100
+
 type LatestExchangeRate struct {
 	Currency string  `json:"currency"`
 	Rate     float64 `json:"rate"`
 }
 
 type RateStatistic struct {
-	Currency string  `json:"currency"`
-	MinRate  float64 `json:"min_rate"`
-	MaxRate  float64 `json:"max_rate"`
-	AvgRate  float64 `json:"avg_rate"`
+	Currency      string  `json:"currency"`
+	MinRate       float64 `json:"min_rate"`
+	MaxRate       float64 `json:"max_rate"`
+	AvgRate       float64 `json:"avg_rate"`
+	MedianRate    float64 `json:"median_rate"`
+	StdDev        float64 `json:"std_dev"`
+	Count         int     `json:"count"`
+	FirstRate     float64 `json:"first_rate"`
+	LastRate      float64 `json:"last_rate"`
+	Change        float64 `json:"change"`
+	ChangePercent float64 `json:"change_percent"`
+	FirstDate     string  `json:"first_date"`
+	LastDate      string  `json:"last_date"`
+	MinDate       string  `json:"min_date"`
+	MaxDate       string  `json:"max_date"`
 }
 
 type (
 	LatestExchangeRates []LatestExchangeRate
 	RateStatistics      []RateStatistic
 )
+
+// Fluctuation describes how the rate of a currency changed between two publication days.
+type Fluctuation struct {
+	StartRate     float64 `json:"start_rate"`
+	EndRate       float64 `json:"end_rate"`
+	Change        float64 `json:"change"`
+	ChangePercent float64 `json:"change_percent"`
+}
+
+// Fluctuations holds the fluctuation of each currency between two resolved publication days.
+type Fluctuations struct {
+	StartDate string                 `json:"start_date"`
+	EndDate   string                 `json:"end_date"`
+	Rates     map[string]Fluctuation `json:"rates"`
+}
+
+// BatchRateItem is one date of a batch rate lookup, optionally restricted to some currencies.
+type BatchRateItem struct {
+	Date    string   `json:"date"`
+	Symbols []string `json:"symbols,omitempty"`
+}
+
+// BatchRateRequest looks up the rates of many dates at once.
+// Base and Fallback apply to all items and default to EUR and the previous publication day.
+type BatchRateRequest struct {
+	Base     string          `json:"base,omitempty"`
+	Fallback Fallback        `json:"fallback,omitempty"`
+	Items    []BatchRateItem `json:"items"`
+}
+
+// BatchRateResult holds the rates of one item of a batch rate lookup.
+// Date is empty and Rates is empty if no usable publication day exists for the requested date.
+type BatchRateResult struct {
+	RequestedDate string              `json:"requested_date"`
+	Date          string              `json:"date,omitempty"`
+	Rates         LatestExchangeRates `json:"rates"`
+}
+
+// DataVersion identifies the state of the stored exchange rates.
+// It changes whenever a sync or cleanup inserts, changes or deletes rates.
+type DataVersion struct {
+	// LatestDay is the latest publication day, or empty if no rates are stored.
+	LatestDay string
+	// Sequence is incremented by every sync or cleanup that changes the stored rates.
+	Sequence int64
+	// ModifiedAt is the time the stored rates last changed.
+	ModifiedAt time.Time
+}
+
+// MarshalJSON encodes the rates as an object keyed by currency, keeping the order of the slice.
+func (r LatestExchangeRates) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, rate := range r {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(rate.Currency)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to encode currency")
+		}
+		value, err := json.Marshal(rate.Rate)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to encode rate")
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON decodes rates encoded as an object keyed by currency, keeping the order of the object.
+func (r *LatestExchangeRates) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if _, err := decoder.Token(); err != nil {
+		return errors.Wrap(err, "failed to decode rates")
+	}
+
+	rates := make(LatestExchangeRates, 0)
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return errors.Wrap(err, "failed to decode currency")
+		}
+		currency, _ := token.(string)
+
+		var rate float64
+		if err = decoder.Decode(&rate); err != nil {
+			return errors.Wrapf(err, "failed to decode rate of %s", currency)
+		}
+		rates = append(rates, LatestExchangeRate{Currency: currency, Rate: rate})
+	}
+
+	*r = rates
+	return nil
+}
+
+// SyncEvent describes the rates inserted or changed by a sync.
+type SyncEvent struct {
+	// Sequence is the sync sequence the sync produced.
+	Sequence int64 `json:"sequence"`
+	// Date is the latest day with inserted or changed rates.
+	Date string `json:"date"`
+	// Currencies are the currencies with inserted or changed rates, in ascending order.
+	Currencies []string `json:"currencies"`
+	// Days are the days with inserted or changed rates, in ascending order.
+	Days []string `json:"days"`
+}
+
+// Job names a background job run by the sync service.
+type Job string
+
+const (
+	// JobSync loads the published rates and stores the new and changed ones.
+	JobSync Job = "sync"
+	// JobCleanup deletes the rates older than the configured age.
+	JobCleanup Job = "cleanup"
+)
+
+// JobRun describes a finished run of a background job.
+type JobRun struct {
+	Job      Job
+	Started  time.Time
+	Duration time.Duration
+	// Rows is the number of rates a sync inserted or changed, or a cleanup deleted.
+	Rows int64
+	// Err is the error the run failed with, if any.
+	Err error
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatestExchangeRatesJSON(t *testing.T) {
+	rates := LatestExchangeRates{
+		{Currency: "USD", Rate: 1.0811},
+		{Currency: "AUD", Rate: 1.5339},
+	}
+
+	data, err := json.Marshal(rates)
+	require.NoError(t, err)
+	assert.Equal(t, `{"USD":1.0811,"AUD":1.5339}`, string(data))
+
+	var decoded LatestExchangeRates
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, rates, decoded)
+
+	data, err = json.Marshal(LatestExchangeRates{})
+	require.NoError(t, err)
+	assert.Equal(t, `{}`, string(data))
+}
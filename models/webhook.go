@@ -0,0 +1,84 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WebhookEventRatesSynced is the event delivered after a sync inserted or changed rates.
+const WebhookEventRatesSynced = "rates.synced"
+
+// Webhook is a subscription to the events of the API.
+type Webhook struct {
+	ID          int64  `json:"id"`
+	URL         string `json:"url"`
+	Description string `json:"description"`
+	Active      bool   `json:"active"`
+	// Secret signs the deliveries. It is only returned when the webhook is created.
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// DeliveryStatus is the state of a webhook delivery.
+type DeliveryStatus string
+
+const (
+	// DeliveryPending deliveries wait for their next attempt.
+	DeliveryPending DeliveryStatus = "pending"
+	// DeliveryDelivered deliveries were acknowledged with a 2xx response.
+	DeliveryDelivered DeliveryStatus = "delivered"
+	// DeliveryDead deliveries exhausted their attempts; they are listed as dead letters.
+	DeliveryDead DeliveryStatus = "dead"
+)
+
+// WebhookDelivery is the delivery of an event to a webhook, with the outcome of its last attempt.
+type WebhookDelivery struct {
+	ID             int64           `json:"id"`
+	WebhookID      int64           `json:"webhook_id"`
+	Event          string          `json:"event"`
+	Sequence       int64           `json:"sequence"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         DeliveryStatus  `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+	LastStatusCode int             `json:"last_status_code,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+}
+
+// WebhookPayload is the body POSTed to the webhooks.
+type WebhookPayload struct {
+	Event string `json:"event"`
+	SyncEvent
+}
+
+// WebhookConfig contains the settings of the webhook deliveries.
+type WebhookConfig struct {
+	// Enabled starts the delivery worker and notifies the webhooks after each sync.
+	Enabled bool `yaml:"enabled"`
+	// PollInterval is how often due deliveries are attempted.
+	PollInterval time.Duration `yaml:"poll_interval"`
+	// Timeout bounds a single delivery attempt.
+	Timeout time.Duration `yaml:"timeout"`
+	// MaxAttempts is the number of attempts after which a delivery becomes a dead letter.
+	MaxAttempts int `yaml:"max_attempts"`
+	// InitialBackoff is the delay before the first retry; it doubles with every further attempt.
+	InitialBackoff time.Duration `yaml:"initial_backoff"`
+	// MaxBackoff caps the delay between two attempts.
+	MaxBackoff time.Duration `yaml:"max_backoff"`
+	// AllowPrivateNetworks allows webhooks on loopback, private and link-local addresses, e.g. receivers
+	// running next to the service. Otherwise such URLs are rejected, and so are host names resolving to them.
+	AllowPrivateNetworks bool `yaml:"allow_private_networks"`
+}
+
+// WebhookInput is the body of the requests creating or updating a webhook.
+// Fields left out are not changed by updates; on creation, url is required.
+type WebhookInput struct {
+	URL         *string `json:"url"`
+	Description *string `json:"description"`
+	// Secret replaces the signing secret; a secret is generated on creation if none is given.
+	Secret *string `json:"secret"`
+	Active *bool   `json:"active"`
+}